@@ -0,0 +1,170 @@
+package glogger
+
+import (
+	"container/list"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	abuseKey         = "abuse"
+	maxAbuseCounters = 10000
+)
+
+// Abuse signals of the detectors provided by glogger.
+const (
+	AbuseSignalRate             = "rate"
+	AbuseSignalSuspiciousPath   = "suspiciousPath"
+	AbuseSignalOversizedHeaders = "oversizedHeaders"
+)
+
+// DefaultSuspiciousPaths are the path patterns commonly probed by vulnerability scanners.
+var DefaultSuspiciousPaths = []string{
+	"/wp-admin",
+	"/wp-login.php",
+	"/xmlrpc.php",
+	"/phpmyadmin",
+	"/.env",
+	"/.git",
+	"/cgi-bin",
+	"*.php",
+}
+
+// Abuse struct contains items of abuse info log.
+type Abuse struct {
+	Signals []string `json:"signals,omitempty"`
+}
+
+// AbuseDetector returns the abuse signal of the request, such as AbuseSignalRate, or an
+// empty string. The IP is the client IP resolved through the TrustedProxies of the
+// middleware or, without trusted proxies, the IP of the peer, so that the clients cannot
+// choose it with the forwarding headers.
+type AbuseDetector func(r *http.Request, ip string) string
+
+// AbuseFunc is called with the abuse signals of a request, e.g. to ban the client IP.
+type AbuseFunc func(r *http.Request, ip string, signals []string)
+
+type rateCounter struct {
+	ip    string
+	start time.Time
+	count int
+}
+
+// RateDetector returns an AbuseDetector signaling the requests of a client IP above the
+// limit of requests per window. Beyond 10000 client IPs, the counters of the oldest
+// windows are evicted.
+func RateDetector(limit int, window time.Duration) AbuseDetector {
+	return newRateDetector(limit, window, time.Now)
+}
+
+func newRateDetector(limit int, window time.Duration, now func() time.Time) AbuseDetector {
+	var mutex sync.Mutex
+	counters := map[string]*list.Element{}
+	// The counters are ordered by the start of their window, the oldest first.
+	windows := list.New()
+
+	return func(r *http.Request, ip string) string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		current := now()
+		element, ok := counters[ip]
+
+		if !ok {
+			if len(counters) >= maxAbuseCounters {
+				oldest := windows.Front()
+				delete(counters, windows.Remove(oldest).(*rateCounter).ip)
+			}
+
+			element = windows.PushBack(&rateCounter{ip: ip, start: current})
+			counters[ip] = element
+		}
+
+		counter := element.Value.(*rateCounter)
+
+		if current.Sub(counter.start) >= window {
+			counter.start, counter.count = current, 0
+			windows.MoveToBack(element)
+		}
+
+		counter.count++
+
+		if counter.count > limit {
+			return AbuseSignalRate
+		}
+
+		return ""
+	}
+}
+
+// SuspiciousPathDetector returns an AbuseDetector signaling the requests whose path, or
+// one of its parent paths, matches one of the patterns (as in path.Match), such as
+// DefaultSuspiciousPaths. Patterns without a slash, such as "*.php", match any segment
+// of the path. Patterns are case-insensitive.
+func SuspiciousPathDetector(patterns ...string) AbuseDetector {
+	lowered := make([]string, len(patterns))
+
+	for i, pattern := range patterns {
+		lowered[i] = strings.ToLower(pattern)
+	}
+
+	return func(r *http.Request, ip string) string {
+		requestPath := strings.ToLower(r.URL.Path)
+
+		for end := len(requestPath); end > 0; end = strings.LastIndex(requestPath[:end], "/") {
+			parent := requestPath[:end]
+			segment := parent[strings.LastIndex(parent, "/")+1:]
+
+			for _, pattern := range lowered {
+				name := parent
+
+				if !strings.Contains(pattern, "/") {
+					name = segment
+				}
+
+				if matched, _ := path.Match(pattern, name); matched {
+					return AbuseSignalSuspiciousPath
+				}
+			}
+		}
+
+		return ""
+	}
+}
+
+// HeaderSizeDetector returns an AbuseDetector signaling the requests whose headers, with
+// the request line, are larger than maxBytes.
+func HeaderSizeDetector(maxBytes int) AbuseDetector {
+	return func(r *http.Request, ip string) string {
+		// The separators of the request line and the headers are counted as on the wire.
+		size := len(r.Method) + len(r.RequestURI) + len(r.Proto) + 4
+
+		for name, values := range r.Header {
+			for _, value := range values {
+				size += len(name) + len(value) + 4
+			}
+		}
+
+		if size > maxBytes {
+			return AbuseSignalOversizedHeaders
+		}
+
+		return ""
+	}
+}
+
+// detectAbuse returns the signals of the detectors for the request.
+func detectAbuse(detectors []AbuseDetector, r *http.Request, ip string) []string {
+	var signals []string
+
+	for _, detector := range detectors {
+		if signal := detector(r, ip); signal != "" {
+			signals = append(signals, signal)
+		}
+	}
+
+	return signals
+}
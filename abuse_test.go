@@ -0,0 +1,120 @@
+package glogger
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestRateDetector(t *testing.T) {
+	now := time.Now()
+	detector := newRateDetector(2, time.Minute, func() time.Time { return now })
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	assert.Equal(t, detector(request, "192.0.2.1"), "")
+	assert.Equal(t, detector(request, "192.0.2.1"), "")
+	assert.Equal(t, detector(request, "192.0.2.1"), AbuseSignalRate)
+	assert.Equal(t, detector(request, "192.0.2.2"), "")
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, detector(request, "192.0.2.1"), "")
+
+	t.Run("The oldest counters are evicted", func(t *testing.T) {
+		detector := newRateDetector(1, time.Hour, func() time.Time { return now })
+
+		assert.Equal(t, detector(request, "192.0.2.1"), "")
+
+		for i := 0; i < maxAbuseCounters-1; i++ {
+			now = now.Add(time.Millisecond)
+			detector(request, fmt.Sprint(i))
+		}
+
+		now = now.Add(time.Millisecond)
+		assert.Equal(t, detector(request, "192.0.2.2"), "")
+		assert.Equal(t, detector(request, "192.0.2.2"), AbuseSignalRate)
+		assert.Equal(t, detector(request, "0"), AbuseSignalRate)
+		assert.Equal(t, detector(request, "192.0.2.1"), "")
+	})
+}
+
+func TestSuspiciousPathDetector(t *testing.T) {
+	detector := SuspiciousPathDetector(DefaultSuspiciousPaths...)
+
+	cases := map[string]string{
+		"/wp-admin":             AbuseSignalSuspiciousPath,
+		"/WP-Admin/install.php": AbuseSignalSuspiciousPath,
+		"/blog/.env":            "",
+		"/.git/config":          AbuseSignalSuspiciousPath,
+		"/index.php":            AbuseSignalSuspiciousPath,
+		"/api/v1/users":         "",
+	}
+
+	for requestPath, expected := range cases {
+		request := httptest.NewRequest(http.MethodGet, requestPath, nil)
+		assert.Equal(t, detector(request, ""), expected, "Unexpected signal for %s", requestPath)
+	}
+}
+
+func TestHeaderSizeDetector(t *testing.T) {
+	detector := HeaderSizeDetector(1024)
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	assert.Equal(t, detector(request, ""), "")
+
+	request.Header.Set("Cookie", strings.Repeat("a", 1024))
+	assert.Equal(t, detector(request, ""), AbuseSignalOversizedHeaders)
+}
+
+func TestAbuseDetectors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	var bannedIP string
+	var bannedSignals []string
+	options := MiddlewareOptions{
+		AbuseDetectors: []AbuseDetector{SuspiciousPathDetector(DefaultSuspiciousPaths...), HeaderSizeDetector(8192)},
+		OnAbuse: func(r *http.Request, ip string, signals []string) {
+			bannedIP, bannedSignals = ip, signals
+		},
+	}
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/wp-login.php", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, bannedIP, "192.0.2.1")
+	assert.DeepEqual(t, bannedSignals, []string{AbuseSignalSuspiciousPath})
+	assert.DeepEqual(t, hook.LastEntry().Data["abuse"], Abuse{Signals: []string{AbuseSignalSuspiciousPath}})
+
+	hook.Reset()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	_, ok := hook.LastEntry().Data["abuse"]
+	assert.Assert(t, !ok)
+
+	t.Run("The forwarding headers are ignored without trusted proxies", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/wp-login.php", nil)
+		request.Header.Set("X-Forwarded-For", "203.0.113.7")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Equal(t, bannedIP, "192.0.2.1")
+	})
+
+	t.Run("The forwarding headers of the trusted proxies are used", func(t *testing.T) {
+		options.TrustedProxies = TrustedProxies{netip.MustParsePrefix("192.0.2.0/24")}
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.NotFoundHandler())
+		request := httptest.NewRequest(http.MethodGet, "/wp-login.php", nil)
+		request.Header.Set("X-Forwarded-For", "203.0.113.7")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Equal(t, bannedIP, "203.0.113.7")
+	})
+}
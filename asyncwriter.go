@@ -0,0 +1,361 @@
+package glogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAsyncBufferSize = 1024
+	// asyncShrinkInterval is the period over which the high-water mark of the buffer is
+	// measured to shrink it.
+	asyncShrinkInterval = 10 * time.Second
+)
+
+// ErrWriterClosed is returned by the writes to a closed AsyncWriter.
+var ErrWriterClosed = errors.New("glogger: writer closed")
+
+// OverflowPolicy is the behaviour of an AsyncWriter when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the writes until the buffer has room, so that no entry is lost.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop drops the written entries, so that logging never blocks.
+	OverflowDrop
+)
+
+// AsyncWriterOptions is the struct of options to configure an AsyncWriter.
+type AsyncWriterOptions struct {
+	// BufferSize is the maximum number of entries buffered. Defaults to 1024.
+	BufferSize int
+	// MaxBufferSize, when greater than BufferSize, lets the buffer grow up to
+	// MaxBufferSize entries during the bursts before the overflow policy applies. The
+	// buffer shrinks back towards BufferSize once the throughput dropped, so that the
+	// worst-case memory is not reserved permanently.
+	MaxBufferSize int
+	// Overflow is the behaviour when the buffer is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+	// Priority returns the priority of the entries, such as JSONPriority. When the buffer
+	// is full with OverflowDrop, the oldest buffered entry of the lowest priority is
+	// dropped to make room for an entry of a higher priority, and the entry is dropped
+	// otherwise. By default all the entries have the same priority.
+	Priority func(entry []byte) Priority
+	// Prefix is the start shared by the entries, such as the bytes of the StaticPrefix of
+	// the JSONFormatter. The entries starting with it are buffered without it, and the
+	// net.Conn writers get it in a buffer shared by the entries of the vectored writes,
+	// instead of a copy in front of each entry.
+	Prefix []byte
+}
+
+// BuffersWriter is implemented by the writers writing several entries at once, such as
+// in a single writev system call on a connection. The AsyncWriter passes the entries
+// buffered while it was writing to WriteBuffers, instead of writing them one by one.
+type BuffersWriter interface {
+	// WriteBuffers writes the buffers, each holding an entry, consuming them as they are
+	// written, and returns the number of bytes written.
+	WriteBuffers(buffers *net.Buffers) (int64, error)
+}
+
+// AsyncWriter is an io.Writer buffering the entries and writing them to the underlying
+// writer from a background goroutine, in the order they were written, to take the output
+// latency off the logging goroutines. The entries buffered while a write is in progress
+// are written at once to the BuffersWriter and net.Conn writers, without copying them
+// into a single buffer. Flush or Close it on shutdown so that no entry is lost. It is
+// flushed when logrus exits the process, such as on Fatal.
+type AsyncWriter struct {
+	writer      io.Writer
+	capacity    int
+	minCapacity int
+	maxCapacity int
+	highWater   int
+	measured    time.Time
+	now         func() time.Time
+	overflow    OverflowPolicy
+	priority    func(entry []byte) Priority
+	mutex       sync.Mutex
+	changed     *sync.Cond
+	queue       [][]byte
+	priorities  []Priority
+	prefix      []byte
+	prefixed    []bool
+	inflight    int
+	closed      bool
+	dropped     int64
+	done        chan struct{}
+}
+
+// NewAsyncWriter returns an AsyncWriter writing to w, to be set as the output of the logger:
+//
+//	writer := glogger.NewAsyncWriter(os.Stdout, glogger.AsyncWriterOptions{})
+//	defer writer.Close()
+//	logger.SetOutput(writer)
+func NewAsyncWriter(w io.Writer, options AsyncWriterOptions) *AsyncWriter {
+	capacity := options.BufferSize
+
+	if capacity <= 0 {
+		capacity = defaultAsyncBufferSize
+	}
+
+	writer := &AsyncWriter{
+		writer:      w,
+		capacity:    capacity,
+		minCapacity: capacity,
+		maxCapacity: max(options.MaxBufferSize, capacity),
+		now:         time.Now,
+		overflow:    options.Overflow,
+		priority:    options.Priority,
+		prefix:      options.Prefix,
+		done:        make(chan struct{}),
+	}
+	writer.measured = writer.now()
+	writer.changed = sync.NewCond(&writer.mutex)
+
+	go writer.run()
+	flushOnExit(writer, writer.Flush)
+
+	return writer
+}
+
+// Write buffers a copy of the entry. When the buffer is full, it blocks or drops an
+// entry depending on the overflow policy.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	var priority Priority
+
+	if w.priority != nil {
+		priority = w.priority(p)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for !w.closed && w.full() {
+		if w.capacity < w.maxCapacity {
+			w.capacity = min(2*w.capacity, w.maxCapacity)
+			continue
+		}
+
+		if w.overflow == OverflowDrop {
+			w.dropped++
+
+			if !w.evict(priority) {
+				return len(p), nil
+			}
+
+			continue
+		}
+
+		w.changed.Wait()
+	}
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	entry := p
+	prefixed := len(w.prefix) > 0 && bytes.HasPrefix(p, w.prefix)
+
+	if prefixed {
+		entry = p[len(w.prefix):]
+	}
+
+	w.queue = append(w.queue, append([]byte(nil), entry...))
+
+	if w.priority != nil {
+		w.priorities = append(w.priorities, priority)
+	}
+
+	if len(w.prefix) > 0 {
+		w.prefixed = append(w.prefixed, prefixed)
+	}
+
+	w.highWater = max(w.highWater, len(w.queue)+w.inflight)
+	w.changed.Broadcast()
+
+	return len(p), nil
+}
+
+func (w *AsyncWriter) full() bool {
+	return len(w.queue)+w.inflight >= w.capacity
+}
+
+// evict removes the oldest buffered entry of the lowest priority, if it is lower than the
+// priority, and returns true if an entry was removed.
+func (w *AsyncWriter) evict(priority Priority) bool {
+	index := -1
+
+	for i, buffered := range w.priorities {
+		if buffered < priority && (index < 0 || buffered < w.priorities[index]) {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return false
+	}
+
+	w.queue = append(w.queue[:index], w.queue[index+1:]...)
+	w.priorities = append(w.priorities[:index], w.priorities[index+1:]...)
+
+	if len(w.prefix) > 0 {
+		w.prefixed = append(w.prefixed[:index], w.prefixed[index+1:]...)
+	}
+
+	return true
+}
+
+// shrink halves the capacity, down to the minimum, when the high-water mark of the buffer
+// stayed at a quarter of the capacity or less for the last period.
+func (w *AsyncWriter) shrink() {
+	current := w.now()
+
+	if current.Sub(w.measured) < asyncShrinkInterval {
+		return
+	}
+
+	if w.highWater <= w.capacity/4 {
+		w.capacity = max(w.capacity/2, w.minCapacity)
+	}
+
+	w.highWater = len(w.queue) + w.inflight
+	w.measured = current
+}
+
+// BufferSize returns the current maximum number of entries buffered, between BufferSize
+// and MaxBufferSize.
+func (w *AsyncWriter) BufferSize() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.capacity
+}
+
+// Saturation returns the number of entries buffered over MaxBufferSize, or BufferSize if
+// it is lower, from 0 to 1 when the overflow policy applies.
+func (w *AsyncWriter) Saturation() float64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return float64(len(w.queue)+w.inflight) / float64(w.maxCapacity)
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (w *AsyncWriter) Dropped() int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.dropped
+}
+
+// Flush blocks until the buffered entries are written to the underlying writer.
+func (w *AsyncWriter) Flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for len(w.queue) > 0 || w.inflight > 0 {
+		w.changed.Wait()
+	}
+}
+
+// Close writes the buffered entries and stops the writer. The following writes fail
+// with ErrWriterClosed. The underlying writer is not closed.
+func (w *AsyncWriter) Close() error {
+	stopFlushOnExit(w)
+
+	w.mutex.Lock()
+	w.closed = true
+	w.changed.Broadcast()
+	w.mutex.Unlock()
+
+	<-w.done
+
+	return nil
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for {
+		w.mutex.Lock()
+
+		for len(w.queue) == 0 && !w.closed {
+			w.changed.Wait()
+		}
+
+		if len(w.queue) == 0 {
+			w.mutex.Unlock()
+			return
+		}
+
+		batch, prefixed := w.queue, w.prefixed
+		w.queue = nil
+		w.priorities = nil
+		w.prefixed = nil
+		w.inflight = len(batch)
+		w.mutex.Unlock()
+
+		w.write(batch, prefixed)
+
+		w.mutex.Lock()
+		w.inflight = 0
+
+		if w.maxCapacity > w.minCapacity {
+			w.shrink()
+		}
+
+		w.changed.Broadcast()
+		w.mutex.Unlock()
+	}
+}
+
+// write writes the batch of entries, with the prefix in front of the prefixed ones.
+func (w *AsyncWriter) write(batch [][]byte, prefixed []bool) {
+	switch writer := w.writer.(type) {
+	case BuffersWriter:
+		buffers := net.Buffers(w.withPrefix(batch, prefixed))
+
+		if _, err := writer.WriteBuffers(&buffers); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		}
+	case net.Conn:
+		// The prefix is shared by the buffers of the vectored write.
+		buffers := make(net.Buffers, 0, len(batch)+len(prefixed))
+
+		for i, entry := range batch {
+			if len(prefixed) > 0 && prefixed[i] {
+				buffers = append(buffers, w.prefix)
+			}
+
+			buffers = append(buffers, entry)
+		}
+
+		if _, err := buffers.WriteTo(writer); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		}
+	default:
+		for _, entry := range w.withPrefix(batch, prefixed) {
+			if _, err := w.writer.Write(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+			}
+		}
+	}
+}
+
+// withPrefix returns the entries of the batch, with a copy of the prefix in front of the
+// prefixed ones, for the writers writing an entry per buffer.
+func (w *AsyncWriter) withPrefix(batch [][]byte, prefixed []bool) [][]byte {
+	for i := range prefixed {
+		if prefixed[i] {
+			entry := make([]byte, 0, len(w.prefix)+len(batch[i]))
+			batch[i] = append(append(entry, w.prefix...), batch[i]...)
+		}
+	}
+
+	return batch
+}
@@ -0,0 +1,264 @@
+package glogger
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+// gatedWriter blocks the writes until the gate is opened.
+type gatedWriter struct {
+	gate   chan struct{}
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.buffer.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.buffer.String()
+}
+
+// buffersWriter records the batches written, blocking each write until the gate is opened.
+type buffersWriter struct {
+	gate    chan struct{}
+	started chan struct{}
+	mutex   sync.Mutex
+	batches [][]string
+}
+
+func (w *buffersWriter) Write(p []byte) (int, error) {
+	panic("unexpected write")
+}
+
+func (w *buffersWriter) WriteBuffers(buffers *net.Buffers) (int64, error) {
+	w.started <- struct{}{}
+	<-w.gate
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var batch []string
+
+	for _, buffer := range *buffers {
+		batch = append(batch, string(buffer))
+	}
+
+	w.batches = append(w.batches, batch)
+
+	return buffers.WriteTo(io.Discard)
+}
+
+// recordingConn records the buffers written to the connection.
+type recordingConn struct {
+	net.Conn
+	mutex   sync.Mutex
+	buffers [][]byte
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.buffers = append(c.buffers, p)
+
+	return len(p), nil
+}
+
+func TestAsyncWriter(t *testing.T) {
+	t.Run("Entries are written in order on flush", func(t *testing.T) {
+		var output bytes.Buffer
+		writer := NewAsyncWriter(&output, AsyncWriterOptions{})
+		defer writer.Close()
+
+		logger := logrus.New()
+		logger.SetFormatter(&JSONFormatter{})
+		logger.SetOutput(writer)
+
+		for i := 0; i < 100; i++ {
+			logger.WithField("i", i).Info("Entry")
+		}
+
+		writer.Flush()
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		assert.Equal(t, len(lines), 100)
+		assert.Assert(t, strings.Contains(lines[99], `"i":99`), lines[99])
+	})
+
+	t.Run("Entries are dropped when the buffer is full", func(t *testing.T) {
+		output := &gatedWriter{gate: make(chan struct{})}
+		writer := NewAsyncWriter(output, AsyncWriterOptions{BufferSize: 2, Overflow: OverflowDrop})
+
+		for i := 0; i < 5; i++ {
+			writer.Write([]byte("entry\n"))
+		}
+
+		assert.Equal(t, writer.Dropped(), int64(3))
+		assert.Equal(t, writer.Saturation(), 1.0)
+
+		close(output.gate)
+		writer.Close()
+
+		assert.Equal(t, output.String(), "entry\nentry\n")
+	})
+
+	t.Run("Entries of the lowest priority are dropped first", func(t *testing.T) {
+		output := &gatedWriter{gate: make(chan struct{})}
+		writer := NewAsyncWriter(output, AsyncWriterOptions{BufferSize: 4, Overflow: OverflowDrop, Priority: JSONPriority})
+		writer.Write([]byte(`{"level":"info","i":0}` + "\n"))
+
+		for inflight := 0; inflight == 0; {
+			writer.mutex.Lock()
+			inflight = writer.inflight
+			writer.mutex.Unlock()
+		}
+
+		for _, entry := range []string{
+			`{"level":"debug","i":1}`,
+			`{"level":"error","i":2}`,
+			`{"level":"info","i":3}`,
+			`{"audit":{},"level":"info","i":4}`,
+			`{"level":"warning","i":5}`,
+			`{"level":"debug","i":6}`,
+		} {
+			writer.Write([]byte(entry + "\n"))
+		}
+
+		assert.Equal(t, writer.Dropped(), int64(3))
+
+		close(output.gate)
+		writer.Close()
+
+		assert.Equal(t, output.String(), `{"level":"info","i":0}`+"\n"+`{"level":"error","i":2}`+"\n"+`{"audit":{},"level":"info","i":4}`+"\n"+`{"level":"warning","i":5}`+"\n")
+	})
+
+	t.Run("Buffer grows during bursts and shrinks back", func(t *testing.T) {
+		output := &gatedWriter{gate: make(chan struct{})}
+		writer := NewAsyncWriter(output, AsyncWriterOptions{BufferSize: 2, MaxBufferSize: 8, Overflow: OverflowDrop})
+
+		var elapsed atomic.Int64
+		start := time.Now()
+
+		writer.mutex.Lock()
+		writer.now = func() time.Time { return start.Add(time.Duration(elapsed.Load())) }
+		writer.measured = start
+		writer.mutex.Unlock()
+
+		for i := 0; i < 10; i++ {
+			writer.Write([]byte("entry\n"))
+		}
+
+		assert.Equal(t, writer.BufferSize(), 8)
+		assert.Equal(t, writer.Dropped(), int64(2))
+
+		close(output.gate)
+		writer.Flush()
+
+		for _, expected := range []int{8, 4, 2, 2} {
+			elapsed.Add(int64(asyncShrinkInterval))
+			writer.Write([]byte("entry\n"))
+			writer.Flush()
+
+			assert.Equal(t, writer.BufferSize(), expected)
+		}
+
+		writer.Close()
+	})
+
+	t.Run("Writes block until the buffer has room", func(t *testing.T) {
+		output := &gatedWriter{gate: make(chan struct{})}
+		writer := NewAsyncWriter(output, AsyncWriterOptions{BufferSize: 1})
+
+		writer.Write([]byte("first\n"))
+		written := make(chan struct{})
+
+		go func() {
+			writer.Write([]byte("second\n"))
+			close(written)
+		}()
+
+		close(output.gate)
+		<-written
+		writer.Close()
+
+		assert.Equal(t, output.String(), "first\nsecond\n")
+		assert.Equal(t, writer.Dropped(), int64(0))
+	})
+
+	t.Run("Writes fail once closed", func(t *testing.T) {
+		writer := NewAsyncWriter(&bytes.Buffer{}, AsyncWriterOptions{})
+		writer.Close()
+
+		_, err := writer.Write([]byte("entry\n"))
+		assert.Equal(t, err, ErrWriterClosed)
+	})
+
+	t.Run("Entries buffered during a write are written at once to a BuffersWriter", func(t *testing.T) {
+		output := &buffersWriter{gate: make(chan struct{}), started: make(chan struct{}, 2)}
+		writer := NewAsyncWriter(output, AsyncWriterOptions{})
+		defer writer.Close()
+
+		writer.Write([]byte("first\n"))
+		<-output.started
+
+		writer.Write([]byte("second\n"))
+		writer.Write([]byte("third\n"))
+		close(output.gate)
+
+		writer.Flush()
+
+		assert.DeepEqual(t, output.batches, [][]string{{"first\n"}, {"second\n", "third\n"}})
+	})
+
+	t.Run("Prefix is shared by the entries written to a connection", func(t *testing.T) {
+		prefix := []byte(`{"service":"api",`)
+		output := &recordingConn{}
+		writer := NewAsyncWriter(output, AsyncWriterOptions{Prefix: prefix})
+
+		writer.Write([]byte(`{"service":"api","i":1}` + "\n"))
+		writer.Write([]byte(`{"i":2}` + "\n"))
+		writer.Close()
+
+		var entries []string
+
+		for _, buffer := range output.buffers {
+			if string(buffer) == string(prefix) {
+				assert.Assert(t, &buffer[0] == &prefix[0], "The prefix is copied")
+			}
+
+			entries = append(entries, string(buffer))
+		}
+
+		assert.DeepEqual(t, entries, []string{string(prefix), `"i":1}` + "\n", `{"i":2}` + "\n"})
+	})
+
+	t.Run("Prefix is written in front of the entries to other writers", func(t *testing.T) {
+		var output bytes.Buffer
+		writer := NewAsyncWriter(&output, AsyncWriterOptions{Prefix: []byte(`{"service":"api",`)})
+
+		writer.Write([]byte(`{"service":"api","i":1}` + "\n"))
+		writer.Write([]byte(`{"i":2}` + "\n"))
+		writer.Close()
+
+		assert.Equal(t, output.String(), `{"service":"api","i":1}`+"\n"+`{"i":2}`+"\n")
+	})
+}
@@ -0,0 +1,375 @@
+package glogger
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	auditKey     = "audit"
+	prevHashKey  = "prevHash"
+	auditMessage = "Audit"
+
+	maxAuditLineSize = 1 << 20
+	maxAuditEventIDs = 10000
+)
+
+// Outcomes of the audit events.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeDenied  = "denied"
+)
+
+type auditLoggerKey struct{}
+
+// AuditEvent struct contains items of audit info log: who did what to which resource.
+type AuditEvent struct {
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	Resource  string `json:"resource"`
+	Outcome   string `json:"outcome"`
+	RequestID string `json:"requestId,omitempty"`
+	EventID   string `json:"eventId,omitempty"`
+}
+
+// AuditOptions is the struct of options to configure an audit logger
+type AuditOptions struct {
+	// Output is the dedicated sink of the audit entries. Defaults to os.Stderr.
+	Output io.Writer
+	// HashChain adds the hash of the previous entry to each entry, as prevHash, and the
+	// SHA-256 of the entry itself, as a trailing hash field, computed over the line without
+	// the hash field. A removed or modified entry breaks the chain. The chain continues
+	// the chain of the entries already written to the Output, when it is an *os.File or
+	// a *RotatingFile, and the entries whose write failed are not chained.
+	HashChain bool
+	// PrevHash is the hash of the last entry written to the Output, to continue its chain
+	// when the Output is not a file, such as a network sink.
+	PrevHash string
+	// DedupWindow drops the events whose EventID was already recorded within the window,
+	// such as the events recorded again by a retried operation, so that they are written
+	// once. The deduplication is best-effort: the event IDs are remembered in memory by
+	// each audit logger, so not across restarts nor replicas, and the oldest are
+	// forgotten before the end of their window past 10000 event IDs.
+	DedupWindow time.Duration
+	// EventIDGenerator generates the IDs of the events recorded without one. Defaults to
+	// UUIDv4.
+	EventIDGenerator IDGenerator
+}
+
+// AuditLogger writes the audit events to a dedicated sink, apart from the request logs.
+type AuditLogger struct {
+	logger     *logrus.Logger
+	fence      *auditFence
+	newEventID IDGenerator
+}
+
+// NewAuditLogger returns an audit logger writing JSON entries to the output.
+func NewAuditLogger(options AuditOptions) *AuditLogger {
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+
+	if options.Output != nil {
+		logger.SetOutput(options.Output)
+	}
+
+	var formatter logrus.Formatter = &JSONFormatter{}
+
+	if options.HashChain {
+		chain := &hashChainFormatter{formatter: formatter, prevHash: options.PrevHash}
+
+		if chain.prevHash == "" {
+			chain.prevHash = lastAuditHash(logger.Out)
+		}
+
+		formatter = chain
+		logger.SetOutput(&hashChainWriter{writer: logger.Out, chain: chain})
+	}
+
+	logger.SetFormatter(formatter)
+
+	return &AuditLogger{logger: logger, fence: newAuditFence(options.DedupWindow, time.Now), newEventID: options.EventIDGenerator}
+}
+
+// WithAuditLogger returns a new context with the audit logger used by Audit.
+func WithAuditLogger(ctx context.Context, audit *AuditLogger) context.Context {
+	return context.WithValue(ctx, auditLoggerKey{}, audit)
+}
+
+// AuditRecorder records the audit events of a context.
+type AuditRecorder struct {
+	ctx   context.Context
+	audit *AuditLogger
+}
+
+// Audit returns the recorder of the audit events of the context, written to the audit
+// logger set by the middleware AuditLogger option or WithAuditLogger. Without audit
+// logger, the events are logged with the context logger.
+func Audit(ctx context.Context) *AuditRecorder {
+	audit, _ := ctx.Value(auditLoggerKey{}).(*AuditLogger)
+	return &AuditRecorder{ctx: ctx, audit: audit}
+}
+
+// Record records the event. Its request ID defaults to the request ID of the context,
+// and its event ID to a new ID of the EventIDGenerator of the audit logger. Set the event
+// ID to a key of the audited operation, such as its idempotency key, so that the retries
+// of the operation record the same event.
+func (recorder *AuditRecorder) Record(event AuditEvent) {
+	if event.RequestID == "" {
+		event.RequestID = RequestIDFromContext(recorder.ctx)
+	}
+
+	if event.EventID == "" {
+		var generate IDGenerator
+
+		if recorder.audit != nil {
+			generate = recorder.audit.newEventID
+		}
+
+		event.EventID = generate.newID()
+	}
+
+	if recorder.audit == nil {
+		Get(recorder.ctx).WithField(auditKey, event).Info(auditMessage)
+		return
+	}
+
+	if !recorder.audit.fence.admit(event.EventID) {
+		return
+	}
+
+	recorder.audit.logger.WithField(auditKey, event).Info(auditMessage)
+}
+
+// auditFence remembers the IDs of the events recorded within a window.
+type auditFence struct {
+	window   time.Duration
+	now      func() time.Time
+	mutex    sync.Mutex
+	recorded map[string]*list.Element
+	// events are the recorded events ordered by their recording time, the oldest first.
+	events *list.List
+}
+
+type recordedEvent struct {
+	id   string
+	time time.Time
+}
+
+func newAuditFence(window time.Duration, now func() time.Time) *auditFence {
+	if window <= 0 {
+		return nil
+	}
+
+	return &auditFence{window: window, now: now, recorded: map[string]*list.Element{}, events: list.New()}
+}
+
+// admit reports whether the event wasn't recorded within the window, remembering it.
+// The events recorded before the window are forgotten, and the oldest events past
+// maxAuditEventIDs. A nil fence admits every event.
+func (fence *auditFence) admit(eventID string) bool {
+	if fence == nil {
+		return true
+	}
+
+	fence.mutex.Lock()
+	defer fence.mutex.Unlock()
+
+	current := fence.now()
+
+	for oldest := fence.events.Front(); oldest != nil; oldest = fence.events.Front() {
+		if event := oldest.Value.(*recordedEvent); current.Sub(event.time) < fence.window && len(fence.recorded) < maxAuditEventIDs {
+			break
+		}
+
+		delete(fence.recorded, fence.events.Remove(oldest).(*recordedEvent).id)
+	}
+
+	if _, ok := fence.recorded[eventID]; ok {
+		return false
+	}
+
+	fence.recorded[eventID] = fence.events.PushBack(&recordedEvent{id: eventID, time: current})
+
+	return true
+}
+
+// hashChainFormatter chains the entries with their hashes. The logger lock is held while
+// formatting and writing, so that the entries are chained in their writing order.
+type hashChainFormatter struct {
+	formatter logrus.Formatter
+	mutex     sync.Mutex
+	prevHash  string
+	// hash is the hash of the formatted entry, which becomes the prevHash once written.
+	hash string
+}
+
+func (formatter *hashChainFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	formatter.mutex.Lock()
+	defer formatter.mutex.Unlock()
+
+	chained := *entry
+	chained.Data = make(logrus.Fields, len(entry.Data)+1)
+
+	for key, value := range entry.Data {
+		chained.Data[key] = value
+	}
+
+	chained.Data[prevHashKey] = formatter.prevHash
+
+	serialized, err := formatter.formatter.Format(&chained)
+
+	if err != nil {
+		return nil, err
+	}
+
+	line := bytes.TrimSuffix(serialized, []byte("\n"))
+	sum := sha256.Sum256(line)
+	hash := hex.EncodeToString(sum[:])
+	formatter.hash = hash
+
+	output := make([]byte, 0, len(line)+len(hash)+12)
+	output = append(output, line[:len(line)-1]...)
+	output = append(output, `,"hash":"`...)
+	output = append(output, hash...)
+	output = append(output, "\"}\n"...)
+
+	return output, nil
+}
+
+// advance chains the next entries to the entry formatted last, once written.
+func (formatter *hashChainFormatter) advance() {
+	formatter.mutex.Lock()
+	defer formatter.mutex.Unlock()
+
+	formatter.prevHash = formatter.hash
+}
+
+// hashChainWriter advances the hash chain once an entry is written, so that the entry
+// following a failed write is chained to the last written entry.
+type hashChainWriter struct {
+	writer io.Writer
+	chain  *hashChainFormatter
+}
+
+func (w *hashChainWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+
+	if err == nil {
+		w.chain.advance()
+	}
+
+	return n, err
+}
+
+// lastAuditHash returns the hash of the last entry of the file written by the output, an
+// *os.File or a *RotatingFile, if any.
+func lastAuditHash(output io.Writer) string {
+	var filename string
+
+	switch output := output.(type) {
+	case *os.File:
+		filename = output.Name()
+	case *RotatingFile:
+		filename = output.options.Filename
+	default:
+		return ""
+	}
+
+	file, err := os.Open(filename)
+
+	if err != nil {
+		return ""
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+
+	offset := max(info.Size()-maxAuditLineSize, 0)
+	tail := make([]byte, info.Size()-offset)
+
+	if _, err := file.ReadAt(tail, offset); err != nil {
+		return ""
+	}
+
+	lines := bytes.Split(bytes.TrimRight(tail, "\n"), []byte("\n"))
+	_, hash, _ := splitAuditHash(lines[len(lines)-1])
+
+	return hash
+}
+
+// splitAuditHash returns the entry without its hash field, and the hash.
+func splitAuditHash(line []byte) ([]byte, string, bool) {
+	index := bytes.LastIndex(line, []byte(`,"hash":"`))
+
+	if index < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", false
+	}
+
+	return append(append([]byte(nil), line[:index]...), '}'), string(line[index+len(`,"hash":"`) : len(line)-2]), true
+}
+
+// VerifyAuditChain verifies the hash chain of the audit entries read from the reader,
+// returning the number of verified entries and an error at the first broken link. The
+// prevHash of the first entry is not verified, so that a rotated file can be verified.
+func VerifyAuditChain(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxAuditLineSize)
+
+	count := 0
+	prevHash := ""
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		if len(line) == 0 {
+			continue
+		}
+
+		unhashed, hash, ok := splitAuditHash(line)
+
+		if !ok {
+			return count, fmt.Errorf("entry %d has no hash", count+1)
+		}
+
+		sum := sha256.Sum256(unhashed)
+
+		if hex.EncodeToString(sum[:]) != hash {
+			return count, fmt.Errorf("entry %d was modified", count+1)
+		}
+
+		var fields struct {
+			PrevHash string `json:"prevHash"`
+		}
+
+		if err := json.Unmarshal(unhashed, &fields); err != nil {
+			return count, fmt.Errorf("entry %d is invalid: %w", count+1, err)
+		}
+
+		if count > 0 && fields.PrevHash != prevHash {
+			return count, fmt.Errorf("entry %d does not follow entry %d", count+1, count)
+		}
+
+		prevHash = hash
+		count++
+	}
+
+	return count, scanner.Err()
+}
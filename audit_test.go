@@ -0,0 +1,179 @@
+package glogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestAudit(t *testing.T) {
+	t.Run("Events are written to the audit sink", func(t *testing.T) {
+		var sink bytes.Buffer
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{AuditLogger: NewAuditLogger(AuditOptions{Output: &sink})}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			Audit(r.Context()).Record(AuditEvent{Actor: "user-1", Action: "delete", Resource: "document/42", Outcome: AuditOutcomeSuccess})
+		}))
+		request := httptest.NewRequest(http.MethodDelete, "/documents/42", nil)
+		request.Header.Set("X-Request-Id", "request-id")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		var entry struct {
+			Message string
+			Audit   AuditEvent
+		}
+		assert.NilError(t, json.Unmarshal(sink.Bytes(), &entry))
+		assert.Equal(t, entry.Message, "Audit")
+		assert.Assert(t, entry.Audit.EventID != "", "Expected a generated event ID")
+		assert.Equal(t, entry.Audit, AuditEvent{Actor: "user-1", Action: "delete", Resource: "document/42", Outcome: "success", RequestID: "request-id", EventID: entry.Audit.EventID})
+
+		for _, logged := range hook.AllEntries() {
+			_, ok := logged.Data["audit"]
+			assert.Assert(t, !ok, "Audit events must not be written to the request logs")
+		}
+	})
+
+	t.Run("Events are logged with the context logger without audit logger", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		ctx := WithLogger(context.Background(), logrus.NewEntry(logger))
+
+		Audit(ctx).Record(AuditEvent{Actor: "user-1", Action: "login", Resource: "session", Outcome: AuditOutcomeDenied})
+
+		assert.Equal(t, hook.LastEntry().Data["audit"].(AuditEvent).Outcome, "denied")
+	})
+}
+
+func TestAuditDedup(t *testing.T) {
+	var sink bytes.Buffer
+	audit := NewAuditLogger(AuditOptions{Output: &sink, DedupWindow: time.Minute})
+	now := time.Now()
+	audit.fence.now = func() time.Time { return now }
+	ctx := WithAuditLogger(context.Background(), audit)
+
+	event := AuditEvent{Actor: "user-1", Action: "pay", Resource: "invoice/42", Outcome: AuditOutcomeSuccess, EventID: "payment-7"}
+
+	Audit(ctx).Record(event)
+	Audit(ctx).Record(event)
+	Audit(ctx).Record(AuditEvent{Actor: "user-1", Action: "pay", Resource: "invoice/43", Outcome: AuditOutcomeSuccess})
+
+	now = now.Add(time.Minute)
+	Audit(ctx).Record(event)
+
+	var eventIDs []string
+
+	for _, line := range strings.Split(strings.TrimSpace(sink.String()), "\n") {
+		var entry struct{ Audit AuditEvent }
+		assert.NilError(t, json.Unmarshal([]byte(line), &entry))
+		eventIDs = append(eventIDs, entry.Audit.EventID)
+	}
+
+	assert.Equal(t, len(eventIDs), 3)
+	assert.Equal(t, eventIDs[0], "payment-7")
+	assert.Assert(t, eventIDs[1] != "" && eventIDs[1] != "payment-7", eventIDs[1])
+	assert.Equal(t, eventIDs[2], "payment-7")
+
+	t.Run("The oldest events are forgotten", func(t *testing.T) {
+		fence := newAuditFence(time.Hour, func() time.Time { return now })
+
+		assert.Assert(t, fence.admit("payment-7"))
+
+		for i := 0; i < maxAuditEventIDs; i++ {
+			now = now.Add(time.Millisecond)
+			assert.Assert(t, fence.admit(fmt.Sprint(i)))
+		}
+
+		assert.Assert(t, !fence.admit("1"))
+		assert.Assert(t, fence.admit("payment-7"))
+		assert.Equal(t, len(fence.recorded), maxAuditEventIDs)
+
+		now = now.Add(time.Hour)
+		assert.Assert(t, fence.admit("1"))
+		assert.Equal(t, len(fence.recorded), 1)
+	})
+}
+
+func TestAuditHashChain(t *testing.T) {
+	var sink bytes.Buffer
+	ctx := WithAuditLogger(context.Background(), NewAuditLogger(AuditOptions{Output: &sink, HashChain: true}))
+
+	for _, action := range []string{"create", "update", "delete"} {
+		Audit(ctx).Record(AuditEvent{Actor: "user-1", Action: action, Resource: "document/42", Outcome: AuditOutcomeSuccess})
+	}
+
+	count, err := VerifyAuditChain(bytes.NewReader(sink.Bytes()))
+	assert.NilError(t, err)
+	assert.Equal(t, count, 3)
+
+	lines := strings.SplitAfter(sink.String(), "\n")
+
+	modified := lines[0] + strings.Replace(lines[1], "update", "read", 1) + lines[2]
+	_, err = VerifyAuditChain(strings.NewReader(modified))
+	assert.ErrorContains(t, err, "entry 2 was modified")
+
+	removed := lines[0] + lines[2]
+	_, err = VerifyAuditChain(strings.NewReader(removed))
+	assert.ErrorContains(t, err, "entry 2 does not follow entry 1")
+
+	t.Run("The entries whose write failed are not chained", func(t *testing.T) {
+		sink := &flakyWriter{}
+		ctx := WithAuditLogger(context.Background(), NewAuditLogger(AuditOptions{Output: sink, HashChain: true}))
+
+		for _, fail := range []bool{false, true, false} {
+			sink.fail = fail
+			Audit(ctx).Record(AuditEvent{Actor: "user-1", Action: "update", Resource: "document/42", Outcome: AuditOutcomeSuccess})
+		}
+
+		count, err := VerifyAuditChain(bytes.NewReader(sink.Bytes()))
+		assert.NilError(t, err)
+		assert.Equal(t, count, 2)
+	})
+
+	t.Run("The chain of the file is continued", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "audit.log")
+
+		for _, action := range []string{"create", "update"} {
+			file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			assert.NilError(t, err)
+
+			ctx := WithAuditLogger(context.Background(), NewAuditLogger(AuditOptions{Output: file, HashChain: true}))
+			Audit(ctx).Record(AuditEvent{Actor: "user-1", Action: action, Resource: "document/42", Outcome: AuditOutcomeSuccess})
+			file.Close()
+		}
+
+		file, err := os.Open(filename)
+		assert.NilError(t, err)
+		defer file.Close()
+
+		count, err := VerifyAuditChain(file)
+		assert.NilError(t, err)
+		assert.Equal(t, count, 2)
+	})
+}
+
+// flakyWriter fails the writes while fail is set.
+type flakyWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("connection reset")
+	}
+
+	return w.Buffer.Write(p)
+}
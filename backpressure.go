@@ -0,0 +1,63 @@
+package glogger
+
+import (
+	"net/http"
+)
+
+const (
+	defaultBackpressureThreshold = 0.9
+	saturatedValue               = "saturated"
+)
+
+// BackpressureOptions is the struct of options to configure the backpressure of the
+// logging middleware, for the systems where losing the logs, such as the audit logs, is
+// worse than shedding load.
+type BackpressureOptions struct {
+	// Saturation returns the saturation of the log pipeline, from 0 when it is idle to 1
+	// when it is full, such as the Saturation of an AsyncWriter or of the OTLP exporter.
+	// The backpressure is disabled when it is nil.
+	Saturation func() float64
+	// Threshold is the saturation from which the pipeline is saturated. Defaults to 0.9.
+	Threshold float64
+	// Shed returns true for the low-priority requests, answered with 503 Service
+	// Unavailable while the pipeline is saturated instead of being passed to the handler.
+	// By default no request is shed.
+	Shed func(r *http.Request) bool
+	// Header is the name of the response header set to "saturated" while the pipeline is
+	// saturated, such as "X-Log-Backpressure", to signal the load balancers and clients.
+	Header string
+}
+
+func (options BackpressureOptions) saturated() bool {
+	if options.Saturation == nil {
+		return false
+	}
+
+	threshold := options.Threshold
+
+	if threshold <= 0 {
+		threshold = defaultBackpressureThreshold
+	}
+
+	return options.Saturation() >= threshold
+}
+
+// handler returns the handler of the request: next, or a handler answering 503 if the
+// request is shed while the pipeline is saturated.
+func (options BackpressureOptions) handler(next http.Handler, rw http.ResponseWriter, r *http.Request) http.Handler {
+	if !options.saturated() {
+		return next
+	}
+
+	if options.Header != "" {
+		rw.Header().Set(options.Header, saturatedValue)
+	}
+
+	if options.Shed == nil || !options.Shed(r) {
+		return next
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	})
+}
@@ -0,0 +1,59 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestBackpressure(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	saturation := 0.5
+
+	options := MiddlewareOptions{
+		Backpressure: BackpressureOptions{
+			Saturation: func() float64 { return saturation },
+			Shed: func(r *http.Request) bool {
+				return strings.HasPrefix(r.URL.Path, "/reports/")
+			},
+			Header: "X-Log-Backpressure",
+		},
+	}
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	t.Run("Requests are served below the threshold", func(t *testing.T) {
+		recorder := serve("/reports/daily")
+
+		assert.Equal(t, recorder.Code, http.StatusNoContent)
+		assert.Equal(t, recorder.Header().Get("X-Log-Backpressure"), "")
+	})
+
+	saturation = 0.95
+
+	t.Run("Low-priority requests are shed when saturated", func(t *testing.T) {
+		recorder := serve("/reports/daily")
+
+		assert.Equal(t, recorder.Code, http.StatusServiceUnavailable)
+		assert.Equal(t, recorder.Header().Get("X-Log-Backpressure"), "saturated")
+		assert.Equal(t, hook.LastEntry().Data["http"].(HTTP).Response.StatusCode, http.StatusServiceUnavailable)
+	})
+
+	t.Run("Other requests are served with the header when saturated", func(t *testing.T) {
+		recorder := serve("/orders")
+
+		assert.Equal(t, recorder.Code, http.StatusNoContent)
+		assert.Equal(t, recorder.Header().Get("X-Log-Backpressure"), "saturated")
+	})
+}
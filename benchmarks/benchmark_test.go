@@ -0,0 +1,114 @@
+package benchmarks
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+func newGloggerLogger(w io.Writer) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(w)
+	logger.SetFormatter(&glogger.JSONFormatter{})
+	logger.SetLevel(logrus.InfoLevel)
+	return logger
+}
+
+func benchmarkMiddleware(b *testing.B, handler http.Handler) {
+	r := newRequest()
+	rw := newDiscardResponseWriter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		handler.ServeHTTP(rw, r)
+	}
+}
+
+func BenchmarkMiddleware(b *testing.B) {
+	b.Run("glogger", func(b *testing.B) {
+		benchmarkMiddleware(b, glogger.LoggingMiddleware(newGloggerLogger(io.Discard))(handler))
+	})
+
+	b.Run("zap", func(b *testing.B) {
+		benchmarkMiddleware(b, zapMiddleware(newZapLogger(io.Discard))(handler))
+	})
+
+	b.Run("zerolog", func(b *testing.B) {
+		benchmarkMiddleware(b, zerologMiddleware(newZerologLogger(io.Discard))(handler))
+	})
+}
+
+func BenchmarkFormatter(b *testing.B) {
+	b.Run("glogger", func(b *testing.B) {
+		logger := newGloggerLogger(io.Discard)
+		fields := logrus.Fields{
+			"correlationId": requestID,
+			"http": glogger.HTTP{
+				Request:  &glogger.Request{Path: requestPath, Method: http.MethodGet, UserAgent: userAgent},
+				Response: &glogger.Response{StatusCode: http.StatusOK, Bytes: len(response)},
+			},
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			logger.WithTime(fixedTime).WithFields(fields).Info("Completed Request")
+		}
+	})
+
+	b.Run("zap", func(b *testing.B) {
+		logger := newZapLogger(io.Discard)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			logger.Info("Completed Request",
+				zap.String("correlationId", requestID),
+				zap.Dict("http",
+					zap.Dict("request",
+						zap.String("path", requestPath),
+						zap.String("method", http.MethodGet),
+						zap.String("useragent", userAgent),
+					),
+					zap.Dict("response",
+						zap.Int("statusCode", http.StatusOK),
+						zap.Int("bytes", len(response)),
+					),
+				),
+			)
+		}
+	})
+
+	b.Run("zerolog", func(b *testing.B) {
+		logger := newZerologLogger(io.Discard)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			logger.Info().
+				Str("correlationId", requestID).
+				Dict("http", zerolog.Dict().
+					Dict("request", zerolog.Dict().
+						Str("path", requestPath).
+						Str("method", http.MethodGet).
+						Str("useragent", userAgent),
+					).
+					Dict("response", zerolog.Dict().
+						Int("statusCode", http.StatusOK).
+						Int("bytes", len(response)),
+					),
+				).
+				Msg("Completed Request")
+		}
+	})
+}
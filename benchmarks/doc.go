@@ -0,0 +1,8 @@
+// Package benchmarks compares the glogger middleware and formatter against zap and
+// zerolog equivalents logging the same fields on identical workloads.
+//
+// It is a separate module, so that the benchmarked loggers are not dependencies of
+// glogger. Run it from this directory with:
+//
+//	go test -bench . -benchmem
+package benchmarks
@@ -0,0 +1,21 @@
+module github.com/platform-horizon/glogger/benchmarks
+
+go 1.23
+
+require (
+	github.com/platform-horizon/glogger v0.0.0-20261015113308-5ab562e2975f
+	github.com/rs/zerolog v1.33.0
+	github.com/sirupsen/logrus v1.7.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/gorilla/mux v1.8.0 // indirect
+	github.com/klauspost/compress v1.17.0 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/pires/go-proxyproto v0.7.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
@@ -0,0 +1,104 @@
+package benchmarks
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newZapLogger returns a zap logger writing JSON entries to w, with the glogger field names.
+func newZapLogger(w io.Writer) *zap.Logger {
+	config := zap.NewProductionEncoderConfig()
+	config.MessageKey = "message"
+	config.TimeKey = "time"
+	config.EncodeTime = zapcore.EpochTimeEncoder
+	config.CallerKey = ""
+	config.StacktraceKey = ""
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.AddSync(w), zapcore.InfoLevel)
+	return zap.New(core)
+}
+
+// zapMiddleware logs the completed requests with the same fields of the glogger middleware.
+func zapMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			writer := statusWriter{ResponseWriter: rw}
+
+			next.ServeHTTP(&writer, r)
+
+			logger.Info("Completed Request",
+				zap.String("correlationId", r.Header.Get("X-Request-Id")),
+				zap.Dict("http",
+					zap.Dict("request",
+						zap.String("path", r.URL.RequestURI()),
+						zap.String("method", r.Method),
+						zap.String("scheme", "http"),
+						zap.String("protocol", r.Proto),
+						zap.String("useragent", r.UserAgent()),
+					),
+					zap.Dict("response",
+						zap.Int("statusCode", writer.statusCode),
+						zap.Int64("responseTime", time.Since(start).Milliseconds()),
+						zap.Int("bytes", writer.length),
+						zap.String("content-type", rw.Header().Get("Content-Type")),
+					),
+				),
+				zap.Dict("host",
+					zap.String("hostname", r.Host),
+					zap.String("ip", r.RemoteAddr),
+				),
+			)
+		})
+	}
+}
+
+// newZerologLogger returns a zerolog logger writing JSON entries to w, with the glogger field names.
+func newZerologLogger(w io.Writer) zerolog.Logger {
+	return zerolog.New(w).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+}
+
+// zerologMiddleware logs the completed requests with the same fields of the glogger middleware.
+func zerologMiddleware(logger zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			writer := statusWriter{ResponseWriter: rw}
+
+			next.ServeHTTP(&writer, r)
+
+			logger.Info().
+				Str("correlationId", r.Header.Get("X-Request-Id")).
+				Dict("http", zerolog.Dict().
+					Dict("request", zerolog.Dict().
+						Str("path", r.URL.RequestURI()).
+						Str("method", r.Method).
+						Str("scheme", "http").
+						Str("protocol", r.Proto).
+						Str("useragent", r.UserAgent()),
+					).
+					Dict("response", zerolog.Dict().
+						Int("statusCode", writer.statusCode).
+						Int64("responseTime", time.Since(start).Milliseconds()).
+						Int("bytes", writer.length).
+						Str("content-type", rw.Header().Get("Content-Type")),
+					),
+				).
+				Dict("host", zerolog.Dict().
+					Str("hostname", r.Host).
+					Str("ip", r.RemoteAddr),
+				).
+				Msg("Completed Request")
+		})
+	}
+}
+
+func init() {
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	zerolog.MessageFieldName = "message"
+}
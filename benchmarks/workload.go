@@ -0,0 +1,75 @@
+package benchmarks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+const (
+	requestPath = "/api/orders/42?expand=items"
+	userAgent   = "benchmark/1.0"
+	requestID   = "4f0a2f1e-6d1b-4c1a-9e53-0b8d2f7c9e11"
+)
+
+// response is the body written by the benchmarked handler.
+var response = []byte(`{"id":42,"status":"shipped"}`)
+
+// handler is the benchmarked handler, writing the same response for every request.
+var handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(response)
+})
+
+// newRequest returns the benchmarked request.
+func newRequest() *http.Request {
+	r := httptest.NewRequest(http.MethodGet, requestPath, nil)
+	r.Header.Set("User-Agent", userAgent)
+	r.Header.Set("X-Request-Id", requestID)
+	return r
+}
+
+// discardResponseWriter is an allocation free http.ResponseWriter.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func newDiscardResponseWriter() *discardResponseWriter {
+	return &discardResponseWriter{header: http.Header{}}
+}
+
+func (rw *discardResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (rw *discardResponseWriter) WriteHeader(statusCode int) {}
+
+// statusWriter records the status code and the length of the response, as the glogger
+// middleware does.
+type statusWriter struct {
+	http.ResponseWriter
+	statusCode int
+	length     int
+}
+
+func (rw *statusWriter) WriteHeader(statusCode int) {
+	rw.statusCode = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *statusWriter) Write(b []byte) (int, error) {
+	if rw.statusCode == 0 {
+		rw.statusCode = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.length += n
+	return n, err
+}
+
+// fixedTime is the time of the entries logged by the formatter benchmarks.
+var fixedTime = time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
@@ -0,0 +1,98 @@
+package glogger
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"unicode/utf8"
+)
+
+var defaultCapturedContentTypes = []string{"application/json", "text/*"}
+
+// BodyCaptureOptions is the struct of options to capture the request and response bodies
+type BodyCaptureOptions struct {
+	// MaxBytes is the maximum number of bytes captured for each body. Bodies longer
+	// than MaxBytes are truncated. Body capture is disabled if zero.
+	MaxBytes int
+	// ContentTypes are the media type patterns (as in path.Match) of the captured bodies.
+	// Defaults to application/json and text/*.
+	ContentTypes []string
+}
+
+func (options BodyCaptureOptions) newCapture(header http.Header) *bodyCapture {
+	if options.MaxBytes <= 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get(contentTypeKey))
+
+	if err != nil {
+		return nil
+	}
+
+	contentTypes := options.ContentTypes
+
+	if len(contentTypes) == 0 {
+		contentTypes = defaultCapturedContentTypes
+	}
+
+	for _, pattern := range contentTypes {
+		if matched, _ := path.Match(pattern, mediaType); matched {
+			return &bodyCapture{limit: options.MaxBytes}
+		}
+	}
+
+	return nil
+}
+
+// bodyCapture records up to limit bytes of a body.
+type bodyCapture struct {
+	limit     int
+	buffer    bytes.Buffer
+	truncated bool
+}
+
+func (capture *bodyCapture) write(b []byte) {
+	if capture.truncated {
+		return
+	}
+
+	remaining := capture.limit - capture.buffer.Len()
+
+	if len(b) <= remaining {
+		capture.buffer.Write(b)
+		return
+	}
+
+	capture.buffer.Write(b[:remaining])
+	capture.truncated = true
+
+	// The body is cut at a rune boundary, so that the captured text is valid UTF-8.
+	data := capture.buffer.Bytes()
+
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				capture.buffer.Truncate(i)
+			}
+
+			break
+		}
+	}
+}
+
+// capturingReader records the bytes read by the handler from the request body,
+// without reading anything more than the handler does.
+type capturingReader struct {
+	io.ReadCloser
+	capture *bodyCapture
+}
+
+func (reader *capturingReader) Read(p []byte) (int, error) {
+	n, err := reader.ReadCloser.Read(p)
+	reader.capture.write(p[:n])
+
+	return n, err
+}
@@ -0,0 +1,76 @@
+package glogger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestBodyCapture(t *testing.T) {
+	t.Run("Bodies are truncated at a rune boundary", func(t *testing.T) {
+		capture := &bodyCapture{limit: 8}
+		capture.write([]byte("caf"))
+		capture.write([]byte("é crème"))
+
+		assert.Equal(t, capture.buffer.String(), "café cr")
+		assert.Assert(t, capture.truncated)
+
+		capture = &bodyCapture{limit: 4}
+		capture.write([]byte("caf\xc3"))
+		capture.write([]byte("\xa9"))
+
+		assert.Equal(t, capture.buffer.String(), "caf")
+		assert.Assert(t, utf8.Valid(capture.buffer.Bytes()))
+		assert.Assert(t, capture.truncated)
+
+		capture = &bodyCapture{limit: 4}
+		capture.write([]byte("café"))
+
+		assert.Equal(t, capture.buffer.String(), "caf")
+	})
+
+	t.Run("Bodies are captured by content type", func(t *testing.T) {
+		cases := map[string]bool{
+			"application/json; charset=utf-8": true,
+			"text/plain":                      true,
+			"image/png":                       false,
+			"":                                false,
+		}
+
+		for contentType, captured := range cases {
+			header := http.Header{contentTypeKey: {contentType}}
+			assert.Equal(t, BodyCaptureOptions{MaxBytes: 64}.newCapture(header) != nil, captured, contentType)
+		}
+
+		header := http.Header{contentTypeKey: {"application/xml"}}
+		assert.Assert(t, BodyCaptureOptions{MaxBytes: 64, ContentTypes: []string{"application/*"}}.newCapture(header) != nil)
+		assert.Assert(t, BodyCaptureOptions{}.newCapture(header) == nil)
+	})
+
+	t.Run("Request and response bodies are logged", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{BodyCapture: BodyCaptureOptions{MaxBytes: 16}}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			io.ReadAll(r.Body)
+			rw.Header().Set("Content-Type", "application/json")
+			rw.Write([]byte(`{"name":"Crème brûlée"}`))
+		}))
+
+		request := httptest.NewRequest(http.MethodPost, "/desserts", strings.NewReader(`{"name":"Tarte"}`))
+		request.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		httpField := hook.LastEntry().Data["http"].(HTTP)
+		assert.Equal(t, httpField.Request.Body, `{"name":"Tarte"}`)
+		assert.Assert(t, !httpField.Request.BodyTruncated)
+		assert.Equal(t, httpField.Response.Body, `{"name":"Crème `)
+		assert.Assert(t, httpField.Response.BodyTruncated)
+	})
+}
@@ -0,0 +1,44 @@
+package glogger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+)
+
+// hashingReader hashes the bytes read by the handler from the request body, without
+// reading anything more than the handler does.
+type hashingReader struct {
+	io.ReadCloser
+	hash hash.Hash
+	size int64
+	eof  bool
+}
+
+func newHashingReader(body io.ReadCloser) *hashingReader {
+	return &hashingReader{ReadCloser: body, hash: sha256.New()}
+}
+
+func (reader *hashingReader) Read(p []byte) (int, error) {
+	n, err := reader.ReadCloser.Read(p)
+	reader.hash.Write(p[:n])
+	reader.size += int64(n)
+
+	if errors.Is(err, io.EOF) {
+		reader.eof = true
+	}
+
+	return n, err
+}
+
+// sum returns the hex SHA-256 of the body, or an empty string if the body is empty or
+// was not read to its end by the handler.
+func (reader *hashingReader) sum() string {
+	if !reader.eof || reader.size == 0 {
+		return ""
+	}
+
+	return hex.EncodeToString(reader.hash.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package glogger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestBodyHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     io.Reader
+		read     func(body io.Reader)
+		expected string
+	}{
+		{
+			name:     "Body read to its end is hashed",
+			body:     strings.NewReader(`{"amount":100}`),
+			read:     func(body io.Reader) { io.ReadAll(body) },
+			expected: "4d4bbe59c6aad22442cde199a6a8a5f034405fcd78fb5a81c24ef249de1c45f1",
+		},
+		{
+			name: "Body partially read is not hashed",
+			body: strings.NewReader(`{"amount":100}`),
+			read: func(body io.Reader) { body.Read(make([]byte, 4)) },
+		},
+		{
+			name: "Empty body is not hashed",
+			body: strings.NewReader(""),
+			read: func(body io.Reader) { io.ReadAll(body) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+
+			handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{BodyHash: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				tt.read(r.Body)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, defaultRequestPath, tt.body))
+
+			assert.Equal(t, hook.LastEntry().Data["http"].(HTTP).Request.BodySHA256, tt.expected)
+		})
+	}
+}
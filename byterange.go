@@ -0,0 +1,120 @@
+package glogger
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	rangeKey        = "Range"
+	contentRangeKey = "Content-Range"
+)
+
+// ByteRange struct contains items of a single requested range info log.
+// Suffix is set for ranges selecting the last bytes of the representation (e.g. "-500").
+type ByteRange struct {
+	Start  *int64 `json:"start,omitempty"`
+	End    *int64 `json:"end,omitempty"`
+	Suffix *int64 `json:"suffix,omitempty"`
+}
+
+// Range struct contains items of the Range request header info log.
+type Range struct {
+	Unit   string      `json:"unit,omitempty"`
+	Ranges []ByteRange `json:"ranges,omitempty"`
+	Raw    string      `json:"raw,omitempty"`
+}
+
+// ContentRange struct contains items of the Content-Range response header info log.
+// Start and End are omitted for unsatisfied ranges, Size when the length is unknown.
+type ContentRange struct {
+	Unit  string `json:"unit,omitempty"`
+	Start *int64 `json:"start,omitempty"`
+	End   *int64 `json:"end,omitempty"`
+	Size  *int64 `json:"size,omitempty"`
+}
+
+func parseOffset(value string) *int64 {
+	offset, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+
+	if err != nil {
+		return nil
+	}
+
+	return &offset
+}
+
+// parseRange parses a Range header such as "bytes=0-499,-500". Malformed ranges
+// are kept only in the raw value.
+func parseRange(header string) *Range {
+	if header == "" {
+		return nil
+	}
+
+	result := &Range{Raw: header}
+	parts := strings.SplitN(header, "=", 2)
+
+	if len(parts) != 2 {
+		return result
+	}
+
+	result.Unit = strings.TrimSpace(parts[0])
+
+	for _, spec := range strings.Split(parts[1], ",") {
+		bounds := strings.SplitN(strings.TrimSpace(spec), "-", 2)
+
+		if len(bounds) != 2 {
+			continue
+		}
+
+		start, end := bounds[0], bounds[1]
+
+		if start == "" {
+			if suffix := parseOffset(end); suffix != nil {
+				result.Ranges = append(result.Ranges, ByteRange{Suffix: suffix})
+			}
+			continue
+		}
+
+		byteRange := ByteRange{Start: parseOffset(start)}
+
+		if byteRange.Start == nil {
+			continue
+		}
+
+		if end != "" {
+			byteRange.End = parseOffset(end)
+		}
+
+		result.Ranges = append(result.Ranges, byteRange)
+	}
+
+	return result
+}
+
+// parseContentRange parses a Content-Range header such as "bytes 0-499/1234" or "bytes */1234".
+func parseContentRange(header string) *ContentRange {
+	if header == "" {
+		return nil
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+
+	if len(parts) != 2 {
+		return nil
+	}
+
+	result := &ContentRange{Unit: parts[0]}
+	values := strings.SplitN(parts[1], "/", 2)
+
+	if bounds := strings.SplitN(values[0], "-", 2); len(bounds) == 2 {
+		result.Start = parseOffset(bounds[0])
+		result.End = parseOffset(bounds[1])
+	}
+
+	if len(values) == 2 && values[1] != "*" {
+		result.Size = parseOffset(values[1])
+	}
+
+	return result
+}
@@ -0,0 +1,59 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	t.Run("Multiple ranges are parsed", func(t *testing.T) {
+		result := parseRange("bytes=0-499, 1000-, -500")
+
+		assert.Equal(t, result.Unit, "bytes")
+		assert.Equal(t, len(result.Ranges), 3, "Unexpected number of ranges")
+		assert.Equal(t, *result.Ranges[0].Start, int64(0))
+		assert.Equal(t, *result.Ranges[0].End, int64(499))
+		assert.Equal(t, *result.Ranges[1].Start, int64(1000))
+		assert.Assert(t, result.Ranges[1].End == nil, "Unexpected end for open range")
+		assert.Equal(t, *result.Ranges[2].Suffix, int64(500))
+	})
+
+	t.Run("Malformed ranges are kept raw", func(t *testing.T) {
+		result := parseRange("garbage")
+
+		assert.Equal(t, result.Raw, "garbage")
+		assert.Equal(t, len(result.Ranges), 0, "Unexpected ranges")
+	})
+
+	t.Run("Unsatisfied content range has no bounds", func(t *testing.T) {
+		result := parseContentRange("bytes */1234")
+
+		assert.Assert(t, result.Start == nil, "Unexpected start")
+		assert.Equal(t, *result.Size, int64(1234))
+	})
+}
+
+func TestRangeFields(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+	request.Header.Set("Range", "bytes=0-3")
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.ServeContent(rw, r, "file.txt", time.Time{}, strings.NewReader("0123456789"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	httpField := hook.LastEntry().Data["http"].(HTTP)
+
+	assert.Equal(t, *httpField.Request.Range.Ranges[0].End, int64(3))
+	assert.Assert(t, httpField.Response.Partial, "Unexpected response not marked as partial")
+	assert.Equal(t, *httpField.Response.ContentRange.Start, int64(0))
+	assert.Equal(t, *httpField.Response.ContentRange.End, int64(3))
+	assert.Equal(t, *httpField.Response.ContentRange.Size, int64(10))
+}
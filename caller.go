@@ -0,0 +1,92 @@
+package glogger
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	callerKey      = "caller"
+	gloggerPackage = "github.com/platform-horizon/glogger"
+	logrusPackage  = "github.com/sirupsen/logrus"
+	maxCallerDepth = 32
+)
+
+// CallerHook is a logrus hook adding the caller of the logging function to every entry,
+// with its file, line and function, and optionally the ID of the logging goroutine. The
+// frames of logrus and glogger, subpackages included, are skipped, so that the caller is
+// the code calling them, such as a handler calling glogger.Error. It is costly and meant
+// for debugging.
+type CallerHook struct {
+	goroutineID bool
+}
+
+// NewCallerHook returns a CallerHook. Getting the goroutine ID requires a stack trace on
+// every entry.
+func NewCallerHook(goroutineID bool) *CallerHook {
+	return &CallerHook{goroutineID: goroutineID}
+}
+
+// Levels returns the levels of the entries enriched by the hook, which are all of them.
+func (hook *CallerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the caller field to the entry.
+func (hook *CallerHook) Fire(entry *logrus.Entry) error {
+	data := make(logrus.Fields, len(entry.Data)+2)
+
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+
+	if caller, ok := currentCaller(); ok {
+		data[callerKey] = caller
+	}
+
+	if hook.goroutineID {
+		data[goroutineIDKey] = currentGoroutineID()
+	}
+
+	entry.Data = data
+
+	return nil
+}
+
+// currentCaller returns the first frame of the stack outside of logrus and glogger,
+// including their subpackages, such as grpclogger.
+func currentCaller() (StackFrame, bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+
+		if pkg := framePackage(frame.Function); !isPackageOf(pkg, logrusPackage) && !isPackageOf(pkg, gloggerPackage) {
+			return StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line}, true
+		}
+
+		if !more {
+			return StackFrame{}, false
+		}
+	}
+}
+
+// isPackageOf returns true if the package is the module package or one of its subpackages.
+func isPackageOf(pkg string, module string) bool {
+	return pkg == module || strings.HasPrefix(pkg, module+"/")
+}
+
+// framePackage returns the package path of a function name, such as
+// "github.com/sirupsen/logrus" for "github.com/sirupsen/logrus.(*Entry).Info".
+func framePackage(function string) string {
+	slash := strings.LastIndexByte(function, '/') + 1
+
+	if dot := strings.IndexByte(function[slash:], '.'); dot >= 0 {
+		return function[:slash+dot]
+	}
+
+	return function
+}
@@ -0,0 +1,21 @@
+package glogger
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestFramePackage(t *testing.T) {
+	assert.Equal(t, framePackage("github.com/sirupsen/logrus.(*Entry).Info"), "github.com/sirupsen/logrus")
+	assert.Equal(t, framePackage("github.com/platform-horizon/glogger.Error"), "github.com/platform-horizon/glogger")
+	assert.Equal(t, framePackage("github.com/platform-horizon/glogger/grpclogger.UnaryServerInterceptor.func1"), "github.com/platform-horizon/glogger/grpclogger")
+	assert.Equal(t, framePackage("main.main"), "main")
+}
+
+func TestIsPackageOf(t *testing.T) {
+	assert.Assert(t, isPackageOf("github.com/platform-horizon/glogger", gloggerPackage))
+	assert.Assert(t, isPackageOf("github.com/platform-horizon/glogger/grpclogger", gloggerPackage))
+	assert.Assert(t, !isPackageOf("github.com/platform-horizon/glogger_test", gloggerPackage))
+	assert.Assert(t, !isPackageOf("github.com/platform-horizon/gloggerx", gloggerPackage))
+}
@@ -0,0 +1,37 @@
+package glogger_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+// The caller hook is tested from another package, since the frames of the glogger
+// package are skipped.
+func TestCallerHook(t *testing.T) {
+	logger, err := glogger.Init(glogger.InitOptions{Output: io.Discard, Caller: true, GoroutineID: true})
+	assert.NilError(t, err)
+	hook := test.NewLocal(logger)
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.WithField("user", "alice").Info("Entry")
+
+	caller := hook.LastEntry().Data["caller"].(glogger.StackFrame)
+	assert.Equal(t, caller.File, file)
+	assert.Equal(t, caller.Line, line+1)
+	assert.Equal(t, caller.Function, "github.com/platform-horizon/glogger_test.TestCallerHook")
+	assert.Assert(t, hook.LastEntry().Data["goroutineId"] != nil)
+
+	ctx := glogger.WithLogger(context.Background(), logger.WithField("correlationId", "id"))
+
+	_, _, line, _ = runtime.Caller(0)
+	glogger.Error(ctx, errors.New("failed"), "Failed")
+
+	assert.Equal(t, hook.LastEntry().Data["caller"].(glogger.StackFrame).Line, line+1)
+}
@@ -0,0 +1,21 @@
+module github.com/platform-horizon/glogger/chilogger
+
+go 1.21
+
+require (
+	github.com/go-chi/chi/v5 v5.1.0
+	github.com/platform-horizon/glogger v0.0.0-20261015113308-5ab562e2975f
+	github.com/sirupsen/logrus v1.7.0
+	gotest.tools v2.2.0+incompatible
+)
+
+require (
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/gorilla/mux v1.8.0 // indirect
+	github.com/klauspost/compress v1.17.0 // indirect
+	github.com/pires/go-proxyproto v0.7.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+)
@@ -0,0 +1,31 @@
+// Package chilogger provides a chi middleware logging the requests with the glogger
+// middleware, including the matched chi route pattern.
+package chilogger
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+)
+
+// RoutePattern returns the chi route pattern matched by the request, such as /users/{id}.
+func RoutePattern(r *http.Request) string {
+	if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+		return routeContext.RoutePattern()
+	}
+
+	return ""
+}
+
+// Logger returns a chi middleware logging the requests, like glogger.LoggingMiddlewareWithOptions.
+// The chi route pattern is logged in http.request.route unless options.RoutePattern is set.
+// Use it with Router.Use, so that the route pattern is resolved once the handler returned.
+func Logger(logger *logrus.Logger, options glogger.MiddlewareOptions) func(http.Handler) http.Handler {
+	if options.RoutePattern == nil {
+		options.RoutePattern = RoutePattern
+	}
+
+	return glogger.LoggingMiddlewareWithOptions(logger, options)
+}
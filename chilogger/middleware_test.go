@@ -0,0 +1,34 @@
+package chilogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLogger(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	router := chi.NewRouter()
+	router.Use(Logger(logger, glogger.MiddlewareOptions{}))
+	router.Get("/users/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		glogger.Get(r.Context()).Info("Handling Request")
+		rw.WriteHeader(http.StatusAccepted)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	entries := hook.AllEntries()
+	assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+	assert.Equal(t, entries[0].Data["correlationId"], entries[1].Data["correlationId"])
+
+	httpField := entries[1].Data["http"].(glogger.HTTP)
+	assert.Equal(t, httpField.Request.Path, "/users/42")
+	assert.Equal(t, httpField.Request.Route, "/users/{id}")
+	assert.Equal(t, httpField.Response.StatusCode, 202)
+}
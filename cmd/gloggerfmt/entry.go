@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+const maxLineSize = 1 << 20
+
+// entry is a log entry written by the glogger JSONFormatter.
+type entry struct {
+	Time          time.Time
+	Level         string
+	Message       string
+	CorrelationID string
+	RequestSeq    int64
+	HTTP          *httpInfo
+	Fields        map[string]interface{}
+}
+
+type httpInfo struct {
+	Request *struct {
+		Path   string `json:"path"`
+		Method string `json:"method"`
+	} `json:"request"`
+	Response *struct {
+		StatusCode   int     `json:"statusCode"`
+		ResponseTime float64 `json:"responseTime"`
+	} `json:"response"`
+}
+
+// parseTime parses the time field, written as unix seconds or as a string in RFC 3339 format.
+func parseTime(value interface{}) time.Time {
+	switch value := value.(type) {
+	case float64:
+		seconds := int64(value)
+		return time.Unix(seconds, int64((value-float64(seconds))*float64(time.Second)))
+	case string:
+		result, _ := time.Parse(time.RFC3339Nano, value)
+		return result
+	default:
+		return time.Time{}
+	}
+}
+
+func parseEntry(line []byte) (entry, error) {
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return entry{}, err
+	}
+
+	result := entry{Fields: fields, Time: parseTime(fields["time"])}
+	result.Level, _ = fields["level"].(string)
+	result.Message, _ = fields["message"].(string)
+	result.CorrelationID, _ = fields["correlationId"].(string)
+
+	if seq, ok := fields["requestSeq"].(float64); ok {
+		result.RequestSeq = int64(seq)
+	}
+
+	if raw, ok := fields["http"]; ok {
+		data, _ := json.Marshal(raw)
+		json.Unmarshal(data, &result.HTTP)
+	}
+
+	return result, nil
+}
+
+// readEntries reads the entries of a newline-delimited JSON stream. Lines which are
+// not JSON objects are skipped.
+func readEntries(r io.Reader, fn func(entry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		if parsed, err := parseEntry(scanner.Bytes()); err == nil {
+			fn(parsed)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/platform-horizon/glogger"
+)
+
+// subjects is the flag of the subjects, repeated.
+type subjects []string
+
+func (s *subjects) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *subjects) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func runErase(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("erase", flag.ContinueOnError)
+	var values subjects
+	flags.Var(&values, "subject", "value identifying the subject, such as its user ID, email or pseudonym (repeatable)")
+	keyFile := flags.String("key-file", "", "file of the HMAC-SHA256 key of the Pseudonymizer, to erase the pseudonyms of the subject values too")
+	remove := flags.Bool("remove", false, "removes the matching entries instead of redacting the subject values")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(values) == 0 || flags.NArg() == 0 {
+		return errors.New("usage: gloggerfmt erase -subject <value> [-key-file <file>] [-remove] <file>...")
+	}
+
+	options := glogger.ErasureOptions{Subjects: values, Remove: *remove}
+
+	if *keyFile != "" {
+		key, err := os.ReadFile(*keyFile)
+
+		if err != nil {
+			return err
+		}
+
+		// The key is read like with glogger.FileSecret.
+		pseudonymizer := glogger.NewPseudonymizer(glogger.HMAC(sha256.New, bytes.TrimSpace(key)))
+
+		for _, value := range values {
+			options.Subjects = append(options.Subjects, pseudonymizer.Pseudonym(value))
+		}
+	}
+
+	for _, path := range flags.Args() {
+		erased, err := glogger.EraseSubject(path, options)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		fmt.Fprintf(stdout, "%s: %d entries\n", path, erased)
+	}
+
+	return nil
+}
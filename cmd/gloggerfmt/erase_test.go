@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"gotest.tools/assert"
+)
+
+func TestErase(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	assert.NilError(t, os.WriteFile(keyFile, []byte("secret\n"), 0o600))
+
+	pseudonymizer := glogger.NewPseudonymizer(glogger.HMAC(sha256.New, []byte("secret")))
+	pseudonym := pseudonymizer.Pseudonym("42")
+	archive := filepath.Join(dir, "app.log")
+	assert.NilError(t, os.WriteFile(archive, []byte(`{"level":"info","message":"Logged in","userId":"`+pseudonym+`"}
+{"level":"info","message":"Logged in","userId":"`+pseudonymizer.Pseudonym("43")+`"}
+`), 0o644))
+
+	var output bytes.Buffer
+
+	err := runErase([]string{"-subject", "42", "-key-file", keyFile, "-remove", archive}, nil, &output)
+	assert.NilError(t, err)
+	assert.Equal(t, output.String(), archive+": 1 entries\n")
+
+	content, err := os.ReadFile(archive)
+	assert.NilError(t, err)
+	assert.Assert(t, !bytes.Contains(content, []byte(pseudonym)), string(content))
+	assert.Equal(t, bytes.Count(content, []byte("\n")), 1)
+
+	err = runErase([]string{archive}, nil, &output)
+	assert.ErrorContains(t, err, "usage: gloggerfmt erase")
+}
@@ -0,0 +1,78 @@
+// Command gloggerfmt analyzes the newline-delimited JSON logs written by glogger.
+//
+// Usage:
+//
+//	gloggerfmt <command> [flags] [file]
+//
+// The logs are read from the file or, if omitted, from the standard input.
+// Commands:
+//
+//	timeline  prints the entries grouped by correlation ID, as per-request timelines
+//	stats     summarizes the entries: levels, status codes, routes, latency and errors
+//	erase     erases a data subject from log archives, rewriting them in place
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	description string
+	run         func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"timeline": {
+		description: "prints the entries grouped by correlation ID, as per-request timelines",
+		run:         runTimeline,
+	},
+	"stats": {
+		description: "summarizes the entries: levels, status codes, routes, latency and errors",
+		run:         runStats,
+	},
+	"erase": {
+		description: "erases a data subject from log archives, rewriting them in place",
+		run:         runErase,
+	},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gloggerfmt <command> [flags] [file]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+
+	for _, name := range []string{"timeline", "stats", "erase"} {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].description)
+	}
+}
+
+// openInput returns the file named by the first argument, or stdin if there are no arguments.
+func openInput(args []string, stdin io.Reader) (io.ReadCloser, error) {
+	if len(args) == 0 || args[0] == "-" {
+		return io.NopCloser(stdin), nil
+	}
+
+	return os.Open(args[0])
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	fingerprintUUIDRegexp   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	fingerprintHexRegexp    = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b|\b[0-9a-fA-F]{16,}\b`)
+	fingerprintNumberRegexp = regexp.MustCompile(`\d+`)
+)
+
+type count struct {
+	key   string
+	value int
+}
+
+type counter map[string]int
+
+// top returns the n keys with the highest counts, sorted by count and then by key.
+func (c counter) top(n int) []count {
+	result := make([]count, 0, len(c))
+
+	for key, value := range c {
+		result = append(result, count{key: key, value: value})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].value != result[j].value {
+			return result[i].value > result[j].value
+		}
+
+		return result[i].key < result[j].key
+	})
+
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+
+	return result
+}
+
+type statistics struct {
+	entries      int
+	levels       counter
+	routes       counter
+	statuses     counter
+	fingerprints counter
+	latencies    []float64
+}
+
+// fingerprint returns the message and error of an entry with the variable parts
+// (UUIDs, hexadecimal IDs and numbers) replaced, to group the occurrences of the same error.
+func fingerprint(e entry) string {
+	result := e.Message
+
+	if err, ok := e.Fields["error"].(string); ok && err != "" {
+		result += ": " + err
+	}
+
+	result = fingerprintUUIDRegexp.ReplaceAllString(result, "<uuid>")
+	result = fingerprintHexRegexp.ReplaceAllString(result, "<hex>")
+
+	return fingerprintNumberRegexp.ReplaceAllString(result, "<n>")
+}
+
+func isErrorLevel(level string) bool {
+	return level == "error" || level == "fatal" || level == "panic"
+}
+
+func (s *statistics) add(e entry) {
+	s.entries++
+	s.levels[e.Level]++
+
+	if isErrorLevel(e.Level) {
+		s.fingerprints[fingerprint(e)]++
+	}
+
+	if e.HTTP == nil || e.HTTP.Request == nil || e.HTTP.Response == nil {
+		return
+	}
+
+	path := e.HTTP.Request.Path
+
+	if index := strings.Index(path, "?"); index >= 0 {
+		path = path[:index]
+	}
+
+	s.routes[e.HTTP.Request.Method+" "+path]++
+	s.statuses[fmt.Sprint(e.HTTP.Response.StatusCode)]++
+	s.latencies = append(s.latencies, e.HTTP.Response.ResponseTime)
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func formatSeconds(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Microsecond)
+}
+
+func printCounts(w io.Writer, title string, counts []count) {
+	fmt.Fprintf(w, "%s:\n", title)
+
+	if len(counts) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+
+	for _, c := range counts {
+		fmt.Fprintf(w, "  %8d  %s\n", c.value, c.key)
+	}
+}
+
+func (s *statistics) print(w io.Writer, top int) {
+	fmt.Fprintf(w, "Entries: %d\n\n", s.entries)
+	printCounts(w, "Levels", s.levels.top(0))
+	fmt.Fprintln(w)
+	printCounts(w, "Status codes", s.statuses.top(0))
+	fmt.Fprintln(w)
+	printCounts(w, "Top routes", s.routes.top(top))
+	fmt.Fprintln(w)
+
+	sort.Float64s(s.latencies)
+	fmt.Fprintln(w, "Latency:")
+
+	if len(s.latencies) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		fmt.Fprintf(w, "  p50 %s  p90 %s  p99 %s  max %s\n",
+			formatSeconds(percentile(s.latencies, 50)),
+			formatSeconds(percentile(s.latencies, 90)),
+			formatSeconds(percentile(s.latencies, 99)),
+			formatSeconds(s.latencies[len(s.latencies)-1]))
+	}
+
+	fmt.Fprintln(w)
+	printCounts(w, "Top errors", s.fingerprints.top(top))
+}
+
+func runStats(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	top := flags.Int("top", 10, "number of routes and errors printed")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	input, err := openInput(flags.Args(), stdin)
+
+	if err != nil {
+		return err
+	}
+
+	defer input.Close()
+
+	s := &statistics{
+		levels:       counter{},
+		routes:       counter{},
+		statuses:     counter{},
+		fingerprints: counter{},
+	}
+
+	if err := readEntries(input, s.add); err != nil {
+		return err
+	}
+
+	s.print(stdout, *top)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const statsLogs = `{"http":{"request":{"method":"GET","path":"/users?page=1"},"response":{"statusCode":200,"responseTime":0.01}},"level":"info","message":"Completed Request"}
+{"http":{"request":{"method":"GET","path":"/users?page=2"},"response":{"statusCode":200,"responseTime":0.02}},"level":"info","message":"Completed Request"}
+{"http":{"request":{"method":"POST","path":"/orders"},"response":{"statusCode":500,"responseTime":1.5}},"level":"error","message":"Completed Request"}
+{"level":"error","message":"Query failed","error":"timeout after 30s on shard 3"}
+{"level":"error","message":"Query failed","error":"timeout after 31s on shard 7"}
+{"level":"trace","message":"Incoming Request"}
+`
+
+func TestStats(t *testing.T) {
+	var output bytes.Buffer
+
+	err := runStats([]string{"-top", "1"}, strings.NewReader(statsLogs), &output)
+	assert.Assert(t, err == nil, "Unexpected error")
+
+	expected := `Entries: 6
+
+Levels:
+         3  error
+         2  info
+         1  trace
+
+Status codes:
+         2  200
+         1  500
+
+Top routes:
+         2  GET /users
+
+Latency:
+  p50 20ms  p90 1.5s  p99 1.5s  max 1.5s
+
+Top errors:
+         2  Query failed: timeout after <n>s on shard <n>
+`
+	assert.Equal(t, output.String(), expected)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	assert.Equal(t, percentile(values, 50), float64(5))
+	assert.Equal(t, percentile(values, 90), float64(9))
+	assert.Equal(t, percentile(values, 99), float64(10))
+	assert.Equal(t, percentile(nil, 50), float64(0))
+}
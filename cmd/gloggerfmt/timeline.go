@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/platform-horizon/glogger"
+)
+
+type timeline struct {
+	correlationID string
+	entries       []entry
+}
+
+func (t *timeline) duration() time.Duration {
+	return t.entries[len(t.entries)-1].Time.Sub(t.entries[0].Time)
+}
+
+func summary(e entry) string {
+	if e.HTTP == nil || e.HTTP.Request == nil {
+		return ""
+	}
+
+	result := e.HTTP.Request.Method + " " + e.HTTP.Request.Path
+
+	if response := e.HTTP.Response; response != nil {
+		result += fmt.Sprintf(" %d %s", response.StatusCode, time.Duration(response.ResponseTime*float64(time.Second)).Round(time.Microsecond))
+	}
+
+	return result
+}
+
+// buildTimelines groups the entries by correlation ID, in order of first appearance,
+// keeping only the correlation ID, or the IDs generated by the service, if not empty.
+// The entries of each timeline are sorted by requestSeq when available, by time otherwise,
+// keeping the input order for equal times.
+func buildTimelines(r io.Reader, correlationID, service string) ([]*timeline, error) {
+	var result []*timeline
+	byID := map[string]*timeline{}
+
+	err := readEntries(r, func(e entry) {
+		if e.CorrelationID == "" || (correlationID != "" && e.CorrelationID != correlationID) {
+			return
+		}
+
+		if service != "" {
+			if prefix, _, ok := glogger.ParseRequestID(e.CorrelationID); !ok || prefix != service {
+				return
+			}
+		}
+
+		t, ok := byID[e.CorrelationID]
+
+		if !ok {
+			t = &timeline{correlationID: e.CorrelationID}
+			byID[e.CorrelationID] = t
+			result = append(result, t)
+		}
+
+		t.entries = append(t.entries, e)
+	})
+
+	for _, t := range result {
+		sort.SliceStable(t.entries, func(i, j int) bool {
+			if t.entries[i].RequestSeq > 0 && t.entries[j].RequestSeq > 0 {
+				return t.entries[i].RequestSeq < t.entries[j].RequestSeq
+			}
+
+			return t.entries[i].Time.Before(t.entries[j].Time)
+		})
+	}
+
+	return result, err
+}
+
+func printTimeline(w io.Writer, t *timeline) {
+	fmt.Fprintf(w, "%s (%d entries, %s)\n", t.correlationID, len(t.entries), t.duration())
+
+	start := t.entries[0].Time
+
+	for _, e := range t.entries {
+		line := fmt.Sprintf("  +%-10s %-7s %s", e.Time.Sub(start), e.Level, e.Message)
+
+		if s := summary(e); s != "" {
+			line += "  " + s
+		}
+
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+func runTimeline(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("timeline", flag.ContinueOnError)
+	correlationID := flags.String("id", "", "prints only the timeline of the request with this correlation ID")
+	service := flags.String("service", "", "prints only the timelines of the requests whose ID was generated by the service with this code")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *service != "" {
+		if _, err := glogger.ServiceIDGenerator(*service, nil); err != nil {
+			return err
+		}
+	}
+
+	input, err := openInput(flags.Args(), stdin)
+
+	if err != nil {
+		return err
+	}
+
+	defer input.Close()
+
+	timelines, err := buildTimelines(input, *correlationID, *service)
+
+	if err != nil {
+		return err
+	}
+
+	for i, t := range timelines {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		printTimeline(stdout, t)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const logs = `{"correlationId":"a","http":{"request":{"method":"GET","path":"/users"}},"level":"trace","message":"Incoming Request","time":1600000000}
+{"correlationId":"b","http":{"request":{"method":"POST","path":"/orders"}},"level":"trace","message":"Incoming Request","time":1600000001}
+not a json line
+{"correlationId":"a","level":"info","message":"Loading users","time":1600000001}
+{"level":"info","message":"Server started","time":1600000001}
+{"correlationId":"a","http":{"request":{"method":"GET","path":"/users"},"response":{"statusCode":200,"responseTime":2.5}},"level":"info","message":"Completed Request","time":1600000002}
+`
+
+func TestTimeline(t *testing.T) {
+	t.Run("Entries are grouped by correlation ID", func(t *testing.T) {
+		var output bytes.Buffer
+
+		err := runTimeline(nil, strings.NewReader(logs), &output)
+		assert.Assert(t, err == nil, "Unexpected error")
+
+		expected := `a (3 entries, 2s)
+  +0s         trace   Incoming Request  GET /users
+  +1s         info    Loading users
+  +2s         info    Completed Request  GET /users 200 2.5s
+
+b (1 entries, 0s)
+  +0s         trace   Incoming Request  POST /orders
+`
+		assert.Equal(t, output.String(), expected)
+	})
+
+	t.Run("Timeline can be filtered by correlation ID", func(t *testing.T) {
+		var output bytes.Buffer
+
+		err := runTimeline([]string{"-id", "b"}, strings.NewReader(logs), &output)
+		assert.Assert(t, err == nil, "Unexpected error")
+		assert.Assert(t, strings.HasPrefix(output.String(), "b (1 entries"), output.String())
+		assert.Assert(t, !strings.Contains(output.String(), "a ("), output.String())
+	})
+
+	t.Run("Timelines can be filtered by service", func(t *testing.T) {
+		var output bytes.Buffer
+
+		input := `{"correlationId":"bill_01ARZ3NDEKTSV4RRFFQ69G5FAV","level":"info","message":"Completed Request","time":1600000000}
+{"correlationId":"auth_01ARZ3NDEKTSV4RRFFQ69G5FAW","level":"info","message":"Completed Request","time":1600000000}
+{"correlationId":"a","level":"info","message":"Completed Request","time":1600000000}
+`
+
+		err := runTimeline([]string{"-service", "bill"}, strings.NewReader(input), &output)
+		assert.Assert(t, err == nil, "Unexpected error")
+		assert.Equal(t, output.String(), "bill_01ARZ3NDEKTSV4RRFFQ69G5FAV (1 entries, 0s)\n  +0s         info    Completed Request\n")
+
+		err = runTimeline([]string{"-service", "Bill"}, strings.NewReader(input), &output)
+		assert.ErrorContains(t, err, "invalid service code")
+	})
+
+	t.Run("Entries are ordered by request sequence", func(t *testing.T) {
+		var output bytes.Buffer
+
+		input := `{"correlationId":"c","level":"info","message":"Completed Request","requestSeq":3,"time":1600000000}
+{"correlationId":"c","level":"trace","message":"Incoming Request","requestSeq":1,"time":1600000000}
+{"correlationId":"c","level":"info","message":"Loading users","requestSeq":2,"time":1600000000}
+`
+
+		err := runTimeline(nil, strings.NewReader(input), &output)
+		assert.Assert(t, err == nil, "Unexpected error")
+
+		expected := `c (3 entries, 0s)
+  +0s         trace   Incoming Request
+  +0s         info    Loading users
+  +0s         info    Completed Request
+`
+		assert.Equal(t, output.String(), expected)
+	})
+}
@@ -0,0 +1,162 @@
+// Command gloggerinit scaffolds the main.go of a new service wired with glogger, so that
+// the services adopt its features consistently: Init with a profile and the selected
+// sinks, the logging middleware recovering the panics, the admin level handler and the
+// flush of the logs on graceful shutdown.
+//
+// Usage:
+//
+//	gloggerinit [-service <name>] [-profile <profile>] [-sinks <sinks>] [-o <file>]
+//
+// The sinks are a comma-separated list of stderr, file, syslog and otlp. The main.go is
+// written to the file or, if omitted, to the standard output.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/platform-horizon/glogger"
+)
+
+// Sinks of the generated main.go.
+const (
+	sinkStderr = "stderr"
+	sinkFile   = "file"
+	sinkSyslog = "syslog"
+	sinkOTLP   = "otlp"
+)
+
+var (
+	sinkNames   = []string{sinkStderr, sinkFile, sinkSyslog, sinkOTLP}
+	serviceName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	profiles    = map[string]string{
+		glogger.ProfileProduction:  "ProfileProduction",
+		glogger.ProfileDevelopment: "ProfileDevelopment",
+		glogger.ProfileTest:        "ProfileTest",
+	}
+)
+
+// config is the configuration of the generated main.go.
+type config struct {
+	Service string
+	// Profile is the name of the glogger constant of the profile.
+	Profile   string
+	Addr      string
+	AdminAddr string
+	Stderr    bool
+	File      bool
+	Syslog    bool
+	OTLP      bool
+}
+
+// Writers returns the expressions of the writers of the entries, in order of the sinks.
+func (c config) Writers() []string {
+	var writers []string
+
+	if c.Stderr {
+		writers = append(writers, "os.Stderr")
+	}
+
+	if c.File {
+		writers = append(writers, "fileWriter")
+	}
+
+	if c.Syslog {
+		writers = append(writers, "syslogWriter")
+	}
+
+	return writers
+}
+
+func newConfig(service, profile, sinks, addr, adminAddr string) (config, error) {
+	if !serviceName.MatchString(service) {
+		return config{}, fmt.Errorf("invalid service name %q", service)
+	}
+
+	constant, ok := profiles[profile]
+
+	if !ok {
+		return config{}, fmt.Errorf("invalid profile %q", profile)
+	}
+
+	c := config{Service: service, Profile: constant, Addr: addr, AdminAddr: adminAddr}
+
+	for _, sink := range strings.Split(sinks, ",") {
+		switch strings.TrimSpace(sink) {
+		case sinkStderr:
+			c.Stderr = true
+		case sinkFile:
+			c.File = true
+		case sinkSyslog:
+			c.Syslog = true
+		case sinkOTLP:
+			c.OTLP = true
+		default:
+			return config{}, fmt.Errorf("invalid sink %q, expected %s", sink, strings.Join(sinkNames, ", "))
+		}
+	}
+
+	return c, nil
+}
+
+// generate returns the gofmt-ed main.go of the configuration.
+func generate(c config) ([]byte, error) {
+	var source bytes.Buffer
+
+	if err := mainTemplate.Execute(&source, c); err != nil {
+		return nil, err
+	}
+
+	return format.Source(source.Bytes())
+}
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("gloggerinit", flag.ContinueOnError)
+	service := flags.String("service", "app", "name of the service, added as the service field of every entry")
+	profile := flags.String("profile", glogger.ProfileProduction, "profile of the logger and the middleware: production, development or test")
+	sinks := flags.String("sinks", sinkStderr, "comma-separated sinks of the entries: "+strings.Join(sinkNames, ", "))
+	addr := flags.String("addr", ":8080", "address of the service")
+	adminAddr := flags.String("admin-addr", ":9090", "address of the admin endpoints, such as the level handler")
+	output := flags.String("o", "", "file written, instead of the standard output")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return errors.New("usage: gloggerinit [-service <name>] [-profile <profile>] [-sinks <sinks>] [-o <file>]")
+	}
+
+	c, err := newConfig(*service, *profile, *sinks, *addr, *adminAddr)
+
+	if err != nil {
+		return err
+	}
+
+	source, err := generate(c)
+
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err := stdout.Write(source)
+		return err
+	}
+
+	return os.WriteFile(*output, source, 0o644)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
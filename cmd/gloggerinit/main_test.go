@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Main is wired with the profile and the sinks", func(t *testing.T) {
+		var output bytes.Buffer
+
+		err := run([]string{"-service", "billing", "-profile", "development", "-sinks", "stderr,file,otlp"}, &output)
+		assert.NilError(t, err)
+
+		source := output.String()
+		_, err = parser.ParseFile(token.NewFileSet(), "main.go", source, 0)
+		assert.NilError(t, err)
+
+		for _, expected := range []string{
+			`service         = "billing"`,
+			"Profile:      glogger.ProfileDevelopment,",
+			"{Writer: os.Stderr},",
+			"{Writer: fileWriter},",
+			"log.AddHook(exporter)",
+			"glogger.ProfileMiddlewareOptions(glogger.ProfileDevelopment)",
+			"options.RecoverPanics = true",
+			"glogger.LevelHandler(log)",
+			"server.Shutdown(shutdownCtx)",
+			"exporter.Close()",
+			"fileWriter.Close()",
+		} {
+			assert.Assert(t, strings.Contains(source, expected), "Missing %q in:\n%s", expected, source)
+		}
+
+		assert.Assert(t, !strings.Contains(source, "syslog"), source)
+	})
+
+	t.Run("Every sink combination type-checks against the current API", func(t *testing.T) {
+		for _, profile := range []string{"production", "development", "test"} {
+			for _, sinks := range []string{"stderr", "file", "syslog", "otlp", "file,syslog", "stderr,file,syslog,otlp"} {
+				var output bytes.Buffer
+
+				err := run([]string{"-profile", profile, "-sinks", sinks}, &output)
+				assert.NilError(t, err, sinks)
+
+				assert.NilError(t, typeCheck(output.Bytes()), "%s %s:\n%s", profile, sinks, output.String())
+			}
+		}
+	})
+
+	t.Run("Main is written to the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "main.go")
+
+		err := run([]string{"-o", path}, &bytes.Buffer{})
+		assert.NilError(t, err)
+
+		source, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Assert(t, bytes.Contains(source, []byte("glogger.ProfileProduction")))
+	})
+
+	t.Run("Invalid options are rejected", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-profile", "staging"},
+			{"-sinks", "stderr,kafka"},
+			{"-service", "Billing API"},
+		} {
+			err := run(args, &bytes.Buffer{})
+			assert.ErrorContains(t, err, "invalid", args)
+		}
+	})
+}
+
+// typeCheck type-checks the generated main.go against the packages of the module, read
+// from the export data of the go command, so that the API changes break the test.
+func typeCheck(source []byte) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", source, 0)
+
+	if err != nil {
+		return err
+	}
+
+	exports, err := exportData(file.Imports)
+
+	if err != nil {
+		return err
+	}
+
+	lookup := func(path string) (io.ReadCloser, error) {
+		return os.Open(exports[path])
+	}
+
+	config := types.Config{Importer: importer.ForCompiler(fset, "gc", lookup)}
+	_, err = config.Check("main", fset, []*ast.File{file}, nil)
+	return err
+}
+
+// exportData returns the export data files of the imported packages and their
+// dependencies, by import path. The packages are listed in a workspace of the glogger
+// module and of the otlplogger module, which has its own go.mod requiring a published
+// version of glogger, replaced with the local one.
+func exportData(imports []*ast.ImportSpec) (map[string]string, error) {
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "gloggerinit")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	version, err := requiredVersion(filepath.Join(root, "otlplogger"), gloggerModule)
+
+	if err != nil {
+		return nil, err
+	}
+
+	work := fmt.Sprintf("go 1.21\n\nuse (\n\t%q\n\t%q\n)\n\nreplace %s %s => %q\n", root, filepath.Join(root, "otlplogger"), gloggerModule, version, root)
+
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte(work), 0o644); err != nil {
+		return nil, err
+	}
+
+	args := []string{"list", "-export", "-deps", "-f", "{{.ImportPath}}={{.Export}}"}
+
+	for _, spec := range imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, path)
+	}
+
+	cmd := exec.Command("go", args...)
+	// The workspaces don't allow -mod=mod.
+	cmd.Env = append(os.Environ(), "GOWORK="+filepath.Join(dir, "go.work"), "GOFLAGS=")
+
+	output, err := cmd.Output()
+
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make(map[string]string)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path, export, ok := strings.Cut(line, "="); ok {
+			exports[path] = export
+		}
+	}
+
+	return exports, nil
+}
+
+// gloggerModule is the path of the glogger module.
+const gloggerModule = "github.com/platform-horizon/glogger"
+
+// requiredVersion returns the version of the module required by the module in dir.
+func requiredVersion(dir string, module string) (string, error) {
+	cmd := exec.Command("go", "mod", "edit", "-json")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+
+	output, err := cmd.Output()
+
+	if err != nil {
+		return "", err
+	}
+
+	var modFile struct {
+		Require []struct {
+			Path    string
+			Version string
+		}
+	}
+
+	if err := json.Unmarshal(output, &modFile); err != nil {
+		return "", err
+	}
+
+	for _, require := range modFile.Require {
+		if require.Path == module {
+			return require.Version, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s doesn't require %s", dir, module)
+}
@@ -0,0 +1,156 @@
+package main
+
+import "text/template"
+
+// mainTemplate is the main.go of a service, formatted with gofmt once executed.
+var mainTemplate = template.Must(template.New("main.go").Parse(`// Command {{.Service}} was scaffolded by gloggerinit.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+{{- if not .Writers}}
+	"io"
+{{- end}}
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/platform-horizon/glogger"
+{{- if .OTLP}}
+	"github.com/platform-horizon/glogger/otlplogger"
+{{- end}}
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	service         = "{{.Service}}"
+	shutdownTimeout = 10 * time.Second
+)
+
+func fail(message string, err error) {
+	fmt.Fprintf(os.Stderr, "%s, %v\n", message, err)
+	os.Exit(1)
+}
+
+func main() {
+{{- if .File}}
+	file, err := glogger.NewRotatingFile(glogger.RotatingFileOptions{
+		Filename:   "/var/log/" + service + "/" + service + ".log",
+		MaxSize:    100 << 20,
+		MaxBackups: 10,
+		Compress:   true,
+	})
+
+	if err != nil {
+		fail("Failed to open log file", err)
+	}
+
+	// The entries are written to the file in the background, and flushed on shutdown.
+	fileWriter := glogger.NewAsyncWriter(file, glogger.AsyncWriterOptions{})
+{{end}}
+{{- if .Syslog}}
+	syslogWriter, err := glogger.NewSyslogWriter(glogger.SyslogOptions{Address: "localhost:514", AppName: service})
+
+	if err != nil {
+		fail("Failed to connect to syslog", err)
+	}
+{{end}}
+	log, err := glogger.Init(glogger.InitOptions{
+		Profile:      glogger.{{.Profile}},
+		StaticFields: logrus.Fields{"service": service},
+{{- $writers := .Writers}}
+{{- if gt (len $writers) 1}}
+		Outputs: []glogger.Output{
+{{- range $writers}}
+			{Writer: {{.}}},
+{{- end}}
+		},
+{{- else if eq (len $writers) 1}}
+		Output: {{index $writers 0}},
+{{- else}}
+		Output: io.Discard,
+{{- end}}
+	})
+
+	if err != nil {
+		fail("Failed to init logger", err)
+	}
+{{if .OTLP}}
+	exporter, err := otlplogger.NewExporter(otlplogger.Options{Endpoint: "localhost:4317", ServiceName: service})
+
+	if err != nil {
+		fail("Failed to create OTLP exporter", err)
+	}
+
+	log.AddHook(exporter)
+{{end}}
+	options, err := glogger.ProfileMiddlewareOptions(glogger.{{.Profile}})
+
+	if err != nil {
+		fail("Failed to configure middleware", err)
+	}
+
+	options.RecoverPanics = true
+	options.Listener = "public"
+
+	router := mux.NewRouter()
+	router.Use(glogger.LoggingMiddlewareWithOptions(log, options))
+	router.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		glogger.Get(r.Context()).Info("Hello")
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	admin := http.NewServeMux()
+	admin.Handle("/log/level", glogger.LevelHandler(log))
+	stopReload := glogger.ReloadLevelOnSignal(log, "")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	servers := []*http.Server{
+		{Addr: "{{.Addr}}", Handler: router},
+		{Addr: "{{.AdminAddr}}", Handler: admin},
+	}
+
+	for _, server := range servers {
+		go func(server *http.Server) {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.WithError(err).WithField("addr", server.Addr).Error("Server failed")
+				stop()
+			}
+		}(server)
+	}
+
+	log.Info("Server started")
+	<-ctx.Done()
+	log.Info("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).WithField("addr", server.Addr).Error("Failed to shut down server")
+		}
+	}
+
+	stopReload()
+
+	// The logs are flushed once the requests are completed.
+{{- if .OTLP}}
+	exporter.Close()
+{{- end}}
+{{- if .File}}
+	fileWriter.Close()
+	file.Close()
+{{- end}}
+{{- if .Syslog}}
+	syslogWriter.Close()
+{{- end}}
+}
+`))
@@ -0,0 +1,36 @@
+package glogger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+const coalescedIntoKey = "coalescedInto"
+
+// MarkCoalesced marks the request of the context as coalesced into the request with the
+// leader request ID, which did the work shared by both, e.g. with singleflight. The
+// coalescedInto field is added to the entries of the request, so that the cache
+// stampedes are visible in the logs. It has no effect on the leader request itself and
+// on contexts not created by the middleware. The leader returns its request ID with the
+// shared result, so that the followers get it:
+//
+//	type result struct {
+//		leader string
+//		value  interface{}
+//	}
+//
+//	shared, err, coalesced := group.Do(key, func() (interface{}, error) {
+//		value, err := load(ctx, key)
+//		return result{leader: glogger.RequestIDFromContext(ctx), value: value}, err
+//	})
+//	if coalesced {
+//		glogger.MarkCoalesced(ctx, shared.(result).leader)
+//	}
+func MarkCoalesced(ctx context.Context, leaderRequestID string) {
+	if leaderRequestID == "" || leaderRequestID == RequestIDFromContext(ctx) {
+		return
+	}
+
+	AddFields(ctx, logrus.Fields{coalescedIntoKey: leaderRequestID})
+}
@@ -0,0 +1,139 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestMarkCoalesced(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		MarkCoalesced(r.Context(), r.URL.Query().Get("leader"))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?leader=leader-id", nil))
+	assert.Equal(t, hook.LastEntry().Data["coalescedInto"], "leader-id")
+
+	request := httptest.NewRequest(http.MethodGet, "/?leader=leader-id", nil)
+	request.Header.Set("X-Request-Id", "leader-id")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	_, ok := hook.LastEntry().Data["coalescedInto"]
+	assert.Assert(t, !ok, "Leader request must not be marked")
+}
+
+// coalescingGroup coalesces the concurrent calls with the same key, like singleflight.
+type coalescingGroup struct {
+	mutex sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+	dups  int
+}
+
+func (g *coalescingGroup) Do(key string, fn func() (interface{}, error)) (interface{}, error, bool) {
+	g.mutex.Lock()
+
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mutex.Unlock()
+		<-call.done
+
+		return call.value, call.err, true
+	}
+
+	call := &coalescedCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	call.value, call.err = fn()
+
+	g.mutex.Lock()
+	delete(g.calls, key)
+	shared := call.dups > 0
+	g.mutex.Unlock()
+	close(call.done)
+
+	return call.value, call.err, shared
+}
+
+func (g *coalescingGroup) dups(key string) int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call.dups
+	}
+
+	return 0
+}
+
+func TestMarkCoalescedPattern(t *testing.T) {
+	type result struct {
+		leader string
+		value  interface{}
+	}
+
+	logger, hook := test.NewNullLogger()
+	group := &coalescingGroup{calls: map[string]*coalescedCall{}}
+	loading, release := make(chan struct{}), make(chan struct{})
+
+	// The documented pattern of MarkCoalesced.
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		shared, _, coalesced := group.Do("key", func() (interface{}, error) {
+			close(loading)
+			<-release
+			return result{leader: RequestIDFromContext(ctx), value: "value"}, nil
+		})
+		if coalesced {
+			MarkCoalesced(ctx, shared.(result).leader)
+		}
+	}))
+
+	var served sync.WaitGroup
+
+	for _, id := range []string{"leader-id", "follower-id"} {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Request-Id", id)
+		served.Add(1)
+
+		go func() {
+			defer served.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+		}()
+
+		if id == "leader-id" {
+			<-loading
+		}
+	}
+
+	for group.dups("key") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	close(release)
+	served.Wait()
+
+	coalescedInto := map[string]interface{}{}
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Completed Request" {
+			coalescedInto[entry.Data["correlationId"].(string)] = entry.Data["coalescedInto"]
+		}
+	}
+
+	assert.DeepEqual(t, coalescedInto, map[string]interface{}{"leader-id": nil, "follower-id": "leader-id"})
+}
@@ -0,0 +1,138 @@
+package glogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCoalescingBufferSize    = 64 << 10
+	defaultCoalescingFlushInterval = 10 * time.Millisecond
+)
+
+// CoalescingWriterOptions is the struct of options to configure a CoalescingWriter.
+type CoalescingWriterOptions struct {
+	// BufferSize is the maximum number of bytes coalesced into a single write. Defaults
+	// to 64 KiB.
+	BufferSize int
+	// FlushInterval is the maximum delay of the entries. Defaults to 10 milliseconds.
+	FlushInterval time.Duration
+}
+
+// CoalescingWriter is an io.Writer coalescing the entries written within the flush
+// interval into a single write to the underlying writer, such as os.Stdout or a file, to
+// save the write system call of each entry at high throughput. The entries are never
+// split across writes. Flush or Close it on shutdown so that no entry is lost. It is
+// flushed when logrus exits the process, such as on Fatal.
+type CoalescingWriter struct {
+	writer   io.Writer
+	size     int
+	interval time.Duration
+	mutex    sync.Mutex
+	buffer   []byte
+	timer    *time.Timer
+	closed   bool
+}
+
+// NewCoalescingWriter returns a CoalescingWriter writing to w, to be set as the output of
+// the logger:
+//
+//	writer := glogger.NewCoalescingWriter(os.Stdout, glogger.CoalescingWriterOptions{})
+//	defer writer.Close()
+//	logger.SetOutput(writer)
+func NewCoalescingWriter(w io.Writer, options CoalescingWriterOptions) *CoalescingWriter {
+	size := options.BufferSize
+
+	if size <= 0 {
+		size = defaultCoalescingBufferSize
+	}
+
+	interval := options.FlushInterval
+
+	if interval <= 0 {
+		interval = defaultCoalescingFlushInterval
+	}
+
+	writer := &CoalescingWriter{writer: w, size: size, interval: interval, buffer: make([]byte, 0, size)}
+	flushOnExit(writer, writer.flushInBackground)
+
+	return writer
+}
+
+// Write buffers the entry. The buffer is written first if the entry doesn't fit, and
+// entries larger than the buffer are written directly.
+func (w *CoalescingWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	if len(w.buffer)+len(p) > w.size {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(p) > w.size {
+		return w.writer.Write(p)
+	}
+
+	if len(w.buffer) == 0 {
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.interval, w.flushInBackground)
+		} else {
+			w.timer.Reset(w.interval)
+		}
+	}
+
+	w.buffer = append(w.buffer, p...)
+
+	return len(p), nil
+}
+
+// Flush writes the buffered entries.
+func (w *CoalescingWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.flush()
+}
+
+// Close writes the buffered entries. The following writes fail with ErrWriterClosed.
+// The underlying writer is not closed.
+func (w *CoalescingWriter) Close() error {
+	stopFlushOnExit(w)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.closed = true
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	return w.flush()
+}
+
+func (w *CoalescingWriter) flushInBackground() {
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+}
+
+func (w *CoalescingWriter) flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	_, err := w.writer.Write(w.buffer)
+	w.buffer = w.buffer[:0]
+
+	return err
+}
@@ -0,0 +1,69 @@
+package glogger
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// recordingWriter records each write.
+type recordingWriter struct {
+	mutex  sync.Mutex
+	writes []string
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.writes = append(w.writes, string(p))
+
+	return len(p), nil
+}
+
+func (w *recordingWriter) Writes() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return append([]string(nil), w.writes...)
+}
+
+func TestCoalescingWriter(t *testing.T) {
+	t.Run("Entries are coalesced until the buffer is full", func(t *testing.T) {
+		output := &recordingWriter{}
+		writer := NewCoalescingWriter(output, CoalescingWriterOptions{BufferSize: 14, FlushInterval: time.Hour})
+
+		for _, entry := range []string{"first\n", "second\n", "third\n", "a longer entry\n"} {
+			_, err := writer.Write([]byte(entry))
+			assert.NilError(t, err)
+		}
+
+		assert.DeepEqual(t, output.Writes(), []string{"first\nsecond\n", "third\n", "a longer entry\n"})
+
+		writer.Write([]byte("last\n"))
+		assert.NilError(t, writer.Close())
+		assert.Equal(t, output.Writes()[3], "last\n")
+
+		_, err := writer.Write([]byte("closed\n"))
+		assert.Equal(t, err, ErrWriterClosed)
+	})
+
+	t.Run("Entries are written after the flush interval", func(t *testing.T) {
+		output := &recordingWriter{}
+		writer := NewCoalescingWriter(output, CoalescingWriterOptions{FlushInterval: time.Millisecond})
+		defer writer.Close()
+
+		writer.Write([]byte("first\n"))
+		writer.Write([]byte("second\n"))
+
+		deadline := time.Now().Add(5 * time.Second)
+
+		for len(output.Writes()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		assert.DeepEqual(t, output.Writes(), []string{"first\nsecond\n"})
+	})
+}
@@ -0,0 +1,129 @@
+package glogger
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ColorsEnv is the environment variable configuring the colors of the pretty printed
+// output when the formatter has no ColorProfile, e.g. "level.error=1;31,key.correlationId=36,key.time=2".
+const ColorsEnv = "GLOGGER_COLORS"
+
+// ColorProfile configures the colors and styles of the pretty printed JSON output.
+// Styles are ANSI SGR parameters, such as "31" (red), "1;33" (bold yellow) or "2" (dim).
+type ColorProfile struct {
+	// Levels are the styles of the level field value, per level.
+	Levels map[logrus.Level]string
+	// Keys are the styles of the values of the fields with the given key, at any depth.
+	Keys map[string]string
+}
+
+// DefaultColorProfile returns the default color profile, coloring the levels and dimming the time.
+func DefaultColorProfile() *ColorProfile {
+	return &ColorProfile{
+		Levels: map[logrus.Level]string{
+			logrus.TraceLevel: "2",
+			logrus.DebugLevel: "37",
+			logrus.InfoLevel:  "36",
+			logrus.WarnLevel:  "33",
+			logrus.ErrorLevel: "31",
+			logrus.FatalLevel: "1;31",
+			logrus.PanicLevel: "1;31",
+		},
+		Keys: map[string]string{
+			"time": "2",
+		},
+	}
+}
+
+// ParseColorProfile parses a color profile in the ColorsEnv format: a comma separated list
+// of "level.<level>=<style>" and "key.<key>=<style>" items, applied over the default profile.
+func ParseColorProfile(value string) (*ColorProfile, error) {
+	profile := DefaultColorProfile()
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, "=", 2)
+
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid color item %q", item)
+		}
+
+		name, style := parts[0], parts[1]
+
+		switch {
+		case strings.HasPrefix(name, "level."):
+			level, err := logrus.ParseLevel(strings.TrimPrefix(name, "level."))
+
+			if err != nil {
+				return nil, err
+			}
+
+			profile.Levels[level] = style
+		case strings.HasPrefix(name, "key."):
+			profile.Keys[strings.TrimPrefix(name, "key.")] = style
+		default:
+			return nil, fmt.Errorf("invalid color item %q", item)
+		}
+	}
+
+	return profile, nil
+}
+
+var (
+	envColorProfile     *ColorProfile
+	envColorProfileOnce sync.Once
+)
+
+// getEnvColorProfile returns the color profile configured by ColorsEnv, if any.
+// An invalid value is ignored.
+func getEnvColorProfile() *ColorProfile {
+	envColorProfileOnce.Do(func() {
+		if value, ok := os.LookupEnv(ColorsEnv); ok {
+			envColorProfile, _ = ParseColorProfile(value)
+		}
+	})
+
+	return envColorProfile
+}
+
+var prettyLineRegexp = regexp.MustCompile(`^(\s*)"((?:[^"\\]|\\.)*)": (.*?)(,?)$`)
+
+func colorize(value string, style string) string {
+	return "\x1b[" + style + "m" + value + "\x1b[0m"
+}
+
+// colorize styles the scalar values of the pretty printed JSON output.
+func (profile *ColorProfile) colorize(output []byte, level logrus.Level) []byte {
+	lines := strings.Split(string(output), "\n")
+
+	for i, line := range lines {
+		match := prettyLineRegexp.FindStringSubmatch(line)
+
+		if match == nil || strings.HasSuffix(match[3], "{") || strings.HasSuffix(match[3], "[") {
+			continue
+		}
+
+		style, ok := profile.Keys[match[2]]
+
+		if match[2] == "level" && match[1] == "  " {
+			style, ok = profile.Levels[level]
+		}
+
+		if ok && style != "" {
+			lines[i] = match[1] + `"` + match[2] + `": ` + colorize(match[3], style) + match[4]
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
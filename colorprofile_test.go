@@ -0,0 +1,49 @@
+package glogger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestColorProfile(t *testing.T) {
+	t.Run("Profile is parsed over the default one", func(t *testing.T) {
+		profile, err := ParseColorProfile("level.error=1;31, key.correlationId=36")
+
+		assert.Assert(t, err == nil, "Unexpected error")
+		assert.Equal(t, profile.Levels[logrus.ErrorLevel], "1;31")
+		assert.Equal(t, profile.Levels[logrus.InfoLevel], "36")
+		assert.Equal(t, profile.Keys["correlationId"], "36")
+		assert.Equal(t, profile.Keys["time"], "2")
+	})
+
+	t.Run("Invalid items are rejected", func(t *testing.T) {
+		_, err := ParseColorProfile("color.error=31")
+		assert.Assert(t, err != nil, "Expected error")
+
+		_, err = ParseColorProfile("level.unknown=31")
+		assert.Assert(t, err != nil, "Expected error")
+	})
+
+	t.Run("Pretty output values are colored", func(t *testing.T) {
+		formatter := JSONFormatter{
+			PrettyPrint:  true,
+			ColorProfile: &ColorProfile{Levels: map[logrus.Level]string{logrus.WarnLevel: "33"}, Keys: map[string]string{"correlationId": "1"}},
+		}
+		entry := logrus.Entry{
+			Level:   logrus.WarnLevel,
+			Message: "Slow",
+			Data:    logrus.Fields{"correlationId": "abc", "host": Host{Hostname: "localhost"}},
+		}
+
+		data, err := formatter.Format(&entry)
+		output := string(data)
+
+		assert.Assert(t, err == nil, "Error is nil")
+		assert.Assert(t, strings.Contains(output, "\"level\": \x1b[33m\"warning\"\x1b[0m,"), output)
+		assert.Assert(t, strings.Contains(output, "\"correlationId\": \x1b[1m\"abc\"\x1b[0m,"), output)
+		assert.Assert(t, strings.Contains(output, "\"message\": \"Slow\","), output)
+	})
+}
@@ -0,0 +1,124 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// withCompatAliases returns a copy of the entry whose fields also have the aliases of the
+// renamed fields. The aliases map the dot-separated path of a field, such as
+// "http.response.responseTime", to the path of its alias. The fields on the path of an
+// alias are converted to their JSON representation to be extended.
+func withCompatAliases(entry *logrus.Entry, aliases map[string]string, timestamp timestampFormat) (*logrus.Entry, error) {
+	data := make(map[string]interface{}, len(entry.Data)+len(aliases))
+
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+
+	builtins := map[string]interface{}{timestamp.key: timestamp.value(entry.Time), "message": entry.Message, "level": entry.Level.String()}
+
+	for path, alias := range aliases {
+		value, ok, err := lookupPath(data, builtins, strings.Split(path, "."))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setPath(data, strings.Split(alias, "."), value); err != nil {
+			return nil, err
+		}
+	}
+
+	aliased := *entry
+	aliased.Data = data
+
+	return &aliased, nil
+}
+
+// lookupPath returns the value of the field at the path.
+func lookupPath(data map[string]interface{}, builtins map[string]interface{}, path []string) (interface{}, bool, error) {
+	value, ok := data[path[0]]
+
+	if !ok {
+		value, ok = builtins[path[0]]
+	}
+
+	for _, key := range path[1:] {
+		if !ok {
+			return nil, false, nil
+		}
+
+		object, err := jsonObjectOf(value)
+
+		if err != nil || object == nil {
+			return nil, false, err
+		}
+
+		value, ok = object[key]
+	}
+
+	return value, ok, nil
+}
+
+// setPath sets the value of the field at the path, converting the objects on the path to
+// maps. The path is not set if it crosses a value which is not an object.
+func setPath(data map[string]interface{}, path []string, value interface{}) error {
+	for _, key := range path[:len(path)-1] {
+		next, ok := data[key]
+
+		if !ok {
+			object := map[string]interface{}{}
+			data[key] = object
+			data = object
+			continue
+		}
+
+		object, err := jsonObjectOf(next)
+
+		if err != nil || object == nil {
+			return err
+		}
+
+		data[key] = object
+		data = object
+	}
+
+	data[path[len(path)-1]] = value
+
+	return nil
+}
+
+// jsonObjectOf returns the value as a map if it is encoded as a JSON object, or nil.
+func jsonObjectOf(value interface{}) (map[string]interface{}, error) {
+	if object, ok := value.(map[string]interface{}); ok {
+		return object, nil
+	}
+
+	if _, isError := value.(error); isError {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(value)
+
+	if err != nil || len(encoded) == 0 || encoded[0] != '{' {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
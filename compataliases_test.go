@@ -0,0 +1,39 @@
+package glogger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestCompatAliases(t *testing.T) {
+	entry := &logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Time:    time.Unix(1700000000, 0),
+		Message: "Completed Request",
+		Data: logrus.Fields{
+			"http": HTTP{
+				Request:  &Request{Method: "GET"},
+				Response: &Response{StatusCode: 200, ResponseTime: 0.25},
+			},
+			"userId": "42",
+		},
+	}
+
+	formatter := JSONFormatter{CompatAliases: map[string]string{
+		"http.response.responseTime": "http.response.duration",
+		"userId":                     "user.id",
+		"message":                    "msg",
+		"http.request.missing":       "missing",
+		"userId.nested":              "nested",
+	}}
+
+	data, err := formatter.Format(entry)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"http":{"request":{"method":"GET"},"response":{"duration":0.25,"responseTime":0.25,"statusCode":200}},"level":"info","message":"Completed Request","msg":"Completed Request","time":1700000000,"user":{"id":"42"},"userId":"42"}`+"\n")
+
+	_, isHTTP := entry.Data["http"].(HTTP)
+	assert.Assert(t, isHTTP, "The fields of the entry must not be modified")
+}
@@ -0,0 +1,172 @@
+package glogger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const componentKey = "component"
+
+// Named returns a new context with a child of the context logger for the named component,
+// and the child logger itself, like Child. The component field is hierarchical: the name
+// is appended to the component of the context logger, if any, such as "api.db.pool" for
+// "pool" under "api.db", so that the subsystems can be filtered and tuned independently
+// with InitOptions.ComponentLevels: the entries of a component having a level are logged
+// with a logger of this level.
+func Named(ctx context.Context, name string) (context.Context, *logrus.Entry) {
+	logger, ok := ctx.Value(loggerKey{}).(*logrus.Entry)
+
+	if !ok {
+		logger = defaultLogger
+	}
+
+	if parent, ok := logger.Data[componentKey].(string); ok && parent != "" {
+		name = parent + "." + name
+	}
+
+	return withChild(ctx, withComponentLogger(logger.WithField(componentKey, name), name))
+}
+
+// componentLevels are the levels of the components of a logger, set with
+// InitOptions.ComponentLevels. The entries of the components having a level are logged
+// with a logger of this level, which shares the hooks, the formatter and the output of
+// the logger, so that the level applies before the hooks.
+type componentLevels struct {
+	root      *logrus.Logger
+	levels    map[string]logrus.Level
+	formatter *sharedFormatter
+	mutex     sync.Mutex
+	loggers   map[logrus.Level]*logrus.Logger
+}
+
+// componentLevelsByLogger holds the componentLevels of the loggers with component levels
+// and of their component loggers.
+var componentLevelsByLogger sync.Map
+
+func newComponentLevels(root *logrus.Logger, levels map[string]string) (*componentLevels, error) {
+	parsed := make(map[string]logrus.Level, len(levels))
+
+	for component, level := range levels {
+		componentLevel, err := logrus.ParseLevel(level)
+
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", component, err)
+		}
+
+		parsed[component] = componentLevel
+	}
+
+	// The formatter writes the entries of the logger and of the component loggers, so
+	// that they are formatted and written one at a time, in order.
+	formatter := &sharedFormatter{formatter: root.Formatter, out: root.Out}
+	root.SetFormatter(formatter)
+	root.SetOutput(io.Discard)
+
+	c := &componentLevels{root: root, levels: parsed, formatter: formatter, loggers: map[logrus.Level]*logrus.Logger{}}
+	componentLevelsByLogger.Store(root, c)
+
+	return c, nil
+}
+
+// withComponentLogger returns the entry logged with the logger of the level of the
+// component, if the component or one of its parents has a level.
+func withComponentLogger(entry *logrus.Entry, component string) *logrus.Entry {
+	value, ok := componentLevelsByLogger.Load(entry.Logger)
+
+	if !ok {
+		return entry
+	}
+
+	c := value.(*componentLevels)
+	logger := c.root
+
+	if level, ok := c.componentLevel(component); ok {
+		logger = c.logger(level)
+	}
+
+	if logger == entry.Logger {
+		return entry
+	}
+
+	return &logrus.Entry{Logger: logger, Data: entry.Data, Time: entry.Time, Context: entry.Context}
+}
+
+func (c *componentLevels) componentLevel(component string) (logrus.Level, bool) {
+	for component != "" {
+		if level, ok := c.levels[component]; ok {
+			return level, true
+		}
+
+		dot := strings.LastIndexByte(component, '.')
+
+		if dot < 0 {
+			break
+		}
+
+		component = component[:dot]
+	}
+
+	return 0, false
+}
+
+// logger returns the logger of the level, sharing the hooks, the formatter and the exit
+// function of the root logger.
+func (c *componentLevels) logger(level logrus.Level) *logrus.Logger {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if logger, ok := c.loggers[level]; ok {
+		return logger
+	}
+
+	logger := &logrus.Logger{
+		Out:          io.Discard,
+		Hooks:        c.root.Hooks,
+		Formatter:    c.formatter,
+		ReportCaller: c.root.ReportCaller,
+		Level:        level,
+		ExitFunc: func(code int) {
+			if c.root.ExitFunc != nil {
+				c.root.ExitFunc(code)
+				return
+			}
+
+			os.Exit(code)
+		},
+	}
+
+	c.loggers[level] = logger
+	componentLevelsByLogger.Store(logger, c)
+
+	return logger
+}
+
+// sharedFormatter formats and writes the entries of several loggers, one at a time.
+type sharedFormatter struct {
+	mutex     sync.Mutex
+	formatter logrus.Formatter
+	out       io.Writer
+}
+
+func (formatter *sharedFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	formatter.mutex.Lock()
+	defer formatter.mutex.Unlock()
+
+	serialized, err := formatter.formatter.Format(entry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := formatter.out.Write(serialized); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+
+	return nil, nil
+}
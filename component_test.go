@@ -0,0 +1,95 @@
+package glogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestNamed(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	ctx := WithLogger(context.Background(), logger.WithField("correlationId", "id"))
+
+	ctx, db := Named(ctx, "db")
+	db.Info("Connected")
+	assert.Equal(t, hook.LastEntry().Data["component"], "db")
+
+	_, pool := Named(ctx, "pool")
+	pool.Info("Exhausted")
+	assert.Equal(t, hook.LastEntry().Data["component"], "db.pool")
+	assert.Equal(t, hook.LastEntry().Data["correlationId"], "id")
+
+	Get(ctx).Info("Queried")
+	assert.Equal(t, hook.LastEntry().Data["component"], "db")
+}
+
+func TestComponentLevels(t *testing.T) {
+	var output bytes.Buffer
+
+	logger, err := Init(InitOptions{
+		Level:           "info",
+		Output:          &output,
+		ComponentLevels: map[string]string{"api.db": "debug", "api.cache": "error"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, logger.GetLevel(), logrus.InfoLevel)
+
+	ctx := WithLogger(context.Background(), logrus.NewEntry(logger))
+	ctx, _ = Named(ctx, "api")
+	_, db := Named(ctx, "db")
+	_, pool := Named(WithLogger(ctx, db), "pool")
+	_, cache := Named(ctx, "cache")
+
+	Get(ctx).Debug("Dropped")
+	Get(ctx).Info("Kept")
+	pool.Debug("Kept")
+	cache.Warn("Dropped")
+	cache.Error("Kept")
+
+	var components []string
+
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, entry["message"], "Kept")
+		components = append(components, entry["component"].(string))
+	}
+
+	assert.DeepEqual(t, components, []string{"api", "api.db.pool", "api.cache"})
+
+	t.Run("The level of the logger applies at runtime", func(t *testing.T) {
+		output.Reset()
+		assert.NilError(t, SetLevel(logger, "debug"))
+
+		Get(ctx).Debug("Kept")
+		cache.Info("Dropped")
+		assert.Equal(t, strings.Count(output.String(), "Kept"), 1)
+
+		assert.NilError(t, SetLevel(logger, "warning"))
+		Get(ctx).Info("Dropped")
+		pool.Debug("Kept")
+		assert.Equal(t, strings.Count(output.String(), "Kept"), 2)
+		assert.Assert(t, !strings.Contains(output.String(), "Dropped"), output.String())
+	})
+
+	t.Run("Hooks only see the entries of the enabled levels", func(t *testing.T) {
+		hook := test.NewLocal(logger)
+		assert.NilError(t, SetLevel(logger, "info"))
+
+		Get(ctx).Debug("Dropped")
+		cache.Warn("Dropped")
+		pool.Debug("Kept")
+
+		assert.Equal(t, len(hook.AllEntries()), 1)
+		assert.Equal(t, hook.LastEntry().Message, "Kept")
+	})
+
+	_, err = Init(InitOptions{ComponentLevels: map[string]string{"api": "verbose"}})
+	assert.Assert(t, err != nil, "Expected error")
+}
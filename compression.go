@@ -0,0 +1,110 @@
+package glogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is a compression codec of the Compression.
+type Codec string
+
+const (
+	// CodecGzip compresses with gzip, supported everywhere.
+	CodecGzip Codec = "gzip"
+	// CodecZstd compresses with zstd, faster and smaller than gzip.
+	CodecZstd Codec = "zstd"
+)
+
+// Compression is the struct of options to configure the compression of the rotated files
+// and of the exports.
+type Compression struct {
+	// Codec is CodecGzip or CodecZstd. Empty disables the compression.
+	Codec Codec
+	// Level is the compression level, from 1, the fastest, to 9 with gzip and 22 with
+	// zstd, the smallest. Zero is the default level of the codec.
+	Level int
+}
+
+// Enabled reports whether a codec is set.
+func (c Compression) Enabled() bool {
+	return c.Codec != ""
+}
+
+// Extension returns the file extension of the codec, such as ".gz".
+func (c Compression) Extension() string {
+	switch c.Codec {
+	case CodecGzip:
+		return ".gz"
+	case CodecZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// NewWriter returns a writer compressing to w with the codec. It must be closed to write
+// the end of the compressed stream, which doesn't close w.
+func (c Compression) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	switch c.Codec {
+	case CodecGzip:
+		level := c.Level
+
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+
+		return gzip.NewWriterLevel(w, level)
+	case CodecZstd:
+		options := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+
+		if c.Level != 0 {
+			if c.Level < 1 || c.Level > 22 {
+				return nil, fmt.Errorf("zstd: invalid compression level: %d", c.Level)
+			}
+
+			options = append(options, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(c.Level)))
+		}
+
+		return zstd.NewWriter(w, options...)
+	default:
+		return nil, fmt.Errorf("glogger: unknown compression codec %q", c.Codec)
+	}
+}
+
+// NewReader returns a reader decompressing r with the codec. Closing it doesn't close r.
+func (c Compression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	switch c.Codec {
+	case CodecGzip:
+		return gzip.NewReader(r)
+	case CodecZstd:
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("glogger: unknown compression codec %q", c.Codec)
+	}
+}
+
+// compressedExtensions are the extensions of the files compressed with any codec.
+var compressedExtensions = []string{".gz", ".zst"}
+
+// compressionOf returns the compression of the file from its extension, disabled if it
+// isn't compressed.
+func compressionOf(path string) Compression {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return Compression{Codec: CodecGzip}
+	case strings.HasSuffix(path, ".zst"):
+		return Compression{Codec: CodecZstd}
+	default:
+		return Compression{}
+	}
+}
@@ -0,0 +1,48 @@
+package glogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestCompression(t *testing.T) {
+	for _, compression := range []Compression{
+		{Codec: CodecGzip},
+		{Codec: CodecGzip, Level: gzip.BestSpeed},
+		{Codec: CodecZstd},
+		{Codec: CodecZstd, Level: 19},
+	} {
+		var buffer bytes.Buffer
+
+		writer, err := compression.NewWriter(&buffer)
+		assert.NilError(t, err)
+
+		_, err = writer.Write([]byte(`{"level":"info","message":"Compressed"}` + "\n"))
+		assert.NilError(t, err)
+		assert.NilError(t, writer.Close())
+
+		reader, err := compression.NewReader(&buffer)
+		assert.NilError(t, err)
+
+		content, err := io.ReadAll(reader)
+		assert.NilError(t, err)
+		assert.NilError(t, reader.Close())
+		assert.Equal(t, string(content), `{"level":"info","message":"Compressed"}`+"\n")
+	}
+
+	_, err := Compression{Codec: "lz4"}.NewWriter(io.Discard)
+	assert.ErrorContains(t, err, `unknown compression codec "lz4"`)
+
+	_, err = Compression{Codec: "lz4"}.NewReader(&bytes.Buffer{})
+	assert.ErrorContains(t, err, `unknown compression codec "lz4"`)
+
+	_, err = Compression{Codec: CodecZstd, Level: 23}.NewWriter(io.Discard)
+	assert.ErrorContains(t, err, "invalid compression level")
+
+	assert.Equal(t, Compression{Codec: CodecZstd}.Extension(), ".zst")
+	assert.Assert(t, !Compression{}.Enabled())
+}
@@ -0,0 +1,155 @@
+package glogger
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+const configKey = "config"
+
+// DefaultSecretKeys are the name patterns of the configuration keys holding secrets.
+var DefaultSecretKeys = []string{"*password*", "*secret*", "*token*", "*credential*", "*key"}
+
+// ConfigChange struct contains items of configuration change info log. Old is missing
+// for the added keys and New for the removed ones.
+type ConfigChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// ConfigReload struct contains items of configuration reload info log.
+type ConfigReload struct {
+	Changes []ConfigChange `json:"changes"`
+}
+
+// DiffConfig returns the changes from the old to the new configuration, such as structs
+// or maps, compared through their JSON encoding: one change per changed value, with the
+// dot-separated path of its key, the arrays being compared as a whole. The values under
+// the keys matching the secretKeys, case-insensitive name patterns (as in path.Match)
+// such as DefaultSecretKeys, are replaced with [REDACTED], including in the objects of
+// the arrays, so that the change is traced without the secret.
+func DiffConfig(old, new interface{}, secretKeys []string) ([]ConfigChange, error) {
+	oldValue, err := decodeConfig(old)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, err := decodeConfig(new)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ConfigChange
+	diffConfig("", oldValue, newValue, false, secretKeys, &changes)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// LogConfigReload logs the changes from the old to the new configuration, as computed by
+// DiffConfig with DefaultSecretKeys, in a "Configuration reloaded" warning entry, such as
+// once a configuration file was reloaded on SIGHUP. Nothing is logged without change.
+func LogConfigReload(logger logrus.FieldLogger, old, new interface{}) error {
+	changes, err := DiffConfig(old, new, DefaultSecretKeys)
+
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	logger.WithField(configKey, ConfigReload{Changes: changes}).Warn("Configuration reloaded")
+
+	return nil
+}
+
+func decodeConfig(config interface{}) (interface{}, error) {
+	data, err := json.Marshal(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+
+	return value, json.Unmarshal(data, &value)
+}
+
+// diffConfig appends the changes from the old to the new value at the path, redacted if
+// the value is secret.
+func diffConfig(path string, old, new interface{}, secret bool, secretKeys []string, changes *[]ConfigChange) {
+	oldObject, oldIsObject := old.(map[string]interface{})
+	newObject, newIsObject := new.(map[string]interface{})
+
+	if oldIsObject && newIsObject {
+		for key, value := range oldObject {
+			diffConfig(joinConfigPath(path, key), value, newObject[key], secret || matchName(secretKeys, key), secretKeys, changes)
+		}
+
+		for key, value := range newObject {
+			if _, ok := oldObject[key]; !ok {
+				diffConfig(joinConfigPath(path, key), nil, value, secret || matchName(secretKeys, key), secretKeys, changes)
+			}
+		}
+
+		return
+	}
+
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+
+	*changes = append(*changes, ConfigChange{
+		Path: path,
+		Old:  redactConfig(old, secret, secretKeys),
+		New:  redactConfig(new, secret, secretKeys),
+	})
+}
+
+// redactConfig returns the value with the values under the secret keys, or the whole value
+// if secret, replaced with the redaction mask.
+func redactConfig(value interface{}, secret bool, secretKeys []string) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if secret {
+		return defaultRedactionMask
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+
+		for key, item := range value {
+			redacted[key] = redactConfig(item, matchName(secretKeys, key), secretKeys)
+		}
+
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+
+		for i, item := range value {
+			redacted[i] = redactConfig(item, false, secretKeys)
+		}
+
+		return redacted
+	}
+
+	return value
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
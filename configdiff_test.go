@@ -0,0 +1,83 @@
+package glogger
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+type databaseConfig struct {
+	Host     string `json:"host"`
+	PoolSize int    `json:"poolSize"`
+	Password string `json:"password"`
+}
+
+type serviceConfig struct {
+	Level    string            `json:"level"`
+	Database databaseConfig    `json:"database"`
+	Features []string          `json:"features"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	APIKey   string            `json:"apiKey"`
+}
+
+func TestDiffConfig(t *testing.T) {
+	old := serviceConfig{
+		Level:    "info",
+		Database: databaseConfig{Host: "db-1", PoolSize: 10, Password: "hunter2"},
+		Features: []string{"search"},
+		Labels:   map[string]string{"team": "payments"},
+		APIKey:   "abc",
+	}
+
+	updated := old
+	updated.Database.PoolSize = 20
+	updated.Database.Password = "correct horse"
+	updated.Features = []string{"search", "export"}
+	updated.Labels = map[string]string{"tier": "1"}
+
+	changes, err := DiffConfig(old, updated, DefaultSecretKeys)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, []ConfigChange{
+		{Path: "database.password", Old: "[REDACTED]", New: "[REDACTED]"},
+		{Path: "database.poolSize", Old: 10.0, New: 20.0},
+		{Path: "features", Old: []interface{}{"search"}, New: []interface{}{"search", "export"}},
+		{Path: "labels.team", Old: "payments"},
+		{Path: "labels.tier", New: "1"},
+	})
+
+	t.Run("Reloads are logged with the changes", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		assert.NilError(t, LogConfigReload(logger, old, old))
+		assert.Equal(t, len(hook.AllEntries()), 0)
+
+		assert.NilError(t, LogConfigReload(logger, old, updated))
+		assert.Equal(t, hook.LastEntry().Message, "Configuration reloaded")
+		assert.Equal(t, len(hook.LastEntry().Data["config"].(ConfigReload).Changes), 5)
+	})
+
+	t.Run("Secrets are redacted in the arrays and the added objects", func(t *testing.T) {
+		old := map[string]interface{}{
+			"sinks": []interface{}{map[string]interface{}{"url": "https://a", "token": "x"}},
+		}
+		updated := map[string]interface{}{
+			"sinks":  []interface{}{map[string]interface{}{"url": "https://b", "token": "x"}},
+			"vendor": map[string]interface{}{"secret": "y"},
+		}
+
+		changes, err := DiffConfig(old, updated, DefaultSecretKeys)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, changes, []ConfigChange{
+			{
+				Path: "sinks",
+				Old:  []interface{}{map[string]interface{}{"url": "https://a", "token": "[REDACTED]"}},
+				New:  []interface{}{map[string]interface{}{"url": "https://b", "token": "[REDACTED]"}},
+			},
+			{Path: "vendor", New: map[string]interface{}{"secret": "[REDACTED]"}},
+		})
+	})
+
+	_, err = DiffConfig(make(chan int), old, nil)
+	assert.ErrorContains(t, err, "unsupported type")
+}
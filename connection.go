@@ -0,0 +1,131 @@
+package glogger
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultConnectionHeartbeatInterval = 30 * time.Second
+	connectionClosedByServer           = "server closed"
+)
+
+// Connection struct contains items of hijacked connection info log.
+type Connection struct {
+	BytesRead    int64   `json:"bytesRead"`
+	BytesWritten int64   `json:"bytesWritten"`
+	Duration     float64 `json:"duration,omitempty"`
+	CloseReason  string  `json:"closeReason,omitempty"`
+}
+
+// loggedConn is a hijacked connection counting the bytes transferred, logging a heartbeat
+// entry every interval while it is open and a closed entry when it is closed.
+type loggedConn struct {
+	net.Conn
+	logger    *logrus.Entry
+	start     time.Time
+	read      atomic.Int64
+	written   atomic.Int64
+	mutex     sync.Mutex
+	heartbeat *time.Timer
+	interval  time.Duration
+	readErr   error
+	closed    bool
+}
+
+func newLoggedConn(conn net.Conn, logger *logrus.Entry, interval time.Duration) *loggedConn {
+	if interval <= 0 {
+		interval = defaultConnectionHeartbeatInterval
+	}
+
+	c := &loggedConn{Conn: conn, logger: logger, start: time.Now(), interval: interval}
+	c.heartbeat = time.AfterFunc(interval, c.beat)
+
+	return c
+}
+
+func (c *loggedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.read.Add(int64(n))
+
+	var timeout net.Error
+
+	if err != nil && !errors.As(err, &timeout) {
+		c.mutex.Lock()
+
+		if c.readErr == nil && !c.closed {
+			c.readErr = err
+		}
+
+		c.mutex.Unlock()
+	}
+
+	return n, err
+}
+
+func (c *loggedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.written.Add(int64(n))
+
+	return n, err
+}
+
+// Close closes the connection and logs the closed entry, once.
+func (c *loggedConn) Close() error {
+	err := c.Conn.Close()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return err
+	}
+
+	c.closed = true
+	c.heartbeat.Stop()
+
+	c.logger.WithField("connection", c.info(c.closeReason())).Info("Connection closed")
+
+	return err
+}
+
+func (c *loggedConn) beat() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.logger.WithField("connection", c.info("")).Info("Connection heartbeat")
+	c.heartbeat.Reset(c.interval)
+}
+
+// closeReason is the client disconnection when a read failed with io.EOF before the
+// connection was closed, the read error when it failed otherwise, and the server
+// closing the connection by default.
+func (c *loggedConn) closeReason() string {
+	switch {
+	case c.readErr == nil:
+		return connectionClosedByServer
+	case errors.Is(c.readErr, io.EOF):
+		return streamClosedByClient
+	default:
+		return c.readErr.Error()
+	}
+}
+
+func (c *loggedConn) info(closeReason string) Connection {
+	return Connection{
+		BytesRead:    c.read.Load(),
+		BytesWritten: c.written.Load(),
+		Duration:     time.Since(c.start).Seconds(),
+		CloseReason:  closeReason,
+	}
+}
@@ -0,0 +1,134 @@
+package glogger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLogConnections(t *testing.T) {
+	const upgrade = "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n"
+
+	logger, hook := test.NewNullLogger()
+
+	options := MiddlewareOptions{LogConnections: true, ConnectionHeartbeatInterval: 10 * time.Millisecond}
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		conn, _, err := http.NewResponseController(rw).Hijack()
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		io.WriteString(conn, upgrade)
+		time.Sleep(35 * time.Millisecond)
+
+		// The handler reads until the client disconnects.
+		io.Copy(io.Discard, conn)
+	}))
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(rw, r)
+		close(done)
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	assert.NilError(t, err)
+
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+
+	response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, http.StatusSwitchingProtocols)
+
+	io.WriteString(conn, "ping")
+	time.Sleep(10 * time.Millisecond)
+	conn.Close()
+	<-done
+
+	var messages []string
+	var closed *logrus.Entry
+
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Message)
+
+		if entry.Message == "Connection closed" {
+			closed = entry
+		}
+	}
+
+	assert.Equal(t, messages[0], "Connection established")
+	assert.Equal(t, messages[1], "Connection heartbeat")
+	assert.Assert(t, closed != nil, messages)
+
+	established := hook.AllEntries()[0].Data["http"].(HTTP).Response
+	assert.Equal(t, established.StatusCode, http.StatusSwitchingProtocols)
+	assert.Equal(t, established.Upgrade, "websocket")
+
+	connection := closed.Data["connection"].(Connection)
+	assert.Equal(t, connection.BytesRead, int64(4))
+	assert.Equal(t, connection.BytesWritten, int64(len(upgrade)))
+	assert.Equal(t, connection.CloseReason, "client disconnected")
+	assert.Assert(t, connection.Duration >= 0.035)
+}
+
+func TestLogConnectionsReadWriter(t *testing.T) {
+	const upgrade = "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n"
+
+	logger, hook := test.NewNullLogger()
+
+	handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{LogConnections: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		conn, brw, err := http.NewResponseController(rw).Hijack()
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		brw.WriteString(upgrade)
+		brw.Flush()
+
+		// The handler reads through the ReadWriter, as gorilla/websocket does, until the
+		// client disconnects.
+		io.Copy(io.Discard, brw)
+	}))
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(rw, r)
+		close(done)
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	assert.NilError(t, err)
+
+	// The first frame is sent with the request, so that the server buffers it.
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\nping")
+
+	response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, http.StatusSwitchingProtocols)
+
+	io.WriteString(conn, "pong")
+	time.Sleep(10 * time.Millisecond)
+	conn.Close()
+	<-done
+
+	var closed *logrus.Entry
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Connection closed" {
+			closed = entry
+		}
+	}
+
+	assert.Assert(t, closed != nil)
+
+	connection := closed.Data["connection"].(Connection)
+	assert.Equal(t, connection.BytesRead, int64(8))
+	assert.Equal(t, connection.BytesWritten, int64(len(upgrade)))
+	assert.Equal(t, connection.CloseReason, "client disconnected")
+}
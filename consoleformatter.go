@@ -0,0 +1,178 @@
+package glogger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats of the InitOptions.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// ConsoleFormatter formats the entries as human-readable lines for local development:
+// the time, the level, the message, a summary of the http field such as
+// "GET /api/v1/users 200 12ms", and the other fields as sorted key=value pairs.
+type ConsoleFormatter struct {
+	// DisableColors disables the colors of the levels.
+	DisableColors bool
+	// ColorProfile colors the levels and the values of the fields. If nil, the profile
+	// configured by the GLOGGER_COLORS environment variable is used, or the default one.
+	ColorProfile *ColorProfile
+}
+
+// Format formats the entry as a single line.
+func (formatter *ConsoleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := entry.Buffer
+
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	profile := formatter.colorProfile()
+
+	formatter.write(b, profile.Keys["time"], entry.Time.Format(time.RFC3339))
+	b.WriteByte(' ')
+	formatter.write(b, profile.Levels[entry.Level], fmt.Sprintf("%-5.5s", strings.ToUpper(entry.Level.String())))
+	b.WriteByte(' ')
+	b.WriteString(entry.Message)
+
+	if summary := httpSummary(entry.Data["http"]); summary != "" {
+		b.WriteByte(' ')
+		b.WriteString(summary)
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+
+	for key := range entry.Data {
+		if key != "http" {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value, err := consoleValue(entry.Data[key])
+
+		if err != nil {
+			return nil, formatError(entry, false, err)
+		}
+
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		formatter.write(b, profile.Keys[key], value)
+	}
+
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+func (formatter *ConsoleFormatter) write(b *bytes.Buffer, style string, value string) {
+	if formatter.DisableColors || style == "" {
+		b.WriteString(value)
+		return
+	}
+
+	b.WriteString(colorize(value, style))
+}
+
+func (formatter *ConsoleFormatter) colorProfile() *ColorProfile {
+	if formatter.ColorProfile != nil {
+		return formatter.ColorProfile
+	}
+
+	if profile := getEnvColorProfile(); profile != nil {
+		return profile
+	}
+
+	return defaultColorProfile
+}
+
+var defaultColorProfile = DefaultColorProfile()
+
+// httpSummary returns the summary of the http field: the method, the path, and the
+// status code and response time of the response, if any.
+func httpSummary(value interface{}) string {
+	var fields HTTP
+
+	switch value := value.(type) {
+	case HTTP:
+		fields = value
+	case *HTTP:
+		if value == nil {
+			return ""
+		}
+
+		fields = *value
+	default:
+		return ""
+	}
+
+	var parts []string
+
+	if request := fields.Request; request != nil {
+		parts = append(parts, request.Method, request.Path)
+	}
+
+	if response := fields.Response; response != nil {
+		if response.StatusCode != 0 {
+			parts = append(parts, strconv.Itoa(response.StatusCode))
+		}
+
+		if response.ResponseTime != 0 {
+			parts = append(parts, consoleDuration(response.ResponseTime).String())
+		}
+	}
+
+	return strings.Join(slices.DeleteFunc(parts, func(part string) bool { return part == "" }), " ")
+}
+
+// consoleDuration rounds the response time in seconds to the millisecond, or to the
+// microsecond below one millisecond.
+func consoleDuration(seconds float64) time.Duration {
+	duration := time.Duration(seconds * float64(time.Second))
+
+	if duration < time.Millisecond {
+		return duration.Round(time.Microsecond)
+	}
+
+	return duration.Round(time.Millisecond)
+}
+
+// consoleValue returns the value as is for the strings without spaces, quoted for the
+// other strings and the errors, and encoded in JSON otherwise.
+func consoleValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			return strconv.Quote(value), nil
+		}
+
+		return value, nil
+	case error:
+		return strconv.Quote(value.Error()), nil
+	case fmt.Stringer:
+		return strconv.Quote(value.String()), nil
+	}
+
+	b, err := appendJSONValue(nil, value, false)
+
+	return string(b), err
+}
+
+// isTerminal reports whether the file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,67 @@
+package glogger
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestConsoleFormatter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	t.Run("Entry is formatted as a single line", func(t *testing.T) {
+		entry := logrus.Entry{
+			Level:   logrus.InfoLevel,
+			Time:    now,
+			Message: "Completed Request",
+			Data: logrus.Fields{
+				"http": HTTP{
+					Request:  &Request{Method: "GET", Path: "/api/v1/users"},
+					Response: &Response{StatusCode: 200, ResponseTime: 0.0123},
+				},
+				"correlationId": "request-id",
+				"user":          "John Doe",
+				"error":         errors.New("failed"),
+				"host":          Host{IP: "192.0.2.1"},
+			},
+		}
+
+		data, err := (&ConsoleFormatter{DisableColors: true}).Format(&entry)
+
+		assert.NilError(t, err)
+		assert.Equal(t, string(data), `2024-01-02T15:04:05Z INFO  Completed Request GET /api/v1/users 200 12ms correlationId=request-id error="failed" host={"ip":"192.0.2.1"} user="John Doe"`+"\n")
+	})
+
+	t.Run("Level is colored", func(t *testing.T) {
+		entry := logrus.Entry{Level: logrus.ErrorLevel, Time: now, Message: "Failed"}
+		profile := &ColorProfile{Levels: map[logrus.Level]string{logrus.ErrorLevel: "31"}}
+
+		data, err := (&ConsoleFormatter{ColorProfile: profile}).Format(&entry)
+
+		assert.NilError(t, err)
+		assert.Equal(t, string(data), "2024-01-02T15:04:05Z \x1b[31mERROR\x1b[0m Failed\n")
+	})
+
+	t.Run("Sub-millisecond response times are rounded to the microsecond", func(t *testing.T) {
+		assert.Equal(t, httpSummary(HTTP{Response: &Response{StatusCode: 204, ResponseTime: 0.0004567}}), "204 457µs")
+	})
+}
+
+func TestInitFormat(t *testing.T) {
+	if isTerminal(os.Stderr) {
+		t.Skip("The output is a terminal")
+	}
+
+	logger, err := Init(InitOptions{Format: FormatConsole})
+	assert.NilError(t, err)
+
+	_, isJSON := logger.Formatter.(*JSONFormatter)
+	assert.Assert(t, isJSON, "Expected the JSON fallback when the output is not a terminal")
+
+	_, err = Init(InitOptions{Format: "xml"})
+	assert.ErrorContains(t, err, "invalid format")
+}
@@ -2,12 +2,32 @@ package glogger
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 )
 
 type loggerKey struct{}
 
+type fieldsKey struct{}
+
+type scopedFieldsKey struct{}
+
+// accumulatedFields holds the fields added to a context with AddFields.
+type accumulatedFields struct {
+	mutex  sync.Mutex
+	fields logrus.Fields
+}
+
+// scopedFields is a layer of fields added with Scoped, on top of the layers of the
+// enclosing scopes.
+type scopedFields struct {
+	parent *scopedFields
+	fields logrus.Fields
+	ended  atomic.Bool
+}
+
 var defaultLogger *logrus.Entry = logrus.NewEntry(logrus.StandardLogger())
 
 // WithLogger returns a new context with the provided logger
@@ -16,18 +36,129 @@ func WithLogger(ctx context.Context, logger *logrus.Entry) context.Context {
 }
 
 // Get retrivies the current logger from the context. If no logger is availabe, the default logger is returned.
+// The fields added to the context with AddFields, and the fields extracted from the context
+// by the extractors registered with RegisterContextField, are included in the returned logger.
+// The returned logger may be shared with other goroutines: its Data map must not be
+// modified, use WithFields or Child to add fields.
 func Get(ctx context.Context) *logrus.Entry {
 	logger := ctx.Value(loggerKey{})
 
 	if logger == nil {
-		return defaultLogger
+		return withContextFields(ctx, withScopedFields(ctx, withAccumulatedFields(ctx, defaultLogger)))
 	}
 
 	entry, ok := logger.(*logrus.Entry)
 
 	if !ok {
-		return defaultLogger
+		entry = defaultLogger
+	}
+
+	return withContextFields(ctx, withScopedFields(ctx, withAccumulatedFields(ctx, entry)))
+}
+
+// Child returns a new context with a child of the context logger, which has the fields
+// added, and the child logger itself. The child owns a copy of the fields: adding fields
+// to the child never affects its parent or siblings, which may be used concurrently, nor
+// the reverse. The fields added with AddFields are shared by the whole request.
+func Child(ctx context.Context, fields logrus.Fields) (context.Context, *logrus.Entry) {
+	logger, ok := ctx.Value(loggerKey{}).(*logrus.Entry)
+
+	if !ok {
+		logger = defaultLogger
+	}
+
+	return withChild(ctx, logger.WithFields(fields))
+}
+
+// withChild returns a new context with the child logger, and the child logger with the
+// fields of the context.
+func withChild(ctx context.Context, child *logrus.Entry) (context.Context, *logrus.Entry) {
+	return WithLogger(ctx, child), withContextFields(ctx, withScopedFields(ctx, withAccumulatedFields(ctx, child)))
+}
+
+// withFieldsAccumulator returns a new context able to accumulate the fields added with AddFields.
+func withFieldsAccumulator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &accumulatedFields{fields: logrus.Fields{}})
+}
+
+// AddFields adds the fields to the logger of the context, so that they are included in
+// the entries logged by every layer handling the request, including the completed
+// request entry of the middleware. It has no effect on contexts not created by the
+// middleware.
+func AddFields(ctx context.Context, fields logrus.Fields) {
+	accumulator, ok := ctx.Value(fieldsKey{}).(*accumulatedFields)
+
+	if !ok {
+		return
+	}
+
+	accumulator.mutex.Lock()
+	defer accumulator.mutex.Unlock()
+
+	for key, value := range fields {
+		accumulator.fields[key] = value
+	}
+}
+
+// Scoped returns a new context whose logger has the fields for a region of code, until
+// the returned function is called, so that the fields of each iteration of a loop do
+// not accumulate on the request logger. The fields override the fields added with
+// AddFields, but only in the returned context and the contexts derived from it: the
+// request logger, the other goroutines and the completed request entry never see them,
+// and nested or concurrent scopes do not affect each other. Calls to the function after
+// the first one have no effect.
+func Scoped(ctx context.Context, fields logrus.Fields) (context.Context, func()) {
+	parent, _ := ctx.Value(scopedFieldsKey{}).(*scopedFields)
+	layer := &scopedFields{parent: parent, fields: make(logrus.Fields, len(fields))}
+
+	for key, value := range fields {
+		layer.fields[key] = value
+	}
+
+	return context.WithValue(ctx, scopedFieldsKey{}, layer), func() {
+		layer.ended.Store(true)
+	}
+}
+
+// withScopedFields returns the entry with the fields of the scopes of the context which
+// have not ended, the innermost scope taking precedence.
+func withScopedFields(ctx context.Context, entry *logrus.Entry) *logrus.Entry {
+	var layers []*scopedFields
+
+	for layer, _ := ctx.Value(scopedFieldsKey{}).(*scopedFields); layer != nil; layer = layer.parent {
+		if !layer.ended.Load() {
+			layers = append(layers, layer)
+		}
+	}
+
+	if len(layers) == 0 {
+		return entry
+	}
+
+	fields := make(logrus.Fields)
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		for key, value := range layers[i].fields {
+			fields[key] = value
+		}
+	}
+
+	return entry.WithFields(fields)
+}
+
+func withAccumulatedFields(ctx context.Context, entry *logrus.Entry) *logrus.Entry {
+	accumulator, ok := ctx.Value(fieldsKey{}).(*accumulatedFields)
+
+	if !ok {
+		return entry
+	}
+
+	accumulator.mutex.Lock()
+	defer accumulator.mutex.Unlock()
+
+	if len(accumulator.fields) == 0 {
+		return entry
 	}
 
-	return entry
+	return entry.WithFields(accumulator.fields)
 }
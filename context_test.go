@@ -0,0 +1,153 @@
+package glogger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestChild(t *testing.T) {
+	t.Run("Child fields do not leak to the parent and siblings", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		parent := logger.WithField("correlationId", "1")
+		ctx := WithLogger(context.Background(), parent)
+
+		firstCtx, first := Child(ctx, logrus.Fields{"component": "first"})
+		_, second := Child(ctx, logrus.Fields{"component": "second"})
+		_, grandchild := Child(firstCtx, logrus.Fields{"step": 1})
+
+		assert.Equal(t, len(parent.Data), 1)
+		assert.Equal(t, first.Data["component"], "first")
+		assert.Equal(t, second.Data["component"], "second")
+		assert.Equal(t, grandchild.Data["component"], "first")
+		assert.Equal(t, grandchild.Data["correlationId"], "1")
+
+		_, ok := first.Data["step"]
+		assert.Assert(t, !ok, "Unexpected grandchild field in the child")
+		assert.Equal(t, Get(firstCtx).Data["component"], "first")
+	})
+
+	t.Run("Children can be used concurrently", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		ctx := WithLogger(withFieldsAccumulator(context.Background()), logger.WithField("correlationId", "1"))
+		AddFields(ctx, logrus.Fields{"userId": "42"})
+
+		var wait sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wait.Add(1)
+
+			go func(i int) {
+				defer wait.Done()
+
+				_, child := Child(ctx, logrus.Fields{"worker": i})
+				child.WithField("item", fmt.Sprint(i)).Info("Processing")
+			}(i)
+		}
+
+		wait.Wait()
+
+		for _, entry := range hook.AllEntries() {
+			assert.Equal(t, entry.Data["item"], fmt.Sprint(entry.Data["worker"]))
+			assert.Equal(t, entry.Data["userId"], "42")
+		}
+	})
+
+	t.Run("Child of a context without logger derives from the default logger", func(t *testing.T) {
+		_, child := Child(context.Background(), logrus.Fields{"component": "worker"})
+
+		assert.Equal(t, child.Logger, logrus.StandardLogger())
+		assert.Equal(t, len(defaultLogger.Data), 0)
+	})
+}
+
+func TestScoped(t *testing.T) {
+	t.Run("Fields are removed when the scope ends", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		ctx := WithLogger(withFieldsAccumulator(context.Background()), logger.WithField("correlationId", "1"))
+		AddFields(ctx, logrus.Fields{"userId": "42"})
+
+		for _, item := range []string{"a", "b"} {
+			itemCtx, done := Scoped(ctx, logrus.Fields{"item": item, "userId": "override"})
+
+			assert.Equal(t, Get(itemCtx).Data["item"], item)
+			assert.Equal(t, Get(itemCtx).Data["userId"], "override")
+
+			done()
+			done()
+		}
+
+		data := Get(ctx).Data
+		_, ok := data["item"]
+		assert.Assert(t, !ok, "Unexpected scoped field after the scope")
+		assert.Equal(t, data["userId"], "42")
+		assert.Equal(t, data["correlationId"], "1")
+	})
+
+	t.Run("Scopes do not leak into the request logger nor into each other", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		ctx := WithLogger(withFieldsAccumulator(context.Background()), logger.WithField("correlationId", "1"))
+
+		outerCtx, endOuter := Scoped(ctx, logrus.Fields{"batch": "1", "item": "none"})
+		firstCtx, endFirst := Scoped(outerCtx, logrus.Fields{"item": "a"})
+		secondCtx, endSecond := Scoped(outerCtx, logrus.Fields{"item": "b"})
+
+		_, ok := Get(ctx).Data["item"]
+		assert.Assert(t, !ok, "Unexpected scoped field on the request logger")
+		assert.Equal(t, Get(firstCtx).Data["item"], "a")
+		assert.Equal(t, Get(secondCtx).Data["item"], "b")
+		assert.Equal(t, Get(secondCtx).Data["batch"], "1")
+
+		endFirst()
+		assert.Equal(t, Get(firstCtx).Data["item"], "none")
+		assert.Equal(t, Get(secondCtx).Data["item"], "b")
+
+		endOuter()
+		_, ok = Get(secondCtx).Data["batch"]
+		assert.Assert(t, !ok, "Unexpected field of an ended scope")
+		assert.Equal(t, Get(secondCtx).Data["item"], "b")
+
+		endSecond()
+	})
+
+	t.Run("Concurrent scopes keep their own fields", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		ctx := WithLogger(withFieldsAccumulator(context.Background()), logger.WithField("correlationId", "1"))
+
+		var group sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			group.Add(1)
+
+			go func(item int) {
+				defer group.Done()
+
+				itemCtx, done := Scoped(ctx, logrus.Fields{"item": item})
+				defer done()
+
+				for j := 0; j < 100; j++ {
+					assert.Check(t, Get(itemCtx).Data["item"] == item)
+				}
+			}(i)
+		}
+
+		group.Wait()
+	})
+
+	t.Run("Scoped fields on a context without the middleware", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		ctx := WithLogger(context.Background(), logger.WithField("correlationId", "1"))
+
+		itemCtx, done := Scoped(ctx, logrus.Fields{"item": "a"})
+		defer done()
+
+		assert.Equal(t, Get(itemCtx).Data["item"], "a")
+		_, ok := Get(ctx).Data["item"]
+		assert.Assert(t, !ok, "Unexpected scoped field on the parent")
+	})
+}
@@ -0,0 +1,50 @@
+package glogger
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ContextFieldFunc extracts a field from a value of the context, such as the user set by
+// an authentication middleware. It reports false when the value is not in the context.
+type ContextFieldFunc func(ctx context.Context) (key string, value interface{}, ok bool)
+
+var (
+	contextFieldsMutex sync.RWMutex
+	contextFields      []ContextFieldFunc
+)
+
+// RegisterContextField registers an extractor of a context value, evaluated by Get with
+// its context, so that the values set in the context by other middlewares are logged
+// without passing them to the logger. It is meant to be called at initialization.
+func RegisterContextField(extractor ContextFieldFunc) {
+	contextFieldsMutex.Lock()
+	defer contextFieldsMutex.Unlock()
+
+	contextFields = append(contextFields, extractor)
+}
+
+func withContextFields(ctx context.Context, entry *logrus.Entry) *logrus.Entry {
+	contextFieldsMutex.RLock()
+	defer contextFieldsMutex.RUnlock()
+
+	if len(contextFields) == 0 {
+		return entry
+	}
+
+	fields := logrus.Fields{}
+
+	for _, extractor := range contextFields {
+		if key, value, ok := extractor(ctx); ok {
+			fields[key] = value
+		}
+	}
+
+	if len(fields) == 0 {
+		return entry
+	}
+
+	return entry.WithFields(fields)
+}
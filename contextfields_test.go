@@ -0,0 +1,33 @@
+package glogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+type tenantKey struct{}
+
+func TestRegisterContextField(t *testing.T) {
+	defer func(extractors []ContextFieldFunc) {
+		contextFields = extractors
+	}(contextFields)
+
+	RegisterContextField(func(ctx context.Context) (string, interface{}, bool) {
+		tenant, ok := ctx.Value(tenantKey{}).(string)
+		return "tenant", tenant, ok
+	})
+
+	logger, _ := test.NewNullLogger()
+	ctx := WithLogger(context.Background(), logger.WithField("correlationId", "1"))
+
+	_, ok := Get(ctx).Data["tenant"]
+	assert.Assert(t, !ok, "Unexpected tenant field")
+
+	tenantCtx := context.WithValue(ctx, tenantKey{}, "acme")
+
+	assert.Equal(t, Get(tenantCtx).Data["tenant"], "acme")
+	assert.Equal(t, Get(tenantCtx).Data["correlationId"], "1")
+}
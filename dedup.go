@@ -0,0 +1,224 @@
+package glogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	repeatedCountKey = "repeatedCount"
+	maxDedupEntries  = 10000
+)
+
+// DedupOptions is the struct of options to configure the deduplication of the entries.
+type DedupOptions struct {
+	// Window is the duration for which the identical entries are collapsed. When it
+	// closes, the first entry dropped is written with the repeatedCount field. The
+	// deduplication is disabled if zero.
+	Window time.Duration
+	// Keys are the fields which, along with the level and the message, identify the
+	// identical entries, such as "error". The other fields are ignored.
+	Keys []string
+}
+
+// dedupFormatter collapses the identical entries: the first entry is written, and the
+// identical entries are dropped until the window elapsed. When the window closes, the
+// first entry dropped is written again with the repeatedCount field, the number of entries
+// dropped. The dropped entries are formatted as no bytes, which the writer returned by
+// output doesn't write.
+type dedupFormatter struct {
+	formatter logrus.Formatter
+	window    time.Duration
+	keys      []string
+	now       func() time.Time
+	// afterFunc calls f once d elapsed, returning the function stopping the call.
+	afterFunc func(d time.Duration, f func()) func() bool
+	mutex     sync.Mutex
+	entries   map[string]*dedupEntry
+	// writeMutex serializes the formatting and the writes of the entries with the ones of
+	// the counts written when the windows close.
+	writeMutex sync.Mutex
+	out        io.Writer
+}
+
+type dedupEntry struct {
+	start   time.Time
+	dropped int
+	// first is the first entry dropped, written with the count when the window closes.
+	first *logrus.Entry
+	stop  func() bool
+	// reported is whether the count was written, when the window closed or with the next
+	// identical entry.
+	reported bool
+}
+
+func newDedupFormatter(formatter logrus.Formatter, options DedupOptions, now func() time.Time) *dedupFormatter {
+	return &dedupFormatter{
+		formatter: formatter,
+		window:    options.Window,
+		keys:      options.Keys,
+		now:       now,
+		afterFunc: func(d time.Duration, f func()) func() bool {
+			return time.AfterFunc(d, f).Stop
+		},
+		entries: map[string]*dedupEntry{},
+	}
+}
+
+// output returns the writer of the entries formatted to out, which drops the empty
+// writes of the dropped entries. The counts are written to out when the windows close.
+func (formatter *dedupFormatter) output(out io.Writer) io.Writer {
+	formatter.out = out
+
+	return &dedupWriter{formatter: formatter}
+}
+
+// Format formats the entry with the wrapped formatter, or returns no bytes if the entry
+// is dropped.
+func (formatter *dedupFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	dropped, ok := formatter.dedup(entry)
+
+	if !ok {
+		return nil, nil
+	}
+
+	formatter.writeMutex.Lock()
+	defer formatter.writeMutex.Unlock()
+
+	if dropped == 0 {
+		return formatter.formatter.Format(entry)
+	}
+
+	fields := entry.Data
+	entry.Data = withField(fields, repeatedCountKey, dropped)
+	defer func() { entry.Data = fields }()
+
+	return formatter.formatter.Format(entry)
+}
+
+// dedup returns whether the entry is written, and the number of identical entries
+// dropped before it.
+func (formatter *dedupFormatter) dedup(entry *logrus.Entry) (int, bool) {
+	key := formatter.key(entry)
+
+	formatter.mutex.Lock()
+	defer formatter.mutex.Unlock()
+
+	current := formatter.now()
+	seen, ok := formatter.entries[key]
+
+	if ok && current.Sub(seen.start) < formatter.window {
+		seen.dropped++
+
+		if seen.first == nil && formatter.out != nil {
+			seen.first = &logrus.Entry{
+				Logger:  entry.Logger,
+				Data:    withField(entry.Data, repeatedCountKey, 0),
+				Level:   entry.Level,
+				Message: entry.Message,
+				Context: entry.Context,
+			}
+			seen.stop = formatter.afterFunc(seen.start.Add(formatter.window).Sub(current), func() {
+				formatter.close(key, seen)
+			})
+		}
+
+		return 0, false
+	}
+
+	if ok {
+		// The window elapsed before it was closed: the count is written with the entry.
+		seen.reported = true
+
+		if seen.stop != nil {
+			seen.stop()
+		}
+	}
+
+	if !ok && len(formatter.entries) >= maxDedupEntries {
+		formatter.entries = map[string]*dedupEntry{}
+	}
+
+	formatter.entries[key] = &dedupEntry{start: current}
+
+	if !ok {
+		return 0, true
+	}
+
+	return seen.dropped, true
+}
+
+// close closes the window of the entry, writing the count of the entries dropped.
+func (formatter *dedupFormatter) close(key string, seen *dedupEntry) {
+	formatter.mutex.Lock()
+
+	if seen.reported {
+		formatter.mutex.Unlock()
+		return
+	}
+
+	seen.reported = true
+
+	if formatter.entries[key] == seen {
+		delete(formatter.entries, key)
+	}
+
+	summary := seen.first
+	summary.Data[repeatedCountKey] = seen.dropped
+	summary.Time = formatter.now()
+	formatter.mutex.Unlock()
+
+	formatter.writeMutex.Lock()
+	defer formatter.writeMutex.Unlock()
+
+	serialized, err := formatter.formatter.Format(summary)
+
+	if err == nil {
+		_, err = formatter.out.Write(serialized)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+}
+
+func (formatter *dedupFormatter) key(entry *logrus.Entry) string {
+	var key strings.Builder
+
+	key.WriteString(entry.Level.String())
+	key.WriteByte(0)
+	key.WriteString(entry.Message)
+
+	for _, field := range formatter.keys {
+		key.WriteByte(0)
+
+		if value, ok := entry.Data[field]; ok {
+			fmt.Fprint(&key, value)
+		}
+	}
+
+	return key.String()
+}
+
+// dedupWriter writes the entries formatted by a dedupFormatter, dropping the empty writes
+// of the dropped entries so that the outputs don't receive them.
+type dedupWriter struct {
+	formatter *dedupFormatter
+}
+
+func (writer *dedupWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	writer.formatter.writeMutex.Lock()
+	defer writer.formatter.writeMutex.Unlock()
+
+	return writer.formatter.out.Write(p)
+}
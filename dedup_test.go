@@ -0,0 +1,120 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestDedupFormatter(t *testing.T) {
+	now := time.Now()
+
+	var output bytes.Buffer
+	var closes []func()
+	logger := logrus.New()
+	formatter := newDedupFormatter(&JSONFormatter{}, DedupOptions{Window: time.Second, Keys: []string{"error"}}, func() time.Time { return now })
+	formatter.afterFunc = func(d time.Duration, f func()) func() bool {
+		assert.Equal(t, d, time.Second)
+		closes = append(closes, f)
+		return func() bool { return true }
+	}
+	logger.SetFormatter(formatter)
+	logger.SetOutput(formatter.output(&output))
+
+	for i := 0; i < 3; i++ {
+		logger.WithError(errors.New("connection refused")).WithField("attempt", i).Error("Failed to connect")
+	}
+
+	logger.WithError(errors.New("timeout")).Error("Failed to connect")
+	logger.Warn("Failed to connect")
+
+	now = now.Add(time.Second)
+	logger.WithError(errors.New("connection refused")).Error("Failed to connect")
+	logger.WithError(errors.New("connection refused")).Error("Failed to connect")
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, len(lines), 4)
+
+	var entries []map[string]interface{}
+
+	for _, line := range lines {
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	assert.Equal(t, entries[0]["attempt"], float64(0))
+	assert.Equal(t, entries[0]["repeatedCount"], nil)
+	assert.Equal(t, entries[1]["error"], "timeout")
+	assert.Equal(t, entries[2]["level"], "warning")
+	assert.Equal(t, entries[3]["repeatedCount"], float64(2))
+
+	t.Run("The count is written when the window closes", func(t *testing.T) {
+		output.Reset()
+		closes = nil
+
+		for i := 0; i < 3; i++ {
+			logger.WithError(errors.New("connection reset")).WithField("attempt", i).Error("Failed to connect")
+		}
+
+		assert.Equal(t, strings.Count(output.String(), "\n"), 1)
+		assert.Equal(t, len(closes), 1)
+
+		closes[0]()
+		closes[0]()
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		assert.Equal(t, len(lines), 2)
+
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assert.Equal(t, entry["repeatedCount"], float64(2))
+		assert.Equal(t, entry["attempt"], float64(1))
+		assert.Equal(t, entry["error"], "connection reset")
+
+		logger.WithError(errors.New("connection reset")).Error("Failed to connect")
+		assert.Equal(t, strings.Count(output.String(), "\n"), 3)
+		assert.Assert(t, !strings.Contains(strings.Split(strings.TrimSpace(output.String()), "\n")[2], "repeatedCount"))
+	})
+}
+
+// writesRecorder records the writes.
+type writesRecorder struct {
+	writes [][]byte
+}
+
+func (recorder *writesRecorder) Write(p []byte) (int, error) {
+	recorder.writes = append(recorder.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func TestInitDedup(t *testing.T) {
+	var output bytes.Buffer
+
+	logger, err := Init(InitOptions{Output: &output, Dedup: DedupOptions{Window: time.Minute}})
+	assert.NilError(t, err)
+
+	logger.Info("Hello")
+	logger.Info("Hello")
+
+	assert.Equal(t, strings.Count(output.String(), "\n"), 1)
+
+	t.Run("The dropped entries are not written", func(t *testing.T) {
+		recorder := &writesRecorder{}
+
+		logger, err := Init(InitOptions{Output: recorder, Dedup: DedupOptions{Window: time.Minute}})
+		assert.NilError(t, err)
+
+		for i := 0; i < 3; i++ {
+			logger.Info("Hello")
+		}
+
+		assert.Equal(t, len(recorder.writes), 1)
+	})
+}
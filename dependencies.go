@@ -0,0 +1,84 @@
+package glogger
+
+import (
+	"context"
+	"sync"
+)
+
+const dependenciesKey = "dependencies"
+
+// DependencyCall struct contains items of downstream call info log. The error is set for
+// the failed calls.
+type DependencyCall struct {
+	Name         string  `json:"name"`
+	ResponseTime float64 `json:"responseTime"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// Dependencies struct contains items of downstream calls summary info log.
+type Dependencies struct {
+	Calls        int             `json:"calls"`
+	Failures     int             `json:"failures"`
+	Slowest      *DependencyCall `json:"slowest,omitempty"`
+	FirstFailure *DependencyCall `json:"firstFailure,omitempty"`
+}
+
+type dependenciesKeyType struct{}
+
+type dependencies struct {
+	mutex   sync.Mutex
+	summary Dependencies
+}
+
+// withDependencies returns a new context able to summarize the calls of RecordDependency.
+func withDependencies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dependenciesKeyType{}, &dependencies{})
+}
+
+// RecordDependency records a call to a downstream dependency in the dependencies field of
+// the completed request entry, which summarizes the calls of the request: their count,
+// the failures, the slowest call and the first failure, so that a single entry explains
+// why the request was slow or failed. The RoundTripper and the grpclogger client
+// interceptor record their calls. It has no effect on contexts not created by the
+// middleware.
+func RecordDependency(ctx context.Context, call DependencyCall) {
+	recorded, ok := ctx.Value(dependenciesKeyType{}).(*dependencies)
+
+	if !ok {
+		return
+	}
+
+	recorded.mutex.Lock()
+	defer recorded.mutex.Unlock()
+
+	summary := &recorded.summary
+	summary.Calls++
+
+	if summary.Slowest == nil || call.ResponseTime > summary.Slowest.ResponseTime {
+		slowest := call
+		summary.Slowest = &slowest
+	}
+
+	if call.Error != "" {
+		summary.Failures++
+
+		if summary.FirstFailure == nil {
+			failure := call
+			summary.FirstFailure = &failure
+		}
+	}
+}
+
+// dependenciesFromContext returns the summary of the calls recorded in the context, if any.
+func dependenciesFromContext(ctx context.Context) (Dependencies, bool) {
+	recorded, ok := ctx.Value(dependenciesKeyType{}).(*dependencies)
+
+	if !ok {
+		return Dependencies{}, false
+	}
+
+	recorded.mutex.Lock()
+	defer recorded.mutex.Unlock()
+
+	return recorded.summary, recorded.summary.Calls > 0
+}
@@ -0,0 +1,80 @@
+package glogger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestDependencies(t *testing.T) {
+	t.Run("Downstream calls are summarized in the completed request entry", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		transport := NewRoundTripper(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Host {
+			case "payments:8080":
+				return nil, errors.New("connection refused")
+			case "inventory:8080":
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Body: http.NoBody}, nil
+			default:
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			}
+		}))
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			for _, url := range []string{"http://users:8080/", "http://payments:8080/", "http://inventory:8080/"} {
+				request, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
+
+				if response, err := transport.RoundTrip(request); err == nil {
+					response.Body.Close()
+				}
+			}
+
+			RecordDependency(r.Context(), DependencyCall{Name: "postgres", ResponseTime: 60})
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		dependencies := hook.LastEntry().Data["dependencies"].(Dependencies)
+
+		assert.Equal(t, dependencies.Calls, 4)
+		assert.Equal(t, dependencies.Failures, 2)
+		assert.DeepEqual(t, dependencies.Slowest, &DependencyCall{Name: "postgres", ResponseTime: 60})
+		assert.Equal(t, dependencies.FirstFailure.Name, "payments")
+		assert.Equal(t, dependencies.FirstFailure.Error, "connection refused")
+	})
+
+	t.Run("Server errors are failures", func(t *testing.T) {
+		ctx := withDependencies(context.Background())
+
+		RecordDependency(ctx, DependencyCall{Name: "users", ResponseTime: 0.1})
+		RecordDependency(ctx, DependencyCall{Name: "inventory", ResponseTime: 0.2, Error: "503 Service Unavailable"})
+
+		dependencies, ok := dependenciesFromContext(ctx)
+		assert.Assert(t, ok)
+		assert.DeepEqual(t, dependencies, Dependencies{
+			Calls:        2,
+			Failures:     1,
+			Slowest:      &DependencyCall{Name: "inventory", ResponseTime: 0.2, Error: "503 Service Unavailable"},
+			FirstFailure: &DependencyCall{Name: "inventory", ResponseTime: 0.2, Error: "503 Service Unavailable"},
+		})
+	})
+
+	t.Run("Summary is omitted without calls", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		_, ok := hook.LastEntry().Data["dependencies"]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("Calls are ignored outside of the middleware", func(t *testing.T) {
+		RecordDependency(context.Background(), DependencyCall{Name: "users"})
+	})
+}
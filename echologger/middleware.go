@@ -0,0 +1,53 @@
+// Package echologger provides an echo middleware logging the requests with the glogger
+// middleware, including the matched echo route pattern.
+package echologger
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+)
+
+type routeKey struct{}
+
+func routePattern(r *http.Request) string {
+	route, _ := r.Context().Value(routeKey{}).(string)
+	return route
+}
+
+// Logger returns an echo middleware logging the requests, like glogger.LoggingMiddlewareWithOptions.
+// The echo route pattern, such as /users/:id, is logged in http.request.route unless
+// options.RoutePattern is set. The errors returned by the handlers are passed to the
+// echo HTTP error handler, so that the response status is logged. The request scoped
+// logger is available with glogger.Get(c.Request().Context()).
+func Logger(logger *logrus.Logger, options glogger.MiddlewareOptions) echo.MiddlewareFunc {
+	if options.RoutePattern == nil {
+		options.RoutePattern = routePattern
+	}
+
+	middleware := glogger.LoggingMiddlewareWithOptions(logger, options)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			writer := c.Response().Writer
+
+			handler := middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				c.Response().Writer = rw
+
+				if err := next(c); err != nil {
+					c.Error(err)
+				}
+			}))
+
+			request := c.Request()
+			handler.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), routeKey{}, c.Path())))
+			c.Response().Writer = writer
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package echologger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLogger(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	e := echo.New()
+	e.Use(Logger(logger, glogger.MiddlewareOptions{}))
+	e.GET("/users/:id", func(c echo.Context) error {
+		glogger.Get(c.Request().Context()).Info("Handling Request")
+		return c.String(http.StatusAccepted, "user "+c.Param("id"))
+	})
+	e.GET("/missing/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound)
+	})
+
+	t.Run("Route pattern is logged", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		assert.Equal(t, recorder.Code, http.StatusAccepted)
+
+		entries := hook.AllEntries()
+		assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+		assert.Equal(t, entries[0].Data["correlationId"], entries[1].Data["correlationId"])
+
+		httpField := entries[1].Data["http"].(glogger.HTTP)
+		assert.Equal(t, httpField.Request.Route, "/users/:id")
+		assert.Equal(t, httpField.Response.StatusCode, 202)
+		assert.Equal(t, httpField.Response.Bytes, 7)
+	})
+
+	t.Run("Handler errors are logged with their status", func(t *testing.T) {
+		hook.Reset()
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing/42", nil))
+
+		assert.Equal(t, recorder.Code, http.StatusNotFound)
+		assert.Equal(t, hook.LastEntry().Data["http"].(glogger.HTTP).Response.StatusCode, 404)
+	})
+}
@@ -0,0 +1,195 @@
+package glogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Environment variables read by InitFromEnv.
+const (
+	// LogProfileEnv is the profile: "production", "development" or "test".
+	LogProfileEnv = "LOG_PROFILE"
+	// LogLevelEnv is the level, such as "info".
+	LogLevelEnv = "LOG_LEVEL"
+	// LogFormatEnv is the format: "json" or "console".
+	LogFormatEnv = "LOG_FORMAT"
+	// LogOutputEnv is the output: "stderr", "stdout", an inherited file descriptor selected
+	// by number or systemd name, such as "fd:3" or "fd:log", or the path of a file, opened
+	// in append mode.
+	LogOutputEnv = "LOG_OUTPUT"
+	// LogProcessFieldsEnv enables the process fields, as parsed by strconv.ParseBool.
+	LogProcessFieldsEnv = "LOG_PROCESS_FIELDS"
+	// LogSamplingEnv is the sampling of the request entries, as a comma separated list of
+	// "rate=<fraction>", "limit=<requests per second>" and "burst=<requests>" items.
+	LogSamplingEnv = "LOG_SAMPLING"
+	// LogConstrainedEnv enables the constrained mode of InitOptions, as parsed by
+	// strconv.ParseBool. LOG_OUTPUT can't be a file path then.
+	LogConstrainedEnv = "LOG_CONSTRAINED"
+)
+
+// InitFromEnv inits the logger configured by the LOG_PROFILE, LOG_LEVEL, LOG_FORMAT,
+// LOG_OUTPUT, LOG_PROCESS_FIELDS and LOG_CONSTRAINED environment variables. The unset
+// variables keep the defaults of Init, and the invalid ones are reported in the returned
+// error, LOG_SAMPLING included although it is read by SamplingOptionsFromEnv.
+func InitFromEnv() (*logrus.Logger, error) {
+	options, err := InitOptionsFromEnv()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Init(options)
+}
+
+// InitOptionsFromEnv returns the InitOptions configured by the environment variables,
+// validated along with LOG_SAMPLING. The file of LOG_OUTPUT is only opened if every variable is valid.
+func InitOptionsFromEnv() (InitOptions, error) {
+	var options InitOptions
+	var errs []error
+
+	if profile, ok := os.LookupEnv(LogProfileEnv); ok {
+		if _, err := (InitOptions{Profile: profile}).withProfile(); err != nil {
+			errs = append(errs, envError(LogProfileEnv, profile, fmt.Errorf("must be %q, %q or %q", ProfileProduction, ProfileDevelopment, ProfileTest)))
+		}
+
+		options.Profile = profile
+	}
+
+	if level, ok := os.LookupEnv(LogLevelEnv); ok {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			errs = append(errs, envError(LogLevelEnv, level, err))
+		}
+
+		options.Level = level
+	}
+
+	if format, ok := os.LookupEnv(LogFormatEnv); ok {
+		if format != FormatJSON && format != FormatConsole {
+			errs = append(errs, envError(LogFormatEnv, format, fmt.Errorf("must be %q or %q", FormatJSON, FormatConsole)))
+		}
+
+		options.Format = format
+	}
+
+	if value, ok := os.LookupEnv(LogProcessFieldsEnv); ok {
+		processFields, err := strconv.ParseBool(value)
+
+		if err != nil {
+			errs = append(errs, envError(LogProcessFieldsEnv, value, errors.New("must be a boolean")))
+		}
+
+		options.ProcessFields = processFields
+	}
+
+	if value, ok := os.LookupEnv(LogConstrainedEnv); ok {
+		constrained, err := strconv.ParseBool(value)
+
+		if err != nil {
+			errs = append(errs, envError(LogConstrainedEnv, value, errors.New("must be a boolean")))
+		}
+
+		options.Constrained = constrained
+	}
+
+	// The sampling applies to the middleware, configured with SamplingOptionsFromEnv, but
+	// an invalid value fails the startup like the other variables.
+	if _, err := SamplingOptionsFromEnv(); err != nil {
+		errs = append(errs, err)
+	}
+
+	output, hasOutput := os.LookupEnv(LogOutputEnv)
+	isFile := hasOutput && output != "stderr" && output != "stdout" && !strings.HasPrefix(output, fdOutputPrefix)
+
+	if hasOutput && (output == "" || output == fdOutputPrefix) {
+		errs = append(errs, envError(LogOutputEnv, output, errors.New("must be stderr, stdout, fd:<descriptor> or a file path")))
+	} else if isFile && options.Constrained {
+		errs = append(errs, envError(LogOutputEnv, output, errors.New("must be stderr, stdout or fd:<descriptor> in constrained mode")))
+	}
+
+	if len(errs) > 0 {
+		return InitOptions{}, errors.Join(errs...)
+	}
+
+	switch {
+	case !hasOutput || output == "stderr":
+	case output == "stdout":
+		options.Output = os.Stdout
+	case strings.HasPrefix(output, fdOutputPrefix):
+		file, err := OpenInheritedFD(strings.TrimPrefix(output, fdOutputPrefix))
+
+		if err != nil {
+			return InitOptions{}, envError(LogOutputEnv, output, err)
+		}
+
+		options.Output = file
+	default:
+		file, err := os.OpenFile(output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+
+		if err != nil {
+			return InitOptions{}, envError(LogOutputEnv, output, err)
+		}
+
+		options.Output = file
+	}
+
+	return options, nil
+}
+
+// SamplingOptionsFromEnv returns the SamplingOptions configured by LOG_SAMPLING, e.g.
+// "rate=0.1,limit=100", validated. It returns no sampling if the variable is unset.
+func SamplingOptionsFromEnv() (SamplingOptions, error) {
+	var options SamplingOptions
+
+	value, ok := os.LookupEnv(LogSamplingEnv)
+
+	if !ok {
+		return options, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		name, setting, _ := strings.Cut(item, "=")
+		var err error
+
+		switch name {
+		case "rate":
+			options.Rate, err = strconv.ParseFloat(setting, 64)
+
+			if err == nil && (options.Rate <= 0 || options.Rate > 1) {
+				err = errors.New("rate must be in (0, 1]")
+			}
+		case "limit":
+			options.Limit, err = strconv.ParseFloat(setting, 64)
+
+			if err == nil && options.Limit < 0 {
+				err = errors.New("limit must not be negative")
+			}
+		case "burst":
+			options.Burst, err = strconv.Atoi(setting)
+
+			if err == nil && options.Burst < 0 {
+				err = errors.New("burst must not be negative")
+			}
+		default:
+			err = fmt.Errorf("unknown item %q", item)
+		}
+
+		if err != nil {
+			return SamplingOptions{}, envError(LogSamplingEnv, value, err)
+		}
+	}
+
+	return options, nil
+}
+
+func envError(name string, value string, err error) error {
+	return fmt.Errorf("invalid %s %q: %w", name, value, err)
+}
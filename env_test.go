@@ -0,0 +1,194 @@
+package glogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestInitFromEnv(t *testing.T) {
+	t.Run("Logger is configured by the environment", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "app.log")
+		t.Setenv(LogLevelEnv, "debug")
+		t.Setenv(LogFormatEnv, "json")
+		t.Setenv(LogOutputEnv, output)
+		t.Setenv(LogProcessFieldsEnv, "true")
+
+		logger, err := InitFromEnv()
+		assert.NilError(t, err)
+		assert.Equal(t, logger.Level, logrus.DebugLevel)
+
+		logger.Debug("Configured")
+		logger.Out.(*os.File).Close()
+
+		data, err := os.ReadFile(output)
+		assert.NilError(t, err)
+		assert.Assert(t, len(data) > 0)
+
+		entry := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal(data, &entry))
+		assert.Equal(t, entry["message"], "Configured")
+		assert.Assert(t, entry["instanceId"] != nil)
+	})
+
+	t.Run("Every invalid variable is reported", func(t *testing.T) {
+		t.Setenv(LogLevelEnv, "verbose")
+		t.Setenv(LogFormatEnv, "xml")
+		t.Setenv(LogProcessFieldsEnv, "maybe")
+		t.Setenv(LogProfileEnv, "staging")
+		t.Setenv(LogSamplingEnv, "rate=2")
+
+		_, err := InitFromEnv()
+		assert.ErrorContains(t, err, `invalid LOG_LEVEL "verbose"`)
+		assert.ErrorContains(t, err, `invalid LOG_FORMAT "xml"`)
+		assert.ErrorContains(t, err, `invalid LOG_PROCESS_FIELDS "maybe"`)
+		assert.ErrorContains(t, err, `invalid LOG_PROFILE "staging"`)
+		assert.ErrorContains(t, err, `invalid LOG_SAMPLING "rate=2"`)
+	})
+
+	t.Run("Defaults are kept without variables", func(t *testing.T) {
+		for _, name := range []string{LogProfileEnv, LogLevelEnv, LogFormatEnv, LogOutputEnv, LogProcessFieldsEnv, LogConstrainedEnv, LogSamplingEnv} {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+
+		options, err := InitOptionsFromEnv()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, options, InitOptions{})
+	})
+}
+
+func TestSamplingOptionsFromEnv(t *testing.T) {
+	t.Setenv(LogSamplingEnv, "rate=0.1, limit=100,burst=20")
+
+	options, err := SamplingOptionsFromEnv()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, options, SamplingOptions{Rate: 0.1, Limit: 100, Burst: 20})
+
+	t.Setenv(LogSamplingEnv, "rate=2")
+	_, err = SamplingOptionsFromEnv()
+	assert.ErrorContains(t, err, `invalid LOG_SAMPLING "rate=2": rate must be in (0, 1]`)
+
+	t.Setenv(LogSamplingEnv, "ratio=0.5")
+	_, err = SamplingOptionsFromEnv()
+	assert.ErrorContains(t, err, `unknown item "ratio=0.5"`)
+}
+
+// countingTransport counts the HTTP requests, failing them.
+type countingTransport struct {
+	requests atomic.Int64
+}
+
+func (transport *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	transport.requests.Add(1)
+	return nil, errors.New("network access")
+}
+
+func TestConstrainedMode(t *testing.T) {
+	t.Run("Nothing but the provided writer is used", func(t *testing.T) {
+		var dials atomic.Int64
+
+		resolver := net.DefaultResolver
+		net.DefaultResolver = &net.Resolver{PreferGo: true, Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dials.Add(1)
+			return nil, errors.New("network access")
+		}}
+		defer func() { net.DefaultResolver = resolver }()
+
+		transport := &countingTransport{}
+		defaultTransport := http.DefaultTransport
+		http.DefaultTransport = transport
+		defer func() { http.DefaultTransport = defaultTransport }()
+
+		// The working and temporary directories are read-only.
+		dir := t.TempDir()
+		assert.NilError(t, os.Chmod(dir, 0o555))
+		defer os.Chmod(dir, 0o755)
+
+		wd, err := os.Getwd()
+		assert.NilError(t, err)
+		assert.NilError(t, os.Chdir(dir))
+		defer os.Chdir(wd)
+
+		t.Setenv("TMPDIR", dir)
+		t.Setenv(LogConstrainedEnv, "true")
+		t.Setenv(LogOutputEnv, "stderr")
+		t.Setenv(LogFormatEnv, FormatConsole)
+		t.Setenv(LogProcessFieldsEnv, "true")
+
+		options, err := InitOptionsFromEnv()
+		assert.NilError(t, err)
+		assert.Assert(t, options.Constrained)
+
+		var output, audit bytes.Buffer
+		options.Output = &output
+		options.Caller = true
+
+		logger, err := Init(options)
+		assert.NilError(t, err)
+
+		_, isJSON := logger.Formatter.(*JSONFormatter)
+		assert.Assert(t, isJSON, "Expected the JSON format in constrained mode")
+
+		middleware := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{
+			AuditLogger:       NewAuditLogger(AuditOptions{Output: &audit, HashChain: true}),
+			IdempotencyWindow: time.Minute,
+			BodyHash:          true,
+			Timing:            true,
+		})
+		handler := middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			io.ReadAll(r.Body)
+			Audit(r.Context()).Record(AuditEvent{Actor: "user-1", Action: "create", Resource: "document", Outcome: AuditOutcomeSuccess})
+			Get(r.Context()).Warn("Handled")
+			rw.WriteHeader(http.StatusCreated)
+		}))
+
+		request := httptest.NewRequest(http.MethodPost, "http://api.example.com/documents", strings.NewReader(`{"title":"report"}`))
+		request.Header.Set("Idempotency-Key", "key")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Assert(t, strings.Contains(output.String(), `"message":"Handled"`), output.String())
+		assert.Assert(t, strings.Contains(audit.String(), `"action":"create"`), audit.String())
+		assert.Equal(t, dials.Load(), int64(0))
+		assert.Equal(t, transport.requests.Load(), int64(0))
+
+		entries, err := os.ReadDir(dir)
+		assert.NilError(t, err)
+		assert.Equal(t, len(entries), 0)
+	})
+
+	t.Run("Outputs creating files are rejected", func(t *testing.T) {
+		t.Setenv(LogConstrainedEnv, "true")
+		t.Setenv(LogOutputEnv, filepath.Join(t.TempDir(), "app.log"))
+
+		_, err := InitOptionsFromEnv()
+		assert.ErrorContains(t, err, "must be stderr, stdout or fd:<descriptor> in constrained mode")
+
+		file, err := NewRotatingFile(RotatingFileOptions{Filename: filepath.Join(t.TempDir(), "app.log")})
+		assert.NilError(t, err)
+		defer file.Close()
+
+		_, err = Init(InitOptions{Constrained: true, Outputs: []Output{{Writer: os.Stdout}, {Writer: file, Level: "error"}}})
+		assert.ErrorContains(t, err, "a rotating file creates files")
+
+		writer := NewAsyncWriter(NewCoalescingWriter(file, CoalescingWriterOptions{}), AsyncWriterOptions{})
+		defer writer.Close()
+
+		_, err = Init(InitOptions{Constrained: true, Output: writer})
+		assert.ErrorContains(t, err, "a rotating file creates files")
+	})
+}
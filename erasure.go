@@ -0,0 +1,244 @@
+package glogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErasureOptions is the struct of options to configure the erasure of a data subject
+// from the log archives, such as for a GDPR erasure request.
+type ErasureOptions struct {
+	// Subjects are the values identifying the subject in the entries, such as its
+	// pseudonym returned by Pseudonymizer.Pseudonym, its user ID or its email. The entries
+	// with a string value equal to one of them match, whether the value is HTML-escaped or
+	// not. The subjects within longer strings, such as in the messages, are not matched.
+	Subjects []string
+	// Remove removes the matching entries from the archives. By default the values of the
+	// subject are replaced with [REDACTED] in the matching entries, keeping the other
+	// fields.
+	Remove bool
+}
+
+// ErrActiveFile is returned by EraseSubject for the file being written by a RotatingFile.
+var ErrActiveFile = errors.New("erasure: the file is being written, erase it with RotatingFile.EraseSubject")
+
+// EraseSubject rewrites the archive of newline-delimited JSON entries at path, such as
+// one of the gzip or zstd compressed backups of a RotatingFile, without the values or the
+// entries of the subject, and returns the number of matching entries. The archive is
+// replaced atomically, and left unchanged if no entry matches. The hash chain of the
+// audit logs is broken by the erasure.
+//
+// The entries written to the archive during the erasure would be lost: it fails with
+// ErrActiveFile for the file written by a RotatingFile of the process, which must be
+// erased with RotatingFile.EraseSubject, and must not be run on the file written by
+// another process.
+func EraseSubject(path string, options ErasureOptions) (int, error) {
+	if isActiveRotatingFile(path) {
+		return 0, ErrActiveFile
+	}
+
+	return eraseSubject(path, options)
+}
+
+// EraseSubject rotates the file and erases the subject from the rotated files, as
+// EraseSubject does, while their compression and removal is paused. It returns the
+// number of matching entries.
+func (f *RotatingFile) EraseSubject(options ErasureOptions) (int, error) {
+	if err := f.Rotate(); err != nil {
+		return 0, err
+	}
+
+	f.millMutex.Lock()
+	defer f.millMutex.Unlock()
+
+	erased := 0
+
+	for _, backup := range f.backups() {
+		n, err := eraseSubject(backup.path, options)
+		erased += n
+
+		if err != nil {
+			return erased, err
+		}
+	}
+
+	return erased, nil
+}
+
+func eraseSubject(path string, options ErasureOptions) (int, error) {
+	subjects, err := quotedSubjects(options.Subjects)
+
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return 0, err
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".erasure-*")
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(temporary.Name())
+	defer temporary.Close()
+
+	erased, err := eraseFile(path, temporary, subjects, options.Remove)
+
+	if err != nil || erased == 0 {
+		return 0, err
+	}
+
+	if err := temporary.Chmod(info.Mode()); err != nil {
+		return 0, err
+	}
+
+	if err := temporary.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(temporary.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return erased, nil
+}
+
+// quotedSubjects returns the subjects as the JSON strings written by the JSONFormatter,
+// with and without HTML escaping.
+func quotedSubjects(subjects []string) ([][]byte, error) {
+	var quoted [][]byte
+
+	for _, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+
+		for _, escapeHTML := range []bool{false, true} {
+			var buffer bytes.Buffer
+			encoder := json.NewEncoder(&buffer)
+			encoder.SetEscapeHTML(escapeHTML)
+
+			if err := encoder.Encode(subject); err != nil {
+				return nil, err
+			}
+
+			encoded := bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))
+
+			if escapeHTML && bytes.Equal(encoded, quoted[len(quoted)-1]) {
+				continue
+			}
+
+			quoted = append(quoted, encoded)
+		}
+	}
+
+	if len(quoted) == 0 {
+		return nil, errors.New("erasure: no subject")
+	}
+
+	return quoted, nil
+}
+
+// eraseFile writes the entries of the file to the destination, compressed like the file,
+// without the values or the entries of the subjects.
+func eraseFile(path string, destination io.Writer, subjects [][]byte, remove bool) (int, error) {
+	source, err := os.Open(path)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer source.Close()
+
+	var reader io.Reader = source
+	writer := io.WriteCloser(nopWriteCloser{destination})
+	compression := compressionOf(path)
+
+	if compression.Enabled() {
+		decompressor, err := compression.NewReader(source)
+
+		if err != nil {
+			return 0, err
+		}
+
+		defer decompressor.Close()
+		reader = decompressor
+
+		if writer, err = compression.NewWriter(destination); err != nil {
+			return 0, err
+		}
+	}
+
+	buffered := bufio.NewReader(reader)
+	output := bufio.NewWriter(writer)
+	erased := 0
+
+	for {
+		line, err := buffered.ReadBytes('\n')
+
+		if len(line) > 0 {
+			erasedLine, matched := eraseLine(line, subjects, remove)
+
+			if matched {
+				erased++
+			}
+
+			if _, err := output.Write(erasedLine); err != nil {
+				return 0, err
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := output.Flush(); err != nil {
+		return 0, err
+	}
+
+	return erased, writer.Close()
+}
+
+// eraseLine returns the line without the values of the subjects, or empty if it is
+// removed, and whether it matched.
+func eraseLine(line []byte, subjects [][]byte, remove bool) ([]byte, bool) {
+	matched := false
+
+	for _, subject := range subjects {
+		if !bytes.Contains(line, subject) {
+			continue
+		}
+
+		if remove {
+			return nil, true
+		}
+
+		matched = true
+		line = bytes.ReplaceAll(line, subject, []byte(`"`+defaultRedactionMask+`"`))
+	}
+
+	return line, matched
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,127 @@
+package glogger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const erasureArchive = `{"email":"alice@example.com","level":"info","message":"Logged in","userId":"42"}
+{"level":"info","message":"Logged in","userId":"43"}
+{"level":"info","message":"Listed orders","userId":"42"}
+`
+
+func TestEraseSubject(t *testing.T) {
+	t.Run("Values of the subject are redacted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		assert.NilError(t, os.WriteFile(path, []byte(erasureArchive), 0o640))
+
+		erased, err := EraseSubject(path, ErasureOptions{Subjects: []string{"42", "alice@example.com"}})
+		assert.NilError(t, err)
+		assert.Equal(t, erased, 2)
+
+		content, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), `{"email":"[REDACTED]","level":"info","message":"Logged in","userId":"[REDACTED]"}
+{"level":"info","message":"Logged in","userId":"43"}
+{"level":"info","message":"Listed orders","userId":"[REDACTED]"}
+`)
+
+		info, err := os.Stat(path)
+		assert.NilError(t, err)
+		assert.Equal(t, info.Mode().Perm(), os.FileMode(0o640))
+	})
+
+	t.Run("Entries of the subject are removed from the compressed backups", func(t *testing.T) {
+		for _, compression := range []Compression{{Codec: CodecGzip}, {Codec: CodecZstd}} {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			assert.NilError(t, os.WriteFile(path, []byte(erasureArchive), 0o644))
+			assert.NilError(t, compressFile(path, compression))
+
+			erased, err := EraseSubject(path+compression.Extension(), ErasureOptions{Subjects: []string{"42"}, Remove: true})
+			assert.NilError(t, err)
+			assert.Equal(t, erased, 2)
+
+			file, err := os.Open(path + compression.Extension())
+			assert.NilError(t, err)
+			defer file.Close()
+
+			reader, err := compression.NewReader(file)
+			assert.NilError(t, err)
+
+			content, err := io.ReadAll(reader)
+			assert.NilError(t, err)
+			assert.Equal(t, string(content), `{"level":"info","message":"Logged in","userId":"43"}`+"\n")
+
+			entries, err := os.ReadDir(dir)
+			assert.NilError(t, err)
+			assert.Equal(t, len(entries), 1)
+		}
+	})
+
+	t.Run("Archives without the subject are unchanged", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		assert.NilError(t, os.WriteFile(path, []byte(erasureArchive), 0o644))
+
+		erased, err := EraseSubject(path, ErasureOptions{Subjects: []string{"4"}})
+		assert.NilError(t, err)
+		assert.Equal(t, erased, 0)
+
+		_, err = EraseSubject(path, ErasureOptions{})
+		assert.ErrorContains(t, err, "no subject")
+	})
+
+	t.Run("HTML-escaped values of the subject are redacted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		assert.NilError(t, os.WriteFile(path, []byte(`{"name":"Tom \u0026 Jerry"}`+"\n"), 0o644))
+
+		erased, err := EraseSubject(path, ErasureOptions{Subjects: []string{"Tom & Jerry"}})
+		assert.NilError(t, err)
+		assert.Equal(t, erased, 1)
+	})
+
+	t.Run("The active file of a RotatingFile is erased after a rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		file, err := NewRotatingFile(RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), Compress: true})
+		assert.NilError(t, err)
+
+		file.Write([]byte(erasureArchive))
+
+		_, err = EraseSubject(file.options.Filename, ErasureOptions{Subjects: []string{"42"}})
+		assert.Equal(t, err, ErrActiveFile)
+
+		erased, err := file.EraseSubject(ErasureOptions{Subjects: []string{"42"}, Remove: true})
+		assert.NilError(t, err)
+		assert.Equal(t, erased, 2)
+
+		file.Write([]byte(`{"level":"info","message":"Logged out","userId":"43"}` + "\n"))
+		assert.NilError(t, file.Close())
+
+		content, err := os.ReadFile(file.options.Filename)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), `{"level":"info","message":"Logged out","userId":"43"}`+"\n")
+
+		backups := file.backups()
+		assert.Equal(t, len(backups), 1)
+
+		compressed, err := os.Open(backups[0].path)
+		assert.NilError(t, err)
+		defer compressed.Close()
+
+		reader, err := gzip.NewReader(compressed)
+		assert.NilError(t, err)
+
+		content, err = io.ReadAll(reader)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), `{"level":"info","message":"Logged in","userId":"43"}`+"\n")
+
+		erased, err = EraseSubject(file.options.Filename, ErasureOptions{Subjects: []string{"43"}})
+		assert.NilError(t, err)
+		assert.Equal(t, erased, 1)
+	})
+}
@@ -0,0 +1,105 @@
+package glogger
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorInfo struct contains items of a structured error info log.
+type ErrorInfo struct {
+	Message string       `json:"message"`
+	Type    string       `json:"type,omitempty"`
+	Causes  []ErrorCause `json:"causes,omitempty"`
+	Stack   []StackFrame `json:"stack,omitempty"`
+}
+
+// ErrorCause struct contains items of an unwrapped error info log.
+type ErrorCause struct {
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+}
+
+// NewErrorInfo returns the structured error info of the error: its message, its type,
+// such as "*fs.PathError", and the errors it wraps, depth first, as returned by the
+// Unwrap methods.
+func NewErrorInfo(err error) ErrorInfo {
+	info := ErrorInfo{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+
+	var unwrap func(err error)
+	unwrap = func(err error) {
+		var causes []error
+
+		switch err := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := err.Unwrap(); cause != nil {
+				causes = []error{cause}
+			}
+		case interface{ Unwrap() []error }:
+			causes = err.Unwrap()
+		}
+
+		for _, cause := range causes {
+			if cause == nil {
+				continue
+			}
+
+			info.Causes = append(info.Causes, ErrorCause{Message: cause.Error(), Type: fmt.Sprintf("%T", cause)})
+			unwrap(cause)
+		}
+	}
+
+	unwrap(err)
+
+	return info
+}
+
+// WithError adds the structured error info of the error to the entry, in place of the
+// error string added by logrus.Entry.WithError, so that the errors can be grouped by type.
+func WithError(entry *logrus.Entry, err error) *logrus.Entry {
+	return entry.WithField(logrus.ErrorKey, NewErrorInfo(err))
+}
+
+// WithErrorStack is like WithError, with the stack trace of the caller.
+func WithErrorStack(entry *logrus.Entry, err error) *logrus.Entry {
+	info := NewErrorInfo(err)
+	info.Stack = callerStack(1)
+
+	return entry.WithField(logrus.ErrorKey, info)
+}
+
+// Error logs the message at error level with the logger of the context, with the
+// structured error info of the error and the stack trace of the caller.
+func Error(ctx context.Context, err error, message string) {
+	info := NewErrorInfo(err)
+	info.Stack = callerStack(1)
+
+	Get(ctx).WithField(logrus.ErrorKey, info).Error(message)
+}
+
+// callerStack returns the stack trace of the goroutine, skipping the frames of
+// callerStack and of its skip callers.
+func callerStack(skip int) []StackFrame {
+	pcs := make([]uintptr, maxStackDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip+2, pcs)])
+
+	var stack []StackFrame
+
+	for {
+		frame, more := frames.Next()
+
+		stack = append(stack, StackFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
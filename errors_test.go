@@ -0,0 +1,59 @@
+package glogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+type notFoundError struct {
+	name string
+}
+
+func (err *notFoundError) Error() string {
+	return err.name + " not found"
+}
+
+func TestNewErrorInfo(t *testing.T) {
+	notFound := &notFoundError{name: "config"}
+	err := fmt.Errorf("failed to load config: %w", errors.Join(notFound, errors.New("fallback failed")))
+
+	info := NewErrorInfo(err)
+
+	assert.Equal(t, info.Message, err.Error())
+	assert.Equal(t, info.Type, "*fmt.wrapError")
+	assert.DeepEqual(t, info.Causes, []ErrorCause{
+		{Message: "config not found\nfallback failed", Type: "*errors.joinError"},
+		{Message: "config not found", Type: "*glogger.notFoundError"},
+		{Message: "fallback failed", Type: "*errors.errorString"},
+	})
+	assert.Assert(t, info.Stack == nil)
+}
+
+func TestError(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	ctx := WithLogger(context.Background(), logrus.NewEntry(logger))
+
+	Error(ctx, errors.New("failed"), "Failed to handle request")
+
+	entry := hook.LastEntry()
+	assert.Equal(t, entry.Level, logrus.ErrorLevel)
+	assert.Equal(t, entry.Message, "Failed to handle request")
+
+	info := entry.Data["error"].(ErrorInfo)
+	assert.Equal(t, info.Message, "failed")
+	assert.Assert(t, strings.HasSuffix(info.Stack[0].Function, "TestError"), info.Stack[0].Function)
+
+	WithError(logrus.NewEntry(logger), errors.New("failed")).Warn("Retrying")
+	assert.Assert(t, hook.LastEntry().Data["error"].(ErrorInfo).Stack == nil)
+
+	WithErrorStack(logrus.NewEntry(logger), errors.New("failed")).Warn("Retrying")
+	stack := hook.LastEntry().Data["error"].(ErrorInfo).Stack
+	assert.Assert(t, strings.HasSuffix(stack[0].Function, "TestError"), stack[0].Function)
+}
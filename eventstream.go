@@ -0,0 +1,102 @@
+package glogger
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventStreamContentType        = "text/event-stream"
+	defaultStreamProgressInterval = 30 * time.Second
+	streamClosedByClient          = "client disconnected"
+	streamClosedByHandler         = "handler returned"
+)
+
+// Stream struct contains items of server-sent events stream info log.
+type Stream struct {
+	Events      int     `json:"events"`
+	Bytes       int     `json:"bytes"`
+	Duration    float64 `json:"duration,omitempty"`
+	CloseReason string  `json:"closeReason,omitempty"`
+}
+
+type eventStream struct {
+	logger       *logrus.Entry
+	interval     time.Duration
+	start        time.Time
+	lastProgress time.Time
+	events       int
+	bytes        int
+	lastByte     byte
+}
+
+func isEventStream(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get(contentTypeKey))
+
+	return err == nil && mediaType == eventStreamContentType
+}
+
+// newEventStream returns a stream logging progress entries every interval. A nil logger
+// disables the progress entries.
+func newEventStream(logger *logrus.Entry, interval time.Duration) *eventStream {
+	if interval <= 0 {
+		interval = defaultStreamProgressInterval
+	}
+
+	now := time.Now()
+
+	return &eventStream{
+		logger:       logger,
+		interval:     interval,
+		start:        now,
+		lastProgress: now,
+	}
+}
+
+// write counts the bytes and the events sent on the stream. An event is
+// terminated by a blank line, so every pair of consecutive line feeds
+// (ignoring carriage returns) closes one event.
+func (stream *eventStream) write(b []byte) {
+	stream.bytes += len(b)
+
+	for _, c := range b {
+		if c == '\r' {
+			continue
+		}
+
+		if c == '\n' && stream.lastByte == '\n' {
+			stream.events++
+			c = 0
+		}
+
+		stream.lastByte = c
+	}
+
+	if stream.logger != nil && time.Since(stream.lastProgress) >= stream.interval {
+		stream.lastProgress = time.Now()
+		stream.logger.WithFields(logrus.Fields{
+			"stream": stream.info(""),
+		}).Info("Stream progress")
+	}
+}
+
+func (stream *eventStream) info(closeReason string) Stream {
+	return Stream{
+		Events:      stream.events,
+		Bytes:       stream.bytes,
+		Duration:    time.Since(stream.start).Seconds(),
+		CloseReason: closeReason,
+	}
+}
+
+func streamCloseReason(ctx context.Context) string {
+	if ctx.Err() != nil {
+		return streamClosedByClient
+	}
+
+	return streamClosedByHandler
+}
@@ -0,0 +1,45 @@
+package glogger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestEventStream(t *testing.T) {
+	t.Run("Events are counted on blank lines", func(t *testing.T) {
+		stream := newEventStream(logrus.NewEntry(logrus.New()), 0)
+
+		stream.write([]byte("data: first\n\ndata: sec"))
+		stream.write([]byte("ond\n"))
+		stream.write([]byte("\r\n"))
+
+		assert.Equal(t, stream.events, 2, "Unexpected number of events")
+		assert.Equal(t, stream.bytes, 28, "Unexpected number of bytes")
+	})
+
+	t.Run("Stream entries replace the completed request entry", func(t *testing.T) {
+		handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+			rw.WriteHeader(http.StatusOK)
+			rw.Write([]byte("data: hello\n\n"))
+			rw.(http.Flusher).Flush()
+		})
+
+		hook := testMiddlewareInvocation(handler, "", nil, "")
+		entries := hook.AllEntries()
+
+		assert.Equal(t, len(entries), 3, "Unexpected entries length.")
+		assert.Equal(t, entries[0].Message, "Incoming Request")
+		assert.Equal(t, entries[1].Message, "Stream opened")
+		assert.Equal(t, entries[2].Message, "Stream closed")
+
+		stream := entries[2].Data["stream"].(Stream)
+		assert.Equal(t, stream.Events, 1, "Unexpected number of events")
+		assert.Equal(t, stream.Bytes, 13, "Unexpected number of bytes")
+		assert.Equal(t, stream.CloseReason, streamClosedByHandler)
+		assert.Assert(t, entries[2].Data["http"].(HTTP).Response != nil, "Unexpected http Response nil")
+	})
+}
@@ -0,0 +1,43 @@
+// Command chi is a chi server logging its requests with the chilogger middleware.
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/platform-horizon/glogger"
+	"github.com/platform-horizon/glogger/chilogger"
+	"github.com/sirupsen/logrus"
+)
+
+func newHandler(logger *logrus.Logger) http.Handler {
+	router := chi.NewRouter()
+	router.Use(chilogger.Logger(logger, glogger.MiddlewareOptions{}))
+
+	router.Get("/users/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		glogger.Get(r.Context()).WithField("userId", id).Info("Loading user")
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]string{"id": id})
+	})
+
+	return router
+}
+
+func main() {
+	logger, err := glogger.Init(glogger.InitOptions{Level: "info"})
+
+	if err != nil {
+		panic(err)
+	}
+
+	logger.Info("Listening on :8080")
+
+	if err := http.ListenAndServe(":8080", newHandler(logger)); err != nil {
+		logger.WithError(err).Error("Server failed")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,11 @@
+// Package examples holds runnable servers wired with glogger, one per adapter: nethttp
+// (net/http with the gorilla/mux middleware), chi, gin and grpc. Their tests serve real
+// requests and assert the NDJSON entries written, so that the examples double as
+// regression tests of the adapters.
+//
+// It is a separate module, so that the example dependencies are not dependencies of
+// glogger. Run a server or the tests from this directory with:
+//
+//	go run ./chi
+//	go test ./...
+package examples
@@ -0,0 +1,43 @@
+// Command gin is a gin server logging its requests with the ginlogger middleware.
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/platform-horizon/glogger"
+	"github.com/platform-horizon/glogger/ginlogger"
+	"github.com/sirupsen/logrus"
+)
+
+func newHandler(logger *logrus.Logger) http.Handler {
+	gin.SetMode(gin.ReleaseMode)
+
+	router := gin.New()
+	router.Use(ginlogger.Logger(logger, glogger.MiddlewareOptions{}))
+
+	router.GET("/users/:id", func(c *gin.Context) {
+		id := c.Param("id")
+		glogger.Get(c.Request.Context()).WithField("userId", id).Info("Loading user")
+
+		c.JSON(http.StatusOK, gin.H{"id": id})
+	})
+
+	return router
+}
+
+func main() {
+	logger, err := glogger.Init(glogger.InitOptions{Level: "info"})
+
+	if err != nil {
+		panic(err)
+	}
+
+	logger.Info("Listening on :8080")
+
+	if err := http.ListenAndServe(":8080", newHandler(logger)); err != nil {
+		logger.WithError(err).Error("Server failed")
+		os.Exit(1)
+	}
+}
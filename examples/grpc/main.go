@@ -0,0 +1,58 @@
+// Command grpc is a gRPC server logging its calls with the grpclogger interceptors.
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/platform-horizon/glogger/grpclogger"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthServer logs the health checks with the call logger.
+type healthServer struct {
+	*health.Server
+}
+
+func (s healthServer) Check(ctx context.Context, request *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	glogger.Get(ctx).WithField("service", request.Service).Info("Checking health")
+
+	return s.Server.Check(ctx, request)
+}
+
+func newServer(logger *logrus.Logger) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpclogger.UnaryServerInterceptor(logger)),
+		grpc.StreamInterceptor(grpclogger.StreamServerInterceptor(logger)),
+	)
+	healthpb.RegisterHealthServer(server, healthServer{Server: health.NewServer()})
+
+	return server
+}
+
+func main() {
+	logger, err := glogger.Init(glogger.InitOptions{Level: "info"})
+
+	if err != nil {
+		panic(err)
+	}
+
+	listener, err := net.Listen("tcp", ":50051")
+
+	if err != nil {
+		logger.WithError(err).Error("Failed to listen")
+		os.Exit(1)
+	}
+
+	logger.Info("Listening on :50051")
+
+	if err := newServer(logger).Serve(listener); err != nil {
+		logger.WithError(err).Error("Server failed")
+		os.Exit(1)
+	}
+}
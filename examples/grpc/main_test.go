@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/platform-horizon/glogger/examples/internal/ndjson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func TestServer(t *testing.T) {
+	var output ndjson.Buffer
+
+	logger, err := glogger.Init(glogger.InitOptions{Level: "trace", Output: &output})
+	assert.NilError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+
+	server := newServer(logger)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NilError(t, err)
+
+	client := healthpb.NewHealthClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "request-id")
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.NilError(t, err)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	conn.Close()
+	// GracefulStop waits for the calls, so that every entry is written.
+	server.GracefulStop()
+
+	entries := output.Entries(t)
+
+	handler := ndjson.Require(t, entries, "Checking health")
+	assert.Equal(t, handler["correlationId"], "request-id")
+
+	completed := ndjson.Require(t, entries, "Completed Request")
+	assert.Equal(t, completed["correlationId"], "request-id")
+	assert.Equal(t, completed.Lookup("grpc.method"), "/grpc.health.v1.Health/Check")
+	assert.Equal(t, completed.Lookup("grpc.type"), "unary")
+	assert.Equal(t, completed.Lookup("grpc.code"), "OK")
+
+	last := entries[len(entries)-1]
+	assert.Equal(t, last.Lookup("grpc.code"), "NotFound")
+}
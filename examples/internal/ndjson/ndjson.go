@@ -0,0 +1,83 @@
+// Package ndjson reads the NDJSON entries written by the example servers in their tests.
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Buffer is an io.Writer collecting the entries, safe for concurrent use.
+type Buffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+// Entry is a decoded entry.
+type Entry map[string]interface{}
+
+// Lookup returns the value of the dot-separated path, such as "http.response.statusCode".
+func (e Entry) Lookup(path string) interface{} {
+	var value interface{} = map[string]interface{}(e)
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+
+		if !ok {
+			return nil
+		}
+
+		value = object[key]
+	}
+
+	return value
+}
+
+// Entries decodes the entries written to the buffer, failing the test if a line is not a
+// JSON object.
+func (b *Buffer) Entries(t testing.TB) []Entry {
+	t.Helper()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(b.buffer.Bytes()))
+
+	for scanner.Scan() {
+		var entry Entry
+
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Require returns the first entry with the message, failing the test if there is none.
+func Require(t testing.TB, entries []Entry, message string) Entry {
+	t.Helper()
+
+	for _, entry := range entries {
+		if entry["message"] == message {
+			return entry
+		}
+	}
+
+	t.Fatalf("no %q entry in %v", message, entries)
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Command nethttp is a net/http server logging its requests with the glogger middleware
+// of gorilla/mux.
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+)
+
+func newHandler(logger *logrus.Logger) http.Handler {
+	router := mux.NewRouter()
+	router.Use(glogger.LoggingMiddlewareWithOptions(logger, glogger.MiddlewareOptions{RecoverPanics: true}))
+
+	router.HandleFunc("/users/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		glogger.Get(r.Context()).WithField("userId", id).Info("Loading user")
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]string{"id": id})
+	}).Methods(http.MethodGet)
+
+	router.HandleFunc("/panic", func(rw http.ResponseWriter, r *http.Request) {
+		panic("unexpected state")
+	})
+
+	return router
+}
+
+func main() {
+	logger, err := glogger.Init(glogger.InitOptions{Level: "info"})
+
+	if err != nil {
+		panic(err)
+	}
+
+	logger.Info("Listening on :8080")
+
+	if err := http.ListenAndServe(":8080", newHandler(logger)); err != nil {
+		logger.WithError(err).Error("Server failed")
+		os.Exit(1)
+	}
+}
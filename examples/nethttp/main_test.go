@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/platform-horizon/glogger/examples/internal/ndjson"
+	"gotest.tools/assert"
+)
+
+func TestServer(t *testing.T) {
+	var output ndjson.Buffer
+
+	logger, err := glogger.Init(glogger.InitOptions{Level: "trace", Output: &output})
+	assert.NilError(t, err)
+
+	server := httptest.NewServer(newHandler(logger))
+	response, err := http.Get(server.URL + "/users/42")
+	assert.NilError(t, err)
+	response.Body.Close()
+
+	panicResponse, err := http.Get(server.URL + "/panic")
+	assert.NilError(t, err)
+	panicResponse.Body.Close()
+
+	// Close waits for the handlers, so that every entry is written.
+	server.Close()
+
+	entries := output.Entries(t)
+	requestID := response.Header.Get("X-Request-Id")
+
+	incoming := ndjson.Require(t, entries, "Incoming Request")
+	assert.Equal(t, incoming["level"], "trace")
+	assert.Equal(t, incoming["correlationId"], requestID)
+
+	handler := ndjson.Require(t, entries, "Loading user")
+	assert.Equal(t, handler["correlationId"], requestID)
+	assert.Equal(t, handler["userId"], "42")
+
+	completed := ndjson.Require(t, entries, "Completed Request")
+	assert.Equal(t, completed["correlationId"], requestID)
+	assert.Equal(t, completed.Lookup("http.request.route"), "/users/{id}")
+	assert.Equal(t, completed.Lookup("http.response.statusCode"), float64(http.StatusOK))
+	assert.Equal(t, completed.Lookup("http.response.content-type"), "application/json")
+
+	recovered := ndjson.Require(t, entries, "Panic Recovered")
+	assert.Equal(t, recovered["level"], "error")
+	assert.Equal(t, panicResponse.StatusCode, http.StatusInternalServerError)
+}
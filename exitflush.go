@@ -0,0 +1,74 @@
+package glogger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// exitFlushTimeout bounds the flush of the buffered writers when the process exits, so
+// that a stuck sink doesn't prevent the exit.
+const exitFlushTimeout = 5 * time.Second
+
+// exitFlush is the flush of an open buffered writer.
+type exitFlush struct {
+	writer interface{}
+	flush  func()
+}
+
+var (
+	exitFlushesMutex sync.Mutex
+	exitFlushes      []exitFlush
+	exitHandlerOnce  sync.Once
+)
+
+// flushOnExit flushes the buffered writer when logrus exits the process, such as after
+// a Fatal entry, so that the entry and the entries before it are written although the
+// deferred Close calls don't run.
+func flushOnExit(writer interface{}, flush func()) {
+	exitHandlerOnce.Do(func() {
+		logrus.RegisterExitHandler(flushAllOnExit)
+	})
+
+	exitFlushesMutex.Lock()
+	defer exitFlushesMutex.Unlock()
+
+	exitFlushes = append(exitFlushes, exitFlush{writer: writer, flush: flush})
+}
+
+// stopFlushOnExit stops flushing the closed writer on exit.
+func stopFlushOnExit(writer interface{}) {
+	exitFlushesMutex.Lock()
+	defer exitFlushesMutex.Unlock()
+
+	for i, flush := range exitFlushes {
+		if flush.writer == writer {
+			exitFlushes = append(exitFlushes[:i], exitFlushes[i+1:]...)
+			return
+		}
+	}
+}
+
+// flushAllOnExit flushes the open writers, the most recent first, as the writers are
+// usually created before the writers buffering their writes.
+func flushAllOnExit() {
+	exitFlushesMutex.Lock()
+	flushes := append([]exitFlush(nil), exitFlushes...)
+	exitFlushesMutex.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := len(flushes) - 1; i >= 0; i-- {
+			flushes[i].flush()
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(exitFlushTimeout):
+	}
+}
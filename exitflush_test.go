@@ -0,0 +1,63 @@
+package glogger
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+// slowBuffer is a buffer whose writes take some time.
+type slowBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *slowBuffer) Write(p []byte) (int, error) {
+	time.Sleep(10 * time.Millisecond)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *slowBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestFlushOnExit(t *testing.T) {
+	var output slowBuffer
+	coalescing := NewCoalescingWriter(&output, CoalescingWriterOptions{FlushInterval: time.Hour})
+	defer coalescing.Close()
+
+	async := NewAsyncWriter(coalescing, AsyncWriterOptions{})
+	defer async.Close()
+
+	var written string
+	logger := logrus.New()
+	logger.SetOutput(async)
+	logger.SetFormatter(&JSONFormatter{})
+	logger.ExitFunc = func(int) { written = output.String() }
+
+	logger.Info("Starting")
+	logger.Fatal("Failed to start")
+
+	assert.Assert(t, bytes.Contains([]byte(written), []byte("Starting")), written)
+	assert.Assert(t, bytes.Contains([]byte(written), []byte("Failed to start")), written)
+
+	t.Run("Closed writers are not flushed", func(t *testing.T) {
+		writer := NewCoalescingWriter(&output, CoalescingWriterOptions{})
+		assert.NilError(t, writer.Close())
+
+		for _, flush := range exitFlushes {
+			assert.Assert(t, flush.writer != writer)
+		}
+	})
+}
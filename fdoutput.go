@@ -0,0 +1,60 @@
+package glogger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	fdOutputPrefix = "fd:"
+	// systemdFirstFD is the first file descriptor passed by the systemd socket activation.
+	systemdFirstFD = 3
+)
+
+// OpenInheritedFD returns the output writing to a file descriptor inherited from the
+// parent process, such as a pipe provided by a supervisor or a connected socket passed by
+// systemd. The descriptor is selected by its number, such as "3", or by its name in the
+// LISTEN_FDNAMES of the systemd socket activation, such as "log" for
+// FileDescriptorName=log. It fails when the descriptor isn't open.
+func OpenInheritedFD(descriptor string) (*os.File, error) {
+	fd, err := inheritedFD(descriptor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFD(fd); err != nil {
+		return nil, fmt.Errorf("file descriptor %d: %w", fd, err)
+	}
+
+	return os.NewFile(uintptr(fd), fdOutputPrefix+descriptor), nil
+}
+
+// inheritedFD returns the number of the descriptor, parsed or looked up in the systemd
+// socket activation variables.
+func inheritedFD(descriptor string) (int, error) {
+	if fd, err := strconv.Atoi(descriptor); err == nil {
+		if fd < 0 {
+			return 0, fmt.Errorf("invalid file descriptor %d", fd)
+		}
+
+		return fd, nil
+	}
+
+	if pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID")); pid != os.Getpid() {
+		return 0, fmt.Errorf("file descriptor %q: no socket activation for this process", descriptor)
+	}
+
+	count, _ := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	for i, name := range names {
+		if name == descriptor && i < count {
+			return systemdFirstFD + i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("file descriptor %q: not passed by the socket activation", descriptor)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package glogger
+
+import "errors"
+
+// checkFD fails: the inherited descriptors are only supported on Unix.
+func checkFD(fd int) error {
+	return errors.New("inherited file descriptors are not supported on this platform")
+}
@@ -0,0 +1,68 @@
+//go:build unix
+
+package glogger
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestInheritedFD(t *testing.T) {
+	t.Run("Entries are written to the descriptor", func(t *testing.T) {
+		reader, writer, err := os.Pipe()
+		assert.NilError(t, err)
+		defer reader.Close()
+		defer writer.Close()
+
+		// The descriptor is duplicated, as inherited, so that it is closed by its output only.
+		fd, err := syscall.Dup(int(writer.Fd()))
+		assert.NilError(t, err)
+
+		t.Setenv(LogOutputEnv, "fd:"+strconv.Itoa(fd))
+		t.Setenv(LogFormatEnv, FormatJSON)
+
+		logger, err := InitFromEnv()
+		assert.NilError(t, err)
+		defer logger.Out.(*os.File).Close()
+
+		logger.Info("Inherited")
+
+		line, err := bufio.NewReader(reader).ReadString('\n')
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(line, `"message":"Inherited"`), line)
+	})
+
+	t.Run("Descriptors are looked up by their systemd name", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", "2")
+		t.Setenv("LISTEN_FDNAMES", "http:log")
+
+		fd, err := inheritedFD("log")
+		assert.NilError(t, err)
+		assert.Equal(t, fd, 4)
+
+		_, err = inheritedFD("metrics")
+		assert.ErrorContains(t, err, "not passed by the socket activation")
+
+		t.Setenv("LISTEN_PID", "1")
+		_, err = inheritedFD("log")
+		assert.ErrorContains(t, err, "no socket activation for this process")
+	})
+
+	t.Run("Closed descriptors are rejected", func(t *testing.T) {
+		file, err := os.CreateTemp(t.TempDir(), "closed")
+		assert.NilError(t, err)
+
+		fd := file.Fd()
+		file.Close()
+
+		_, err = OpenInheritedFD(strconv.Itoa(int(fd)))
+		assert.ErrorContains(t, err, "bad file descriptor")
+	})
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package glogger
+
+import "syscall"
+
+// checkFD checks that the descriptor is open, before it is owned by an os.File which
+// would close it, even if reused by another file meanwhile.
+func checkFD(fd int) error {
+	var stat syscall.Stat_t
+
+	return syscall.Fstat(fd, &stat)
+}
@@ -0,0 +1,133 @@
+// Package fiberlogger provides a fiber middleware logging the requests with the glogger
+// middleware, including the matched fiber route pattern.
+package fiberlogger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+)
+
+type routeKey struct{}
+
+// responseWriter records the response written through the glogger middleware, which is
+// the response of the fiber handler with the headers of the middleware, or the response
+// of the middleware when it doesn't call the handler, such as a shed request.
+type responseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.statusCode == 0 {
+		rw.statusCode = http.StatusOK
+	}
+
+	return rw.body.Write(b)
+}
+
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	if rw.statusCode == 0 {
+		rw.statusCode = statusCode
+	}
+}
+
+// writeTo replaces the fiber response with the recorded response.
+func (rw *responseWriter) writeTo(response *fiber.Response) {
+	var removed []string
+
+	response.Header.VisitAll(func(key []byte, value []byte) {
+		if _, ok := rw.header[http.CanonicalHeaderKey(string(key))]; !ok {
+			removed = append(removed, string(key))
+		}
+	})
+
+	for _, key := range removed {
+		response.Header.Del(key)
+	}
+
+	for key, values := range rw.header {
+		response.Header.Del(key)
+
+		for _, value := range values {
+			response.Header.Add(key, value)
+		}
+	}
+
+	if rw.statusCode == 0 {
+		rw.statusCode = http.StatusOK
+	}
+
+	response.SetStatusCode(rw.statusCode)
+	response.SetBody(rw.body.Bytes())
+}
+
+func routePattern(r *http.Request) string {
+	if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+		return *route
+	}
+
+	return ""
+}
+
+// Logger returns a fiber middleware logging the requests, like glogger.LoggingMiddlewareWithOptions.
+// The fiber route pattern, such as /users/:id, is logged in http.request.route unless
+// options.RoutePattern is set. The errors returned by the handlers are passed to the
+// fiber error handler, so that the response status is logged. The request scoped logger
+// is available with glogger.Get(c.UserContext()).
+//
+// Fiber is not based on net/http: the response is replayed to the middleware once the
+// handler returned, so server-sent events streams are not logged as such. The response
+// written through the middleware, with its headers such as X-Request-Id, is then sent by
+// fiber.
+func Logger(logger *logrus.Logger, options glogger.MiddlewareOptions) fiber.Handler {
+	if options.RoutePattern == nil {
+		options.RoutePattern = routePattern
+	}
+
+	middleware := glogger.LoggingMiddlewareWithOptions(logger, options)
+
+	return func(c *fiber.Ctx) error {
+		r, err := adaptor.ConvertRequest(c, true)
+
+		if err != nil {
+			return err
+		}
+
+		var route string
+
+		handler := middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			c.SetUserContext(r.Context())
+
+			if err := c.Next(); err != nil {
+				if err := c.App().Config().ErrorHandler(c, err); err != nil {
+					c.Status(fiber.StatusInternalServerError)
+				}
+			}
+
+			route = c.Route().Path
+
+			c.Response().Header.VisitAll(func(key []byte, value []byte) {
+				rw.Header().Add(string(key), string(value))
+			})
+			rw.WriteHeader(c.Response().StatusCode())
+			rw.Write(c.Response().Body())
+		}))
+
+		recorder := &responseWriter{header: http.Header{}}
+		handler.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), routeKey{}, &route)))
+		recorder.writeTo(c.Response())
+
+		return nil
+	}
+}
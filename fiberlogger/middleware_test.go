@@ -0,0 +1,90 @@
+package fiberlogger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLogger(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	app := fiber.New()
+	app.Use(Logger(logger, glogger.MiddlewareOptions{}))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		glogger.Get(c.UserContext()).Info("Handling Request")
+		return c.Status(http.StatusAccepted).SendString("user " + c.Params("id"))
+	})
+	app.Get("/missing/:id", func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	t.Run("Route pattern is logged", func(t *testing.T) {
+		response, err := app.Test(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+		assert.NilError(t, err)
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, response.StatusCode, http.StatusAccepted)
+		assert.Equal(t, string(body), "user 42")
+
+		entries := hook.AllEntries()
+		assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+		assert.Equal(t, entries[0].Data["correlationId"], entries[1].Data["correlationId"])
+
+		httpField := entries[1].Data["http"].(glogger.HTTP)
+		assert.Equal(t, httpField.Request.Path, "/users/42")
+		assert.Equal(t, httpField.Request.Route, "/users/:id")
+		assert.Equal(t, httpField.Response.StatusCode, 202)
+		assert.Equal(t, httpField.Response.Bytes, 7)
+	})
+
+	t.Run("Handler errors are logged with their status", func(t *testing.T) {
+		hook.Reset()
+		response, err := app.Test(httptest.NewRequest(http.MethodGet, "/missing/42", nil))
+		assert.NilError(t, err)
+
+		assert.Equal(t, response.StatusCode, http.StatusNotFound)
+		assert.Equal(t, hook.LastEntry().Data["http"].(glogger.HTTP).Response.StatusCode, 404)
+	})
+
+	t.Run("Headers of the middleware are sent", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		request.Header.Set("X-Request-Id", "request-id")
+
+		response, err := app.Test(request)
+		assert.NilError(t, err)
+
+		assert.Equal(t, response.StatusCode, http.StatusAccepted)
+		assert.Equal(t, response.Header.Get("X-Request-Id"), "request-id")
+	})
+
+	t.Run("Shed requests are answered by the middleware", func(t *testing.T) {
+		hook.Reset()
+		app := fiber.New()
+		app.Use(Logger(logger, glogger.MiddlewareOptions{
+			Backpressure: glogger.BackpressureOptions{
+				Saturation: func() float64 { return 1 },
+				Shed:       func(r *http.Request) bool { return true },
+				Header:     "X-Log-Backpressure",
+			},
+		}))
+		app.Get("/reports", func(c *fiber.Ctx) error {
+			return c.SendString("report")
+		})
+
+		response, err := app.Test(httptest.NewRequest(http.MethodGet, "/reports", nil))
+		assert.NilError(t, err)
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, response.StatusCode, http.StatusServiceUnavailable)
+		assert.Equal(t, response.Header.Get("X-Log-Backpressure"), "saturated")
+		assert.Assert(t, string(body) != "report")
+		assert.Equal(t, hook.LastEntry().Data["http"].(glogger.HTTP).Response.StatusCode, http.StatusServiceUnavailable)
+	})
+}
@@ -0,0 +1,185 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultVerifyTailSize = 64 << 10
+
+// VerifyOptions is the struct of options to configure the read-back verification of a
+// rotating file of JSON entries, catching the corruptions such as the entries truncated
+// when the disk is full or interleaved by concurrent writers.
+type VerifyOptions struct {
+	// Interval is the period of the verification. Zero disables it.
+	Interval time.Duration
+	// TailSize is the maximum number of bytes read at the end of the file by each
+	// verification, which reads only the bytes written since the previous one. Defaults to
+	// 64 KiB.
+	TailSize int64
+	// OnCorruption is called with the errors of the periodic verifications, wrapping a
+	// CorruptionError per corrupted line. Defaults to printing them to os.Stderr.
+	OnCorruption func(err error)
+}
+
+func (options VerifyOptions) tailSize() int64 {
+	if options.TailSize <= 0 {
+		return defaultVerifyTailSize
+	}
+
+	return options.TailSize
+}
+
+// CorruptionError is a corrupted line found by the verification of a file.
+type CorruptionError struct {
+	// Path is the path of the file.
+	Path string
+	// Offset is the offset of the line in the file.
+	Offset int64
+	// Reason is "partial line" for a line without a final newline, or "invalid JSON".
+	Reason string
+}
+
+func (err *CorruptionError) Error() string {
+	return fmt.Sprintf("corrupted log file %s at offset %d: %s", err.Path, err.Offset, err.Reason)
+}
+
+// verification is the state of the verification of the file, which resumes after the
+// last line verified.
+type verification struct {
+	info     os.FileInfo
+	verified int64
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Verify reads back the end of the file and returns the corrupted lines since the previous
+// verification, as CorruptionError, joined. Only the JSON entries are valid.
+func (f *RotatingFile) Verify() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return ErrWriterClosed
+	}
+
+	if f.options.Lock {
+		if err := f.lock(); err != nil {
+			return err
+		}
+
+		defer f.unlock()
+	}
+
+	return f.verify()
+}
+
+func (f *RotatingFile) verify() error {
+	file, err := os.Open(f.options.Filename)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	state := &f.verification
+
+	if state.info == nil || !os.SameFile(state.info, info) || info.Size() < state.verified {
+		state.verified = 0
+	}
+
+	state.info = info
+	start := max(state.verified, info.Size()-f.options.Verify.tailSize())
+	tail := make([]byte, info.Size()-start)
+
+	if _, err := file.ReadAt(tail, start); err != nil {
+		return err
+	}
+
+	// The tail starts within a line unless it resumes the previous verification.
+	if start > state.verified {
+		index := bytes.IndexByte(tail, '\n')
+
+		if index < 0 {
+			return nil
+		}
+
+		start += int64(index + 1)
+		tail = tail[index+1:]
+	}
+
+	var corruptions []error
+	offset := start
+
+	for len(tail) > 0 {
+		index := bytes.IndexByte(tail, '\n')
+
+		if index < 0 {
+			corruptions = append(corruptions, &CorruptionError{Path: f.options.Filename, Offset: offset, Reason: "partial line"})
+			break
+		}
+
+		if line := bytes.TrimSpace(tail[:index]); len(line) > 0 && !json.Valid(line) {
+			corruptions = append(corruptions, &CorruptionError{Path: f.options.Filename, Offset: offset, Reason: "invalid JSON"})
+		}
+
+		offset += int64(index + 1)
+		tail = tail[index+1:]
+	}
+
+	state.verified = info.Size()
+
+	return errors.Join(corruptions...)
+}
+
+// startVerification verifies the file periodically until it is closed.
+func (f *RotatingFile) startVerification() {
+	options := f.options.Verify
+	f.verification.stop = make(chan struct{})
+	f.verification.done = make(chan struct{})
+
+	go func() {
+		defer close(f.verification.done)
+
+		ticker := time.NewTicker(options.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := f.Verify()
+
+				if err == nil || errors.Is(err, ErrWriterClosed) {
+					continue
+				}
+
+				if options.OnCorruption != nil {
+					options.OnCorruption(err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to verify log file, %v\n", err)
+				}
+			case <-f.verification.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopVerification stops the periodic verification, if any.
+func (f *RotatingFile) stopVerification() {
+	if f.verification.stop != nil {
+		close(f.verification.stop)
+		<-f.verification.done
+	}
+}
@@ -0,0 +1,67 @@
+package glogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestRotatingFileVerification(t *testing.T) {
+	t.Run("Corrupted lines are reported once", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		file, err := NewRotatingFile(RotatingFileOptions{Filename: path})
+		assert.NilError(t, err)
+		defer file.Close()
+
+		file.Write([]byte(`{"message":"first"}` + "\n"))
+		assert.NilError(t, file.Verify())
+
+		// A concurrent writer interleaves a partial entry.
+		other, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		assert.NilError(t, err)
+		other.Write([]byte(`{"message":"trunc`))
+		other.Close()
+
+		err = file.Verify()
+		var corruption *CorruptionError
+		assert.Assert(t, errors.As(err, &corruption))
+		assert.DeepEqual(t, *corruption, CorruptionError{Path: path, Offset: 20, Reason: "partial line"})
+
+		file.Write([]byte(`{"message":"second"}` + "\n"))
+		assert.NilError(t, file.Verify())
+
+		file.Write([]byte(`{"message":"third"` + "\n"))
+		assert.ErrorContains(t, file.Verify(), "at offset 58: invalid JSON")
+	})
+
+	t.Run("Only the tail of the file is read", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		assert.NilError(t, os.WriteFile(path, []byte("not json\n"+`{"message":"first"}`+"\n"), 0o644))
+
+		file, err := NewRotatingFile(RotatingFileOptions{Filename: path, Verify: VerifyOptions{TailSize: 25}})
+		assert.NilError(t, err)
+		defer file.Close()
+
+		assert.NilError(t, file.Verify())
+	})
+
+	t.Run("Corruptions are reported periodically", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		corruptions := make(chan error, 1)
+
+		file, err := NewRotatingFile(RotatingFileOptions{Filename: path, Verify: VerifyOptions{
+			Interval:     time.Millisecond,
+			OnCorruption: func(err error) { corruptions <- err },
+		}})
+		assert.NilError(t, err)
+
+		file.Write([]byte("{\"message\":\n"))
+
+		assert.ErrorContains(t, <-corruptions, "at offset 0: invalid JSON")
+		assert.NilError(t, file.Close())
+	})
+}
@@ -0,0 +1,101 @@
+package glogger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ForwardOptions is the struct of options to configure a ForwardWriter.
+type ForwardOptions struct {
+	// Network is "tcp", the default, or "unix".
+	Network string
+	// Address is the address of the Fluentd or Fluent Bit forward input, such as
+	// "localhost:24224".
+	Address string
+	// Tag is the tag of the entries, used for the routing by the collector.
+	Tag string
+	// TLSConfig enables TLS, such as for the secure forward input.
+	TLSConfig *tls.Config
+	// Timeout is the timeout of the connections and writes. Defaults to 5 seconds.
+	Timeout time.Duration
+	// Buffer configures the buffer of the entries written while the collector is slow
+	// or unreachable.
+	Buffer AsyncWriterOptions
+}
+
+// ForwardWriter is an io.WriteCloser shipping the JSON entries to Fluentd or Fluent Bit,
+// with the forward protocol. Each entry is sent as a message of the tag, the time of the
+// write and the record, encoded with msgpack. The entries are buffered and sent from a
+// background goroutine, reconnecting with a backoff when the connection fails. Once the
+// buffer is full, the writes block or the entries are dropped, according to the overflow
+// policy of the buffer. It can be used as the InitOptions.Output.
+type ForwardWriter struct {
+	tag    string
+	conn   *netWriter
+	buffer *AsyncWriter
+}
+
+// NewForwardWriter returns a ForwardWriter. The collector is connected on the first
+// entry written, so that it may be unreachable at start.
+func NewForwardWriter(options ForwardOptions) (*ForwardWriter, error) {
+	if options.Address == "" {
+		return nil, errors.New("forward: address is required")
+	}
+
+	if options.Tag == "" {
+		return nil, errors.New("forward: tag is required")
+	}
+
+	network := options.Network
+
+	if network == "" {
+		network = "tcp"
+	}
+
+	conn := newNetWriter(network, options.Address, options.TLSConfig, options.Timeout)
+
+	return &ForwardWriter{
+		tag:    options.Tag,
+		conn:   conn,
+		buffer: NewAsyncWriter(conn, options.Buffer),
+	}, nil
+}
+
+// Write encodes the JSON entry in a forward message and buffers it.
+func (w *ForwardWriter) Write(p []byte) (int, error) {
+	message := appendMsgpackString([]byte{0x93}, w.tag)
+	message = appendMsgpackEventTime(message, time.Now())
+	message, err := appendMsgpackJSON(message, bytes.TrimSpace(p))
+
+	if err != nil {
+		return 0, fmt.Errorf("forward: %w", err)
+	}
+
+	if _, err := w.buffer.Write(message); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (w *ForwardWriter) Dropped() int64 {
+	return w.buffer.Dropped()
+}
+
+// Flush blocks until the buffered entries are sent.
+func (w *ForwardWriter) Flush() {
+	w.buffer.Flush()
+}
+
+// Close sends the buffered entries, without retrying when the collector is unreachable,
+// and closes the connection.
+func (w *ForwardWriter) Close() error {
+	w.conn.shutdown()
+	w.buffer.Close()
+
+	return w.conn.Close()
+}
@@ -0,0 +1,111 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestAppendMsgpackJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		expected []byte
+	}{
+		{
+			name:  "Keys are in order",
+			entry: `{"msg":"hi","level":"info"}`,
+			expected: []byte{
+				0x82,
+				0xa3, 'm', 's', 'g', 0xa2, 'h', 'i',
+				0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'i', 'n', 'f', 'o',
+			},
+		},
+		{
+			name:  "Values",
+			entry: `{"a":[1,-1,null,true,false],"b":{"c":300}}`,
+			expected: []byte{
+				0x82,
+				0xa1, 'a', 0x95, 0x01, 0xff, 0xc0, 0xc3, 0xc2,
+				0xa1, 'b', 0x81, 0xa1, 'c', 0xd3, 0, 0, 0, 0, 0, 0, 0x01, 0x2c,
+			},
+		},
+		{
+			name:     "Floats",
+			entry:    `{"f":1.5}`,
+			expected: []byte{0x81, 0xa1, 'f', 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := appendMsgpackJSON(nil, []byte(tt.entry))
+			assert.NilError(t, err)
+			assert.DeepEqual(t, b, tt.expected)
+		})
+	}
+
+	t.Run("Long strings", func(t *testing.T) {
+		b, err := appendMsgpackJSON(nil, []byte(`{"s":"`+string(bytes.Repeat([]byte("x"), 300))+`"}`))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, b[:6], []byte{0x81, 0xa1, 's', 0xda, 0x01, 0x2c})
+		assert.Equal(t, len(b), 306)
+	})
+
+	t.Run("Entries must be JSON objects", func(t *testing.T) {
+		_, err := appendMsgpackJSON(nil, []byte(`level=info msg=hi`))
+		assert.Assert(t, err != nil, "Expected error")
+
+		_, err = appendMsgpackJSON(nil, []byte(`["a"]`))
+		assert.Assert(t, err != nil, "Expected error")
+	})
+}
+
+func TestForwardWriter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte)
+
+	go func() {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		b, _ := io.ReadAll(conn)
+		received <- b
+	}()
+
+	writer, err := NewForwardWriter(ForwardOptions{Address: listener.Addr().String(), Tag: "app"})
+	assert.NilError(t, err)
+
+	before := time.Now()
+
+	_, err = writer.Write([]byte(`{"msg":"hi"}` + "\n"))
+	assert.NilError(t, err)
+
+	_, err = writer.Write([]byte("not json\n"))
+	assert.Assert(t, err != nil, "Expected error")
+
+	assert.NilError(t, writer.Close())
+
+	message := <-received
+
+	assert.DeepEqual(t, message[:7], []byte{0x93, 0xa3, 'a', 'p', 'p', 0xd7, 0x00})
+	seconds := binary.BigEndian.Uint32(message[7:11])
+	assert.Assert(t, int64(seconds) >= before.Unix() && int64(seconds) <= time.Now().Unix())
+	assert.DeepEqual(t, message[15:], []byte{0x81, 0xa3, 'm', 's', 'g', 0xa2, 'h', 'i'})
+
+	_, err = NewForwardWriter(ForwardOptions{Address: listener.Addr().String()})
+	assert.Assert(t, err != nil, "Expected error")
+}
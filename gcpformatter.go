@@ -0,0 +1,194 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cloudTraceContextHeaderKey = "X-Cloud-Trace-Context"
+	cloudTraceContextKey       = "cloudTraceContext"
+	gcpTraceKey                = "logging.googleapis.com/trace"
+	gcpSpanIDKey               = "logging.googleapis.com/spanId"
+	gcpTraceSampledKey         = "logging.googleapis.com/trace_sampled"
+)
+
+// GCPHTTPRequest struct contains the items of the Google Cloud Logging HttpRequest object.
+type GCPHTTPRequest struct {
+	RequestMethod string `json:"requestMethod,omitempty"`
+	RequestURL    string `json:"requestUrl,omitempty"`
+	Status        int    `json:"status,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty"`
+	RemoteIP      string `json:"remoteIp,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+	Latency       string `json:"latency,omitempty"`
+}
+
+// GCPFormatter formats the entries as Google Cloud Logging structured logs: the
+// level is written as severity, the http field is mapped to httpRequest and the
+// X-Cloud-Trace-Context header of the request to the trace and span ID fields.
+type GCPFormatter struct {
+	// ProjectID is the Google Cloud project ID, used to build the trace resource name.
+	ProjectID string
+	// Strict panics with a *FormatError when the entry cannot be formatted, regardless
+	// of SetStrictMode.
+	Strict bool
+}
+
+func gcpSeverity(level logrus.Level) string {
+	switch level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		return "DEBUG"
+	case logrus.InfoLevel:
+		return "INFO"
+	case logrus.WarnLevel:
+		return "WARNING"
+	case logrus.ErrorLevel:
+		return "ERROR"
+	case logrus.FatalLevel:
+		return "CRITICAL"
+	default:
+		return "ALERT"
+	}
+}
+
+func newGCPHTTPRequest(http HTTP, host Host) *GCPHTTPRequest {
+	if http.Request == nil {
+		return nil
+	}
+
+	result := &GCPHTTPRequest{
+		RequestMethod: http.Request.Method,
+		RequestURL:    http.Request.Path,
+		UserAgent:     http.Request.UserAgent,
+		RemoteIP:      host.IP,
+		Protocol:      http.Request.Protocol,
+	}
+
+	if scheme := http.Request.Scheme; (scheme == schemeHTTP || scheme == schemeHTTPS) && host.Hostname != "" {
+		result.RequestURL = scheme + "://" + host.Hostname + http.Request.Path
+	}
+
+	if http.Response != nil {
+		result.Status = http.Response.StatusCode
+		result.Latency = strconv.FormatFloat(http.Response.ResponseTime, 'f', -1, 64) + "s"
+	}
+
+	return result
+}
+
+// usesGCPFormatter returns true if the formatter is a GCPFormatter, or wraps one, such
+// as the formatter of the outputs.
+func usesGCPFormatter(formatter logrus.Formatter) bool {
+	switch formatter := formatter.(type) {
+	case *GCPFormatter:
+		return true
+	case *dedupFormatter:
+		return usesGCPFormatter(formatter.formatter)
+	case *sharedFormatter:
+		return usesGCPFormatter(formatter.formatter)
+	case *sinkFormatter:
+		return usesGCPFormatter(formatter.formatter)
+	case *hashChainFormatter:
+		return usesGCPFormatter(formatter.formatter)
+	case *outputsFormatter:
+		for _, output := range formatter.outputs {
+			if usesGCPFormatter(output.formatter) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// addCloudTrace parses the X-Cloud-Trace-Context value "TRACE_ID/SPAN_ID;o=OPTIONS", the
+// span ID being decimal.
+func (formatter *GCPFormatter) addCloudTrace(data logrus.Fields, traceContext string) {
+	trace, options := traceContext, ""
+
+	if index := strings.Index(trace, ";"); index >= 0 {
+		trace, options = trace[:index], trace[index+1:]
+	}
+
+	traceID, spanID := trace, ""
+
+	if index := strings.Index(trace, "/"); index >= 0 {
+		traceID, spanID = trace[:index], trace[index+1:]
+	}
+
+	if traceID == "" {
+		return
+	}
+
+	if formatter.ProjectID != "" {
+		traceID = fmt.Sprintf("projects/%s/traces/%s", formatter.ProjectID, traceID)
+	}
+
+	data[gcpTraceKey] = traceID
+
+	// The span ID of the header is decimal, and Cloud Logging expects 16 hex digits.
+	if id, err := strconv.ParseUint(spanID, 10, 64); err == nil {
+		data[gcpSpanIDKey] = fmt.Sprintf("%016x", id)
+	}
+
+	if options == "o=1" {
+		data[gcpTraceSampledKey] = true
+	}
+}
+
+// Format function will set how to format entry in Google Cloud Logging JSON
+func (formatter *GCPFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+4)
+
+	data["time"] = entry.Time.UTC().Format(time.RFC3339Nano)
+	data["message"] = entry.Message
+	data["severity"] = gcpSeverity(entry.Level)
+
+	for k, v := range entry.Data {
+		switch v := v.(type) {
+		case error:
+			data[k] = v.Error()
+		default:
+			data[k] = v
+		}
+	}
+
+	if http, ok := entry.Data["http"].(HTTP); ok {
+		host, _ := entry.Data["host"].(Host)
+
+		if httpRequest := newGCPHTTPRequest(http, host); httpRequest != nil {
+			data["httpRequest"] = httpRequest
+		}
+	}
+
+	if traceContext, ok := entry.Data[cloudTraceContextKey].(string); ok {
+		delete(data, cloudTraceContextKey)
+		formatter.addCloudTrace(data, traceContext)
+	}
+
+	var b *bytes.Buffer
+
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	start := b.Len()
+	encoder := json.NewEncoder(b)
+
+	if err := encoder.Encode(data); err != nil {
+		return nil, formatError(entry, formatter.Strict, fmt.Errorf("failed to marshal fields to JSON: %v", err))
+	}
+
+	recordEntrySize(b.Len() - start)
+
+	return b.Bytes(), nil
+}
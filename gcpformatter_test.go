@@ -0,0 +1,83 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestGCPFormatter(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buffer)
+	logger.SetFormatter(&GCPFormatter{ProjectID: "my-project"})
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/v1/users?name=Test", nil)
+	request.Header.Set("X-Cloud-Trace-Context", "105445aa7843bc8bf206b12000100000/1;o=1")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	var log map[string]interface{}
+	err := json.Unmarshal([]byte(strings.TrimSpace(buffer.String())), &log)
+	assert.Assert(t, err == nil, "log is not a JSON")
+
+	httpRequest := log["httpRequest"].(map[string]interface{})
+
+	assert.Equal(t, log["severity"], "WARNING")
+	assert.Assert(t, log["level"] == nil, "Unexpected level field")
+	assert.Assert(t, log[cloudTraceContextKey] == nil, "Unexpected raw trace context field")
+	assert.Equal(t, log["logging.googleapis.com/trace"], "projects/my-project/traces/105445aa7843bc8bf206b12000100000")
+	assert.Equal(t, log["logging.googleapis.com/spanId"], "0000000000000001")
+	assert.Equal(t, log["logging.googleapis.com/trace_sampled"], true)
+	assert.Equal(t, httpRequest["requestMethod"], "GET")
+	assert.Equal(t, httpRequest["requestUrl"], "http://localhost/api/v1/users?name=Test")
+	assert.Equal(t, httpRequest["status"], float64(http.StatusNotFound))
+	assert.Assert(t, strings.HasSuffix(httpRequest["latency"].(string), "s"), "Unexpected latency format")
+
+	t.Run("The trace context is not logged by the other formatters", func(t *testing.T) {
+		buffer.Reset()
+		logger.SetFormatter(&JSONFormatter{})
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Assert(t, !strings.Contains(buffer.String(), cloudTraceContextKey), buffer.String())
+	})
+
+	t.Run("The GCP formatter of an output is used", func(t *testing.T) {
+		outputs, err := newOutputsFormatter([]Output{{Writer: &buffer}, {Writer: &buffer, Formatter: &GCPFormatter{}}})
+		assert.NilError(t, err)
+
+		assert.Assert(t, usesGCPFormatter(newDedupFormatter(outputs, DedupOptions{}, nil)))
+		assert.Assert(t, !usesGCPFormatter(&JSONFormatter{}))
+	})
+}
+
+func TestCloudTrace(t *testing.T) {
+	formatter := &GCPFormatter{}
+
+	for traceContext, spanID := range map[string]interface{}{
+		"105445aa7843bc8bf206b12000100000/18446744073709551615": "ffffffffffffffff",
+		"105445aa7843bc8bf206b12000100000/2882400018;o=0":       "00000000abcdef12",
+		"105445aa7843bc8bf206b12000100000/abc":                  nil,
+		"105445aa7843bc8bf206b12000100000":                      nil,
+	} {
+		data := logrus.Fields{}
+		formatter.addCloudTrace(data, traceContext)
+
+		assert.Equal(t, data[gcpTraceKey], "105445aa7843bc8bf206b12000100000")
+		assert.Equal(t, data[gcpSpanIDKey], spanID, traceContext)
+	}
+}
+
+func TestGCPSeverity(t *testing.T) {
+	assert.Equal(t, gcpSeverity(logrus.TraceLevel), "DEBUG")
+	assert.Equal(t, gcpSeverity(logrus.WarnLevel), "WARNING")
+	assert.Equal(t, gcpSeverity(logrus.FatalLevel), "CRITICAL")
+}
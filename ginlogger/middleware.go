@@ -0,0 +1,69 @@
+// Package ginlogger provides a gin middleware logging the requests with the glogger
+// middleware, including the matched gin route pattern.
+package ginlogger
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+)
+
+type routeKey struct{}
+
+// responseWriter routes the writes of the gin handlers through the glogger middleware writer.
+type responseWriter struct {
+	gin.ResponseWriter
+	writer http.ResponseWriter
+}
+
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	rw.writer.WriteHeader(statusCode)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	return rw.writer.Write(b)
+}
+
+func (rw *responseWriter) WriteString(s string) (int, error) {
+	return rw.writer.Write([]byte(s))
+}
+
+// Hijack hijacks the connection through the glogger middleware writer, which records it.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(rw.writer).Hijack()
+}
+
+func routePattern(r *http.Request) string {
+	route, _ := r.Context().Value(routeKey{}).(string)
+	return route
+}
+
+// Logger returns a gin middleware logging the requests, like glogger.LoggingMiddlewareWithOptions.
+// The gin route pattern, such as /users/:id, is logged in http.request.route unless
+// options.RoutePattern is set. The request scoped logger is available with
+// glogger.Get(c.Request.Context()).
+func Logger(logger *logrus.Logger, options glogger.MiddlewareOptions) gin.HandlerFunc {
+	if options.RoutePattern == nil {
+		options.RoutePattern = routePattern
+	}
+
+	middleware := glogger.LoggingMiddlewareWithOptions(logger, options)
+
+	return func(c *gin.Context) {
+		writer := c.Writer
+
+		handler := middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			c.Writer = &responseWriter{ResponseWriter: writer, writer: rw}
+			c.Next()
+		}))
+
+		handler.ServeHTTP(writer, c.Request.WithContext(context.WithValue(c.Request.Context(), routeKey{}, c.FullPath())))
+		c.Writer = writer
+	}
+}
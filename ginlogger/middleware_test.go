@@ -0,0 +1,40 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, hook := test.NewNullLogger()
+
+	router := gin.New()
+	router.Use(Logger(logger, glogger.MiddlewareOptions{}))
+	router.GET("/users/:id", func(c *gin.Context) {
+		glogger.Get(c.Request.Context()).Info("Handling Request")
+		c.String(http.StatusAccepted, "user %s", c.Param("id"))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, recorder.Code, http.StatusAccepted)
+	assert.Equal(t, recorder.Body.String(), "user 42")
+
+	entries := hook.AllEntries()
+	assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+	assert.Equal(t, entries[0].Data["correlationId"], entries[1].Data["correlationId"])
+
+	httpField := entries[1].Data["http"].(glogger.HTTP)
+	assert.Equal(t, httpField.Request.Path, "/users/42")
+	assert.Equal(t, httpField.Request.Route, "/users/:id")
+	assert.Equal(t, httpField.Response.StatusCode, 202)
+	assert.Equal(t, httpField.Response.Bytes, 7)
+}
@@ -0,0 +1,219 @@
+package gloggertest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// Contract is the expectations of a downstream consumer of the entries, such as a
+// billing pipeline or an alerting rule, published as a JSON spec by the team owning it:
+//
+//	{
+//	  "name": "billing",
+//	  "message": "Completed Request",
+//	  "fields": [
+//	    {"name": "http.response.statusCode", "type": "number", "required": true},
+//	    {"name": "tenant", "type": "string"}
+//	  ]
+//	}
+type Contract struct {
+	// Name identifies the consumer in the violations.
+	Name string `json:"name"`
+	// Message is the message of the entries the contract applies to. Defaults to every
+	// entry.
+	Message string `json:"message,omitempty"`
+	// Fields are the expectations of the fields of the entries.
+	Fields []ContractField `json:"fields"`
+}
+
+// ContractField is the expectation of a consumer on a field of the entries.
+type ContractField struct {
+	// Name is the path of the field in the JSON entry, with the nested keys separated by
+	// dots, such as "http.request.method".
+	Name string `json:"name"`
+	// Type is the JSON type of the field: "string", "number", "boolean", "object" or
+	// "array". Defaults to any type.
+	Type string `json:"type,omitempty"`
+	// Required fails the entries without the field. The type of an optional field is
+	// checked when it is present.
+	Required bool `json:"required,omitempty"`
+}
+
+// LoadContracts returns the contracts of the JSON files of the directory, such as the
+// contracts registered by the consumers in a shared repository.
+func LoadContracts(dir string) ([]Contract, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	contracts := make([]Contract, 0, len(paths))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var contract Contract
+
+		if err := json.Unmarshal(data, &contract); err != nil {
+			return nil, fmt.Errorf("contract %s: %w", path, err)
+		}
+
+		if contract.Name == "" {
+			contract.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	return contracts, nil
+}
+
+// Check returns the violations of the contract by the JSON entry, or nil if the contract
+// doesn't apply to it.
+func (contract Contract) Check(entry []byte) error {
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return fmt.Errorf("contract %s: entry is not a JSON object: %w", contract.Name, err)
+	}
+
+	if !contract.applies(fields) {
+		return nil
+	}
+
+	return contract.check(fields)
+}
+
+func (contract Contract) check(fields map[string]interface{}) error {
+	var violations []error
+
+	for _, field := range contract.Fields {
+		value, ok := lookup(fields, field.Name)
+
+		if !ok {
+			if field.Required {
+				violations = append(violations, fmt.Errorf("contract %s: missing field %s", contract.Name, field.Name))
+			}
+
+			continue
+		}
+
+		if actual := jsonType(value); field.Type != "" && actual != field.Type {
+			violations = append(violations, fmt.Errorf("contract %s: field %s is %s, expected %s", contract.Name, field.Name, actual, field.Type))
+		}
+	}
+
+	return errors.Join(violations...)
+}
+
+func (contract Contract) applies(fields map[string]interface{}) bool {
+	if contract.Message == "" {
+		return true
+	}
+
+	message, _ := lookup(fields, "message")
+
+	return message == contract.Message
+}
+
+// RequireContracts fails the test with the violations of the contracts by the entries
+// captured by the hook, formatted with the formatter of their logger, or if a contract
+// applies to none of the entries:
+//
+//	contracts, err := gloggertest.LoadContracts("testdata/contracts")
+//	...
+//	gloggertest.RequireContracts(t, hook, contracts...)
+func RequireContracts(t TB, hook *test.Hook, contracts ...Contract) {
+	t.Helper()
+
+	var violations strings.Builder
+	applied := make([]bool, len(contracts))
+
+	for i, entry := range hook.AllEntries() {
+		serialized, err := entry.Bytes()
+
+		if err != nil {
+			t.Fatalf("entry %d (%q) can't be formatted: %v", i, entry.Message, err)
+			return
+		}
+
+		var fields map[string]interface{}
+
+		if err := json.Unmarshal(serialized, &fields); err != nil {
+			t.Fatalf("entry %d (%q) is not a JSON object: %v", i, entry.Message, err)
+			return
+		}
+
+		for j, contract := range contracts {
+			if !contract.applies(fields) {
+				continue
+			}
+
+			applied[j] = true
+
+			if err := contract.check(fields); err != nil {
+				fmt.Fprintf(&violations, "\n  entry %d (%q): %s", i, entry.Message, strings.ReplaceAll(err.Error(), "\n", ", "))
+			}
+		}
+	}
+
+	for i, contract := range contracts {
+		if !applied[i] {
+			fmt.Fprintf(&violations, "\n  contract %s applies to none of the entries", contract.Name)
+		}
+	}
+
+	if violations.Len() > 0 {
+		t.Fatalf("contract violations among %d entries%s", len(hook.AllEntries()), violations.String())
+	}
+}
+
+// lookup returns the value at the dotted path of the fields.
+func lookup(fields map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = fields
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// jsonType returns the JSON type of the decoded value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return "null"
+	}
+}
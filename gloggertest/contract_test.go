@@ -0,0 +1,61 @@
+package gloggertest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestContracts(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "billing.json"), []byte(`{
+		"message": "Charged",
+		"fields": [
+			{"name": "payment.amount", "type": "number", "required": true},
+			{"name": "tenant", "type": "string"}
+		]
+	}`), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "alerting.json"), []byte(`{
+		"name": "alerting",
+		"fields": [{"name": "level", "type": "string", "required": true}]
+	}`), 0o644))
+
+	contracts, err := LoadContracts(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(contracts), 2)
+	assert.Equal(t, contracts[1].Name, "billing")
+
+	t.Run("Entries satisfying the contracts", func(t *testing.T) {
+		logger, hook := NewLogger()
+		logger.WithField("payment", map[string]interface{}{"amount": 42}).Info("Charged")
+		logger.Info("Started")
+
+		RequireContracts(t, hook, contracts...)
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		logger, hook := NewLogger()
+		logger.WithField("tenant", 42).Info("Charged")
+
+		fake := &fakeTB{}
+		RequireContracts(fake, hook, contracts...)
+
+		assert.Assert(t, strings.Contains(fake.failure, `entry 0 ("Charged"): contract billing: missing field payment.amount, contract billing: field tenant is number, expected string`), fake.failure)
+	})
+
+	t.Run("Contracts applying to no entry", func(t *testing.T) {
+		logger, hook := NewLogger()
+		logger.Info("Started")
+
+		fake := &fakeTB{}
+		RequireContracts(fake, hook, contracts...)
+
+		assert.Assert(t, strings.HasSuffix(fake.failure, "contract billing applies to none of the entries"), fake.failure)
+	})
+
+	assert.NilError(t, contracts[0].Check([]byte(`{"level":"info"}`)))
+	assert.ErrorContains(t, contracts[0].Check([]byte(`{"message":"Started"}`)), "missing field level")
+}
@@ -0,0 +1,310 @@
+package gloggertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Fixture is an exchange recorded through the logging middleware: the request, the
+// response of the handler, and the JSON entries emitted by the middleware. The entries
+// logged while the handler runs are not recorded.
+type Fixture struct {
+	Request  FixtureRequest           `json:"request"`
+	Response FixtureResponse          `json:"response"`
+	Entries  []map[string]interface{} `json:"entries"`
+}
+
+// FixtureRequest is the request of a Fixture.
+type FixtureRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// FixtureResponse is the response written by the handler of a Fixture.
+type FixtureResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// fixtureCredentialHeaders are the credential headers whose values are masked in the
+// recorded fixtures, which are committed to the repositories.
+var fixtureCredentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// fixtureRedactionMask replaces the values of the credential headers in the fixtures.
+const fixtureRedactionMask = "[REDACTED]"
+
+// Middleware returns the logging middleware under test logging to the logger, such as
+// glogger.LoggingMiddleware.
+type Middleware func(logger *logrus.Logger) mux.MiddlewareFunc
+
+// RecordFixture serves the request with the handler wrapped by the middleware, and writes
+// the Fixture of the exchange as JSON to path, creating its directory. The values of the
+// Authorization, Proxy-Authorization, Cookie and Set-Cookie headers are masked.
+func RecordFixture(path string, middleware Middleware, handler http.Handler, r *http.Request) error {
+	fixture := Fixture{Request: FixtureRequest{Method: r.Method, URL: r.URL.String(), Header: redactFixtureHeader(r.Header)}}
+
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+
+		if err != nil {
+			return err
+		}
+
+		fixture.Request.Body = string(body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	recording := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		recorder := &responseRecorder{ResponseWriter: rw, statusCode: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+
+		if recorder.header == nil {
+			recorder.header = rw.Header().Clone()
+		}
+
+		fixture.Response = FixtureResponse{StatusCode: recorder.statusCode, Header: redactFixtureHeader(recorder.header), Body: recorder.body.String()}
+	})
+
+	entries, err := serve(middleware, recording, r)
+
+	if err != nil {
+		return err
+	}
+
+	fixture.Entries = entries
+	data, err := json.MarshalIndent(fixture, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// redactFixtureHeader returns a copy of the header with the values of the credential
+// headers masked.
+func redactFixtureHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+
+	for _, name := range fixtureCredentialHeaders {
+		if values, ok := redacted[name]; ok {
+			for i := range values {
+				values[i] = fixtureRedactionMask
+			}
+		}
+	}
+
+	return redacted
+}
+
+// ReplayFixture serves the request of the Fixture at path with the middleware, the
+// handler writing the recorded response, and fails the test if the schema of the entries
+// emitted differs from the recorded one: their number, levels and messages, and the paths
+// and JSON types of their fields. The values of the fields, such as the times and the
+// generated IDs, are not compared.
+//
+//	if *update {
+//		err = gloggertest.RecordFixture(fixture, glogger.LoggingMiddleware, handler, request)
+//	}
+//	gloggertest.ReplayFixture(t, fixture, glogger.LoggingMiddleware)
+func ReplayFixture(t TB, path string, middleware Middleware) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatalf("fixture %s can't be read: %v", path, err)
+		return
+	}
+
+	var fixture Fixture
+
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("fixture %s is invalid: %v", path, err)
+		return
+	}
+
+	request := httptest.NewRequest(fixture.Request.Method, fixture.Request.URL, strings.NewReader(fixture.Request.Body))
+	request.Header = fixture.Request.Header.Clone()
+
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+
+	replaying := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		for key, values := range fixture.Response.Header {
+			rw.Header()[key] = values
+		}
+
+		rw.WriteHeader(fixture.Response.StatusCode)
+		io.WriteString(rw, fixture.Response.Body)
+	})
+
+	entries, err := serve(middleware, replaying, request)
+
+	if err != nil {
+		t.Fatalf("fixture %s can't be replayed: %v", path, err)
+		return
+	}
+
+	if differences := diffSchemas(fixture.Entries, entries); len(differences) > 0 {
+		t.Fatalf("fixture %s: the entries differ from the recorded ones:\n  %s", path, strings.Join(differences, "\n  "))
+	}
+}
+
+// serve serves the request with the handler wrapped by the middleware, and returns the
+// JSON entries emitted by the middleware, before and after the handler.
+func serve(middleware Middleware, handler http.Handler, r *http.Request) ([]map[string]interface{}, error) {
+	logger, hook := NewLogger()
+	var before, after int
+
+	wrapped := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		before = len(hook.AllEntries())
+		handler.ServeHTTP(rw, r)
+		after = len(hook.AllEntries())
+	})
+
+	middleware(logger)(wrapped).ServeHTTP(httptest.NewRecorder(), r)
+	entries := hook.AllEntries()
+
+	return decodeEntries(append(entries[:before:before], entries[after:]...))
+}
+
+func decodeEntries(logged []*logrus.Entry) ([]map[string]interface{}, error) {
+	var entries []map[string]interface{}
+
+	for _, entry := range logged {
+		serialized, err := entry.Bytes()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var fields map[string]interface{}
+
+		if err := json.Unmarshal(serialized, &fields); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, fields)
+	}
+
+	return entries, nil
+}
+
+// diffSchemas returns the differences between the schemas of the recorded and replayed
+// entries.
+func diffSchemas(recorded, replayed []map[string]interface{}) []string {
+	if len(recorded) != len(replayed) {
+		return []string{fmt.Sprintf("%d entries, recorded %d", len(replayed), len(recorded))}
+	}
+
+	var differences []string
+
+	for i := range recorded {
+		for _, key := range []string{"level", "message"} {
+			if recorded[i][key] != replayed[i][key] {
+				differences = append(differences, fmt.Sprintf("entry %d: %s is %v, recorded %v", i, key, replayed[i][key], recorded[i][key]))
+			}
+		}
+
+		recordedSchema, replayedSchema := schema(recorded[i]), schema(replayed[i])
+		var paths []string
+
+		for path := range recordedSchema {
+			paths = append(paths, path)
+		}
+
+		for path := range replayedSchema {
+			if _, ok := recordedSchema[path]; !ok {
+				paths = append(paths, path)
+			}
+		}
+
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			recordedType, wasRecorded := recordedSchema[path]
+			replayedType, isReplayed := replayedSchema[path]
+
+			switch {
+			case !isReplayed:
+				differences = append(differences, fmt.Sprintf("entry %d: missing field %s", i, path))
+			case !wasRecorded:
+				differences = append(differences, fmt.Sprintf("entry %d: new field %s", i, path))
+			case recordedType != replayedType:
+				differences = append(differences, fmt.Sprintf("entry %d: field %s is %s, recorded %s", i, path, replayedType, recordedType))
+			}
+		}
+	}
+
+	return differences
+}
+
+// schema returns the JSON types of the fields of the entry by dot-separated path. The
+// objects are flattened, and the arrays typed as a whole.
+func schema(fields map[string]interface{}) map[string]string {
+	types := map[string]string{}
+
+	var walk func(prefix string, fields map[string]interface{})
+
+	walk = func(prefix string, fields map[string]interface{}) {
+		for key, value := range fields {
+			if object, ok := value.(map[string]interface{}); ok {
+				walk(prefix+key+".", object)
+				continue
+			}
+
+			types[prefix+key] = jsonType(value)
+		}
+	}
+
+	walk("", fields)
+
+	return types
+}
+
+// responseRecorder records the response written by the handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	header     http.Header
+	body       bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(statusCode int) {
+	if w.header == nil {
+		w.statusCode = statusCode
+		w.header = w.Header().Clone()
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	if w.header == nil {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.body.Write(p)
+
+	return w.ResponseWriter.Write(p)
+}
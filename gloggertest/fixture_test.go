@@ -0,0 +1,64 @@
+package gloggertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures", "create-order.json")
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		glogger.Get(r.Context()).WithField("orderId", "42").Info("Order created")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusCreated)
+		rw.Write([]byte(`{"id":"42"}`))
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/orders?express=1", strings.NewReader(`{"item":"book"}`))
+	request.Header.Set("X-Request-Id", "request-id")
+	request.Header.Set("Authorization", "Bearer secret")
+
+	assert.NilError(t, RecordFixture(path, glogger.LoggingMiddleware, handler, request))
+
+	data, err := os.ReadFile(path)
+	assert.NilError(t, err)
+
+	var fixture Fixture
+	assert.NilError(t, json.Unmarshal(data, &fixture))
+	assert.Equal(t, fixture.Request.Body, `{"item":"book"}`)
+	assert.Equal(t, fixture.Request.Header.Get("Authorization"), "[REDACTED]")
+	assert.Equal(t, fixture.Request.Header.Get("X-Request-Id"), "request-id")
+	assert.Assert(t, !strings.Contains(string(data), "secret"), string(data))
+	assert.Equal(t, request.Header.Get("Authorization"), "Bearer secret")
+	assert.Equal(t, fixture.Response.StatusCode, http.StatusCreated)
+	assert.Equal(t, fixture.Response.Body, `{"id":"42"}`)
+	assert.Equal(t, len(fixture.Entries), 2)
+	assert.Equal(t, fixture.Entries[1]["message"], "Completed Request")
+
+	t.Run("Replay with the same schema", func(t *testing.T) {
+		ReplayFixture(t, path, glogger.LoggingMiddleware)
+	})
+
+	t.Run("Replay with a changed schema", func(t *testing.T) {
+		changed := func(logger *logrus.Logger) mux.MiddlewareFunc {
+			return glogger.LoggingMiddlewareWithOptions(logger, glogger.MiddlewareOptions{Timing: true, CompletedRequestMessage: "Request completed"})
+		}
+
+		fake := &fakeTB{}
+		ReplayFixture(fake, path, changed)
+
+		assert.Assert(t, strings.Contains(fake.failure, "message is Request completed, recorded Completed Request"), fake.failure)
+		assert.Assert(t, strings.Contains(fake.failure, "new field timing.duration"), fake.failure)
+	})
+}
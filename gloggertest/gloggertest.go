@@ -0,0 +1,168 @@
+// Package gloggertest provides helpers to assert on the entries logged with glogger in
+// the tests: a capturing logger, entry matchers, a request context, the contracts of the
+// consumers of the entries and the fixtures of the middleware.
+package gloggertest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// TB is the subset of testing.TB used by the helpers.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// NewLogger returns a logger discarding its output at trace level, and the hook
+// capturing its entries.
+func NewLogger() (*logrus.Logger, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.TraceLevel)
+	logger.SetFormatter(&glogger.JSONFormatter{})
+
+	return logger, hook
+}
+
+// NewContext returns a context with the request logger and the request ID installed
+// by the glogger middleware, as the context of a request handled by it.
+func NewContext(logger *logrus.Logger, requestID string) context.Context {
+	var ctx context.Context
+
+	options := glogger.MiddlewareOptions{Skip: func(r *http.Request) bool { return true }}
+	handler := glogger.LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Request-Id", requestID)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	return ctx
+}
+
+// FieldMatcher returns an error describing the mismatch if the fields don't match.
+type FieldMatcher func(fields logrus.Fields) error
+
+// Field matches the entries whose field has the value, compared with reflect.DeepEqual.
+func Field(key string, value interface{}) FieldMatcher {
+	return func(fields logrus.Fields) error {
+		actual, ok := fields[key]
+
+		if !ok {
+			return fmt.Errorf("missing field %s", key)
+		}
+
+		if !reflect.DeepEqual(actual, value) {
+			return fmt.Errorf("field %s is %#v, expected %#v", key, actual, value)
+		}
+
+		return nil
+	}
+}
+
+// HasField matches the entries with the field, whatever its value.
+func HasField(key string) FieldMatcher {
+	return func(fields logrus.Fields) error {
+		if _, ok := fields[key]; !ok {
+			return fmt.Errorf("missing field %s", key)
+		}
+
+		return nil
+	}
+}
+
+// NoField matches the entries without the field.
+func NoField(key string) FieldMatcher {
+	return func(fields logrus.Fields) error {
+		if value, ok := fields[key]; ok {
+			return fmt.Errorf("unexpected field %s: %#v", key, value)
+		}
+
+		return nil
+	}
+}
+
+// FieldFunc matches the entries whose field value satisfies the predicate.
+func FieldFunc(key string, predicate func(value interface{}) bool) FieldMatcher {
+	return func(fields logrus.Fields) error {
+		value, ok := fields[key]
+
+		if !ok {
+			return fmt.Errorf("missing field %s", key)
+		}
+
+		if !predicate(value) {
+			return fmt.Errorf("field %s is %#v, not matching the predicate", key, value)
+		}
+
+		return nil
+	}
+}
+
+// FindEntry returns the first entry captured by the hook with the level, the message and
+// the fields matching all the matchers, or nil.
+func FindEntry(hook *test.Hook, level logrus.Level, message string, matchers ...FieldMatcher) *logrus.Entry {
+	for _, entry := range hook.AllEntries() {
+		if mismatch(entry, level, message, matchers) == "" {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// RequireEntry returns the first entry captured by the hook with the level, the message
+// and the fields matching all the matchers. The test fails with the mismatches of the
+// captured entries if there is none:
+//
+//	gloggertest.RequireEntry(t, hook, logrus.InfoLevel, "Completed Request",
+//		gloggertest.Field("correlationId", "id"))
+func RequireEntry(t TB, hook *test.Hook, level logrus.Level, message string, matchers ...FieldMatcher) *logrus.Entry {
+	t.Helper()
+
+	entries := hook.AllEntries()
+	var mismatches strings.Builder
+
+	for i, entry := range entries {
+		reason := mismatch(entry, level, message, matchers)
+
+		if reason == "" {
+			return entry
+		}
+
+		fmt.Fprintf(&mismatches, "\n  entry %d (%s %q): %s", i, entry.Level, entry.Message, reason)
+	}
+
+	t.Fatalf("no %s entry %q matching among %d entries%s", level, message, len(entries), mismatches.String())
+
+	return nil
+}
+
+func mismatch(entry *logrus.Entry, level logrus.Level, message string, matchers []FieldMatcher) string {
+	if entry.Level != level {
+		return "level is " + entry.Level.String()
+	}
+
+	if entry.Message != message {
+		return "message differs"
+	}
+
+	var reasons []string
+
+	for _, matcher := range matchers {
+		if err := matcher(entry.Data); err != nil {
+			reasons = append(reasons, err.Error())
+		}
+	}
+
+	return strings.Join(reasons, ", ")
+}
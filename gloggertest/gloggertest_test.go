@@ -0,0 +1,57 @@
+package gloggertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+// fakeTB records the failure instead of failing the test.
+type fakeTB struct {
+	failure string
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Fatalf(format string, args ...interface{}) {
+	t.failure = fmt.Sprintf(format, args...)
+}
+
+func TestNewContext(t *testing.T) {
+	logger, hook := NewLogger()
+	ctx := NewContext(logger, "request-id")
+
+	assert.Equal(t, glogger.RequestIDFromContext(ctx), "request-id")
+
+	glogger.Get(ctx).WithField("user", "alice").Debug("Handled")
+
+	entry := RequireEntry(t, hook, logrus.DebugLevel, "Handled",
+		Field("correlationId", "request-id"),
+		HasField("user"),
+		NoField("error"),
+		FieldFunc("user", func(value interface{}) bool { return strings.HasPrefix(value.(string), "a") }),
+	)
+	assert.Equal(t, entry.Data["user"], "alice")
+	assert.Equal(t, len(hook.AllEntries()), 1)
+}
+
+func TestRequireEntry(t *testing.T) {
+	logger, hook := NewLogger()
+	logger.WithField("user", "bob").Info("Handled")
+	logger.Warn("Handled")
+
+	fake := &fakeTB{}
+	entry := RequireEntry(fake, hook, logrus.InfoLevel, "Handled", Field("user", "alice"))
+
+	assert.Assert(t, entry == nil)
+	assert.Equal(t, fake.failure, `no info entry "Handled" matching among 2 entries`+
+		"\n  entry 0 (info \"Handled\"): field user is \"bob\", expected \"alice\""+
+		"\n  entry 1 (warning \"Handled\"): level is warning")
+
+	assert.Assert(t, FindEntry(hook, logrus.WarnLevel, "Handled") == hook.LastEntry())
+	assert.Assert(t, FindEntry(hook, logrus.ErrorLevel, "Handled") == nil)
+}
@@ -1,19 +1,19 @@
 module github.com/platform-horizon/glogger
 
-go 1.17
+go 1.21
 
 require (
-	github.com/google/uuid v1.1.3
+	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.8.0
+	github.com/klauspost/compress v1.17.0
+	github.com/pires/go-proxyproto v0.7.0
 	github.com/sirupsen/logrus v1.7.0
-	github.com/stretchr/testify v1.2.2
 	gotest.tools v2.2.0+incompatible
 )
 
 require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/google/go-cmp v0.5.6 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
 )
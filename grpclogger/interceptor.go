@@ -0,0 +1,195 @@
+// Package grpclogger provides gRPC server interceptors logging the calls with
+// the same schema of the glogger HTTP middleware.
+package grpclogger
+
+import (
+	"context"
+	"time"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	correlationIDKey = "x-request-id"
+	authorityKey     = ":authority"
+	userAgentKey     = "user-agent"
+	forwardedHostKey = "x-forwarded-host"
+
+	callTypeUnary        = "unary"
+	callTypeClientStream = "client_stream"
+	callTypeServerStream = "server_stream"
+	callTypeBidiStream   = "bidi_stream"
+)
+
+// Call struct contains items of gRPC call info log.
+type Call struct {
+	Method       string  `json:"method,omitempty"`
+	Type         string  `json:"type,omitempty"`
+	UserAgent    string  `json:"userAgent,omitempty"`
+	Code         string  `json:"code,omitempty"`
+	StatusCode   int     `json:"statusCode"`
+	ResponseTime float64 `json:"responseTime,omitempty"`
+}
+
+func getMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Options is the struct of options to configure the interceptors.
+type Options struct {
+	// IDGenerator generates the correlation IDs of the calls without one in their
+	// metadata, such as glogger.UUIDv7, glogger.ULID or glogger.KSUID for IDs ordered by
+	// time. Defaults to glogger.UUIDv4.
+	IDGenerator glogger.IDGenerator
+}
+
+func (options Options) getCorrelationID(md metadata.MD) string {
+	if correlationID := getMetadata(md, correlationIDKey); correlationID != "" {
+		return correlationID
+	}
+
+	if options.IDGenerator == nil {
+		return glogger.UUIDv4()
+	}
+
+	return options.IDGenerator()
+}
+
+func newHost(ctx context.Context, md metadata.MD) glogger.Host {
+	host := glogger.Host{
+		ForwardedHostname: getMetadata(md, forwardedHostKey),
+	}
+
+	host.Hostname, _ = glogger.SplitHostPort(getMetadata(md, authorityKey))
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip, _ := glogger.SplitHostPort(p.Addr.String())
+		host.IP = glogger.NormalizeIP(ip)
+	}
+
+	return host
+}
+
+// logCall logs the incoming call, invokes the handler with the request scoped logger
+// in the context and logs the completed call.
+func (options Options) logCall(ctx context.Context, logger *logrus.Logger, method string, callType string, handler func(ctx context.Context) error) error {
+	start := time.Now()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = glogger.WithLogger(ctx, logrus.NewEntry(logger).WithFields(logrus.Fields{
+		"correlationId": options.getCorrelationID(md),
+	}))
+
+	call := Call{
+		Method:    method,
+		Type:      callType,
+		UserAgent: getMetadata(md, userAgentKey),
+	}
+	host := newHost(ctx, md)
+
+	glogger.Get(ctx).WithFields(logrus.Fields{
+		"grpc": call,
+		"host": host,
+	}).Trace("Incoming Request")
+
+	err := handler(ctx)
+
+	code := status.Code(err)
+	call.Code = code.String()
+	call.StatusCode = int(code)
+	call.ResponseTime = time.Since(start).Seconds()
+
+	glogger.Get(ctx).WithFields(logrus.Fields{
+		"grpc": call,
+		"host": host,
+	}).Info("Completed Request")
+
+	return err
+}
+
+// UnaryServerInterceptor is a gRPC unary interceptor to log all calls.
+// It logs the incoming call and when the call is completed, and injects the
+// request scoped logger in the context, to be retrieved with glogger.Get.
+func UnaryServerInterceptor(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return UnaryServerInterceptorWithOptions(logger, Options{})
+}
+
+// UnaryServerInterceptorWithOptions is a UnaryServerInterceptor configured by the options.
+func UnaryServerInterceptorWithOptions(logger *logrus.Logger, options Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var resp interface{}
+
+		err := options.logCall(ctx, logger, info.FullMethod, callTypeUnary, func(ctx context.Context) error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		})
+
+		return resp, err
+	}
+}
+
+type loggingServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *loggingServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func streamCallType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return callTypeBidiStream
+	case info.IsClientStream:
+		return callTypeClientStream
+	default:
+		return callTypeServerStream
+	}
+}
+
+// StreamServerInterceptor is a gRPC stream interceptor to log all calls.
+// It logs the incoming call and when the call is completed, and injects the
+// request scoped logger in the stream context, to be retrieved with glogger.Get.
+func StreamServerInterceptor(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return StreamServerInterceptorWithOptions(logger, Options{})
+}
+
+// StreamServerInterceptorWithOptions is a StreamServerInterceptor configured by the
+// options.
+func StreamServerInterceptorWithOptions(logger *logrus.Logger, options Options) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return options.logCall(ss.Context(), logger, info.FullMethod, streamCallType(info), func(ctx context.Context) error {
+			return handler(srv, &loggingServerStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+}
+
+// UnaryClientInterceptor is a gRPC unary client interceptor recording the outgoing calls
+// with glogger.RecordDependency, in the dependencies summary of the completed request
+// entry of the request context. The calls whose code is not OK are failures.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		call := glogger.DependencyCall{Name: method, ResponseTime: time.Since(start).Seconds()}
+
+		if err != nil {
+			call.Error = err.Error()
+		}
+
+		glogger.RecordDependency(ctx, call)
+
+		return err
+	}
+}
@@ -0,0 +1,126 @@
+package grpclogger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func incomingContext() context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "request-id",
+		":authority", "localhost:50051",
+		"user-agent", "grpc-go/1.67.0",
+	))
+
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 43210}})
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *fakeServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.TraceLevel)
+
+	interceptor := UnaryServerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.v1.Users/GetUser"}
+
+	_, err := interceptor(incomingContext(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Equal(t, glogger.Get(ctx).Data["correlationId"], "request-id")
+		return nil, status.Error(codes.NotFound, "user not found")
+	})
+
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	entries := hook.AllEntries()
+	assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+	assert.Equal(t, entries[0].Message, "Incoming Request")
+	assert.Equal(t, entries[1].Message, "Completed Request")
+
+	call := entries[1].Data["grpc"].(Call)
+	host := entries[1].Data["host"].(glogger.Host)
+
+	assert.Equal(t, call.Method, "/users.v1.Users/GetUser")
+	assert.Equal(t, call.Type, "unary")
+	assert.Equal(t, call.Code, "NotFound")
+	assert.Equal(t, call.StatusCode, int(codes.NotFound))
+	assert.Equal(t, call.UserAgent, "grpc-go/1.67.0")
+	assert.Assert(t, call.ResponseTime != 0, "Unexpected response time equal to 0")
+	assert.Equal(t, host.Hostname, "localhost")
+	assert.Equal(t, host.IP, "192.0.2.1")
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	interceptor := StreamServerInterceptor(logger)
+	info := &grpc.StreamServerInfo{FullMethod: "/users.v1.Users/ListUsers", IsServerStream: true}
+
+	err := interceptor(nil, &fakeServerStream{ctx: incomingContext()}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		assert.Equal(t, glogger.Get(stream.Context()).Data["correlationId"], "request-id")
+		return nil
+	})
+
+	assert.Assert(t, err == nil, "Unexpected error")
+
+	call := hook.LastEntry().Data["grpc"].(Call)
+
+	assert.Equal(t, call.Type, "server_stream")
+	assert.Equal(t, call.Code, "OK")
+}
+
+func TestInterceptorIDGenerator(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	interceptor := UnaryServerInterceptorWithOptions(logger, Options{IDGenerator: func() string { return "generated-id" }})
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.v1.Users/GetUser"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.Equal(t, glogger.Get(ctx).Data["correlationId"], "generated-id")
+		return nil, nil
+	})
+
+	assert.Assert(t, err == nil, "Unexpected error")
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	interceptor := UnaryClientInterceptor()
+	handler := glogger.LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+
+		err := interceptor(r.Context(), "/users.v1.Users/GetUser", nil, nil, nil, invoker)
+		assert.Equal(t, status.Code(err), codes.Unavailable)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	dependencies := hook.LastEntry().Data["dependencies"].(glogger.Dependencies)
+
+	assert.Equal(t, dependencies.Calls, 1)
+	assert.Equal(t, dependencies.Failures, 1)
+	assert.Equal(t, dependencies.FirstFailure.Name, "/users.v1.Users/GetUser")
+	assert.Equal(t, dependencies.FirstFailure.Error, "rpc error: code = Unavailable desc = connection refused")
+}
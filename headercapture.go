@@ -0,0 +1,37 @@
+package glogger
+
+import (
+	"net/http"
+	"strings"
+)
+
+// deniedCaptureHeaders are the credential headers never captured, even if allowed.
+var deniedCaptureHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// captureHeaders returns the values of the allowed headers present in the header, joined
+// with commas, by canonical name. The credential headers are never captured.
+func captureHeaders(allowed []string, header http.Header) map[string]string {
+	var captured map[string]string
+
+	for _, name := range allowed {
+		name = http.CanonicalHeaderKey(name)
+		values := header.Values(name)
+
+		if deniedCaptureHeaders[name] || len(values) == 0 {
+			continue
+		}
+
+		if captured == nil {
+			captured = make(map[string]string, len(allowed))
+		}
+
+		captured[name] = strings.Join(values, ", ")
+	}
+
+	return captured
+}
@@ -0,0 +1,58 @@
+package glogger
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventKey         = "event"
+	honeypotEvent    = "honeypot"
+	requestHeaderKey = "headers"
+)
+
+// Event struct contains items of event info log.
+type Event struct {
+	Kind string `json:"kind,omitempty"`
+}
+
+// isHoneypot returns true if the request path matches one of the honeypot paths.
+func (options MiddlewareOptions) isHoneypot(r *http.Request) bool {
+	for _, pattern := range options.HoneypotPaths {
+		if matched, _ := path.Match(pattern, r.URL.Path); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// honeypotHeaders returns the request headers logged for a honeypot request: the allowed
+// headers if any, or all of them, the credential headers excepted as in captureHeaders.
+func honeypotHeaders(allowed []string, header http.Header) map[string]string {
+	if len(allowed) == 0 {
+		for name := range header {
+			allowed = append(allowed, name)
+		}
+	}
+
+	return captureHeaders(allowed, header)
+}
+
+// withHoneypot adds the event kind and the request headers to the fields of the completed
+// entry of a honeypot request, and returns its level, at least warning.
+func withHoneypot(fields logrus.Fields, headers map[string]string, level logrus.Level) logrus.Level {
+	fields[eventKey] = Event{Kind: honeypotEvent}
+
+	if len(headers) > 0 {
+		fields[requestHeaderKey] = headers
+	}
+
+	if level > logrus.WarnLevel {
+		return logrus.WarnLevel
+	}
+
+	return level
+}
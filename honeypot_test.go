@@ -0,0 +1,50 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestHoneypotPaths(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	options := MiddlewareOptions{
+		HoneypotPaths: []string{"/admin/backup.zip"},
+		ExcludedPaths: []string{"/admin/*"},
+		Sampling:      SamplingOptions{Rate: 0.000001},
+		Redaction:     RedactionOptions{Headers: []string{"X-Api-Key"}},
+	}
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+
+	request := httptest.NewRequest(http.MethodGet, "/admin/backup.zip", nil)
+	request.Header.Set("Authorization", "Basic secret")
+	request.Header.Set("Cookie", "session=secret")
+	request.Header.Set("X-Api-Key", "secret")
+	request.Header.Set("User-Agent", "scanner")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	entry := hook.LastEntry()
+	assert.Assert(t, entry != nil)
+	assert.Equal(t, entry.Level, logrus.WarnLevel)
+	assert.Equal(t, entry.Data["event"], Event{Kind: "honeypot"})
+	assert.DeepEqual(t, entry.Data["headers"], map[string]string{"X-Api-Key": "[REDACTED]", "User-Agent": "scanner"})
+	assert.Equal(t, entry.Data["host"].(Host).IP, "192.0.2.1")
+
+	hook.Reset()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+
+	assert.Equal(t, len(hook.AllEntries()), 0)
+
+	t.Run("The captured request headers are logged only", func(t *testing.T) {
+		options.CaptureRequestHeaders = []string{"User-Agent", "Authorization"}
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.DeepEqual(t, hook.LastEntry().Data["headers"], map[string]string{"User-Agent": "scanner"})
+	})
+}
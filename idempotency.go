@@ -0,0 +1,76 @@
+package glogger
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	idempotencyKey       = "idempotency"
+	idempotencyHeaderKey = "Idempotency-Key"
+	maxIdempotencyKeys   = 10000
+)
+
+// Idempotency struct contains items of idempotency info log.
+type Idempotency struct {
+	Key            string `json:"key"`
+	Attempt        int    `json:"attempt,omitempty"`
+	FirstRequestID string `json:"firstRequestId,omitempty"`
+}
+
+// idempotencyTracker counts the attempts of the requests with the same idempotency key
+// within a window, remembering the request ID of the first attempt.
+type idempotencyTracker struct {
+	window   time.Duration
+	now      func() time.Time
+	mutex    sync.Mutex
+	attempts map[string]*idempotentRequest
+}
+
+type idempotentRequest struct {
+	start     time.Time
+	requestID string
+	count     int
+}
+
+func newIdempotencyTracker(window time.Duration, now func() time.Time) *idempotencyTracker {
+	if window <= 0 {
+		return nil
+	}
+
+	return &idempotencyTracker{window: window, now: now, attempts: map[string]*idempotentRequest{}}
+}
+
+// idempotency returns the idempotency of the request, if it has an Idempotency-Key header.
+// The attempts are counted only by a non-nil tracker.
+func (tracker *idempotencyTracker) idempotency(r *http.Request, requestID string) (Idempotency, bool) {
+	key := r.Header.Get(idempotencyHeaderKey)
+
+	if key == "" {
+		return Idempotency{}, false
+	}
+
+	if tracker == nil {
+		return Idempotency{Key: key}, true
+	}
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	current := tracker.now()
+	request, ok := tracker.attempts[key]
+
+	if !ok || current.Sub(request.start) >= tracker.window {
+		if !ok && len(tracker.attempts) >= maxIdempotencyKeys {
+			tracker.attempts = map[string]*idempotentRequest{}
+		}
+
+		request = &idempotentRequest{start: current, requestID: requestID}
+		tracker.attempts[key] = request
+	}
+
+	request.count++
+
+	return Idempotency{Key: key, Attempt: request.count, FirstRequestID: request.requestID}, true
+}
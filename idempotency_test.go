@@ -0,0 +1,59 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestIdempotencyTracker(t *testing.T) {
+	now := time.Now()
+	tracker := newIdempotencyTracker(time.Minute, func() time.Time { return now })
+
+	request := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	request.Header.Set("Idempotency-Key", "key")
+
+	idempotency, ok := tracker.idempotency(request, "first")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, idempotency, Idempotency{Key: "key", Attempt: 1, FirstRequestID: "first"})
+
+	idempotency, _ = tracker.idempotency(request, "second")
+	assert.DeepEqual(t, idempotency, Idempotency{Key: "key", Attempt: 2, FirstRequestID: "first"})
+
+	now = now.Add(time.Minute)
+	idempotency, _ = tracker.idempotency(request, "third")
+	assert.DeepEqual(t, idempotency, Idempotency{Key: "key", Attempt: 1, FirstRequestID: "third"})
+
+	var disabled *idempotencyTracker
+	idempotency, _ = disabled.idempotency(request, "fourth")
+	assert.DeepEqual(t, idempotency, Idempotency{Key: "key"})
+
+	_, ok = tracker.idempotency(httptest.NewRequest(http.MethodPost, "/payments", nil), "fifth")
+	assert.Assert(t, !ok)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{IdempotencyWindow: time.Minute})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		Get(r.Context()).Info("Charging")
+	}))
+
+	for _, requestID := range []string{"first", "second"} {
+		request := httptest.NewRequest(http.MethodPost, "/payments", nil)
+		request.Header.Set("Idempotency-Key", "key")
+		request.Header.Set("X-Request-Id", requestID)
+
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	entries := hook.AllEntries()
+	expected := Idempotency{Key: "key", Attempt: 2, FirstRequestID: "first"}
+
+	assert.DeepEqual(t, entries[len(entries)-2].Data["idempotency"], expected)
+	assert.DeepEqual(t, entries[len(entries)-1].Data["idempotency"], expected)
+}
@@ -0,0 +1,167 @@
+package glogger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator returns a new ID, such as a request ID, or an empty string if none can be
+// generated. The time-ordered generators, UUIDv7, ULID and KSUID, make the range scans
+// of the log stores by ID cheaper, as the IDs of the close requests are close.
+type IDGenerator func() string
+
+// ksuidEpoch is the epoch of the KSUID timestamps, in Unix seconds.
+const ksuidEpoch = 1400000000
+
+const (
+	// serviceCodeSeparator separates the service code from the ID generated.
+	serviceCodeSeparator = "_"
+	maxServiceCodeLength = 8
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62Alphabet    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	ksuidLength       = 27
+)
+
+// UUIDv4 returns a random UUID, such as "f47ac10b-58cc-4372-a567-0e02b2c3d479". It is the
+// default IDGenerator.
+func UUIDv4() string {
+	id, err := uuid.NewRandom()
+
+	if err != nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+// UUIDv7 returns a UUID ordered by its millisecond timestamp, such as
+// "01890a5d-ac96-774b-bcce-b302099a8057".
+func UUIDv7() string {
+	id, err := uuid.NewV7()
+
+	if err != nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+// ULID returns a ULID of 26 characters, such as "01ARZ3NDEKTSV4RRFFQ69G5FAV": a 48-bit
+// millisecond timestamp followed by 80 random bits, encoded in Crockford's base32. The
+// ULIDs are ordered by their timestamps.
+func ULID() string {
+	var id [16]byte
+	ms := uint64(time.Now().UnixMilli())
+
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		return ""
+	}
+
+	// The 128 bits are encoded in 26 characters of 5 bits, the first one holding 3 bits.
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	encoded := make([]byte, 26)
+
+	for i := len(encoded) - 1; i >= 0; i-- {
+		encoded[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(encoded)
+}
+
+// KSUID returns a KSUID of 27 characters, such as "0ujtsYcgvSTl8PAuAdqWYSMnLOv": a 32-bit
+// timestamp in seconds since the KSUID epoch followed by 128 random bits, encoded in
+// base62. The KSUIDs are ordered by their timestamps.
+func KSUID() string {
+	var id [20]byte
+	binary.BigEndian.PutUint32(id[:4], uint32(time.Now().Unix()-ksuidEpoch))
+
+	if _, err := rand.Read(id[4:]); err != nil {
+		return ""
+	}
+
+	value := new(big.Int).SetBytes(id[:])
+	base, remainder := big.NewInt(62), new(big.Int)
+	encoded := make([]byte, ksuidLength)
+
+	for i := range encoded {
+		encoded[i] = '0'
+	}
+
+	for i := ksuidLength - 1; value.Sign() > 0; i-- {
+		value.DivMod(value, base, remainder)
+		encoded[i] = base62Alphabet[remainder.Int64()]
+	}
+
+	return string(encoded)
+}
+
+func (generate IDGenerator) newID() string {
+	if generate == nil {
+		return UUIDv4()
+	}
+
+	return generate()
+}
+
+// ServiceIDGenerator returns an IDGenerator prefixing the IDs of the generator, UUIDv4 if
+// nil, with the service code and an underscore, such as "bill_01ARZ3NDEKTSV4RRFFQ69G5FAV",
+// so that a bare request ID identifies the service which generated it. The service code
+// has 1 to 8 lowercase letters or digits, and is shared by the services of the monorepo.
+func ServiceIDGenerator(service string, generate IDGenerator) (IDGenerator, error) {
+	if !validServiceCode(service) {
+		return nil, fmt.Errorf("invalid service code %q", service)
+	}
+
+	return func() string {
+		id := generate.newID()
+
+		if id == "" {
+			return ""
+		}
+
+		return service + serviceCodeSeparator + id
+	}, nil
+}
+
+// ParseRequestID returns the service code and the generated ID of a request ID of a
+// ServiceIDGenerator. It reports false for the IDs without a valid service code, such as
+// the IDs generated without prefix.
+func ParseRequestID(id string) (service, generated string, ok bool) {
+	service, generated, ok = strings.Cut(id, serviceCodeSeparator)
+
+	if !ok || generated == "" || !validServiceCode(service) {
+		return "", "", false
+	}
+
+	return service, generated, true
+}
+
+func validServiceCode(service string) bool {
+	if service == "" || len(service) > maxServiceCodeLength {
+		return false
+	}
+
+	for _, c := range service {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
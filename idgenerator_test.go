@@ -0,0 +1,101 @@
+package glogger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gotest.tools/assert"
+)
+
+func TestIDGenerators(t *testing.T) {
+	t.Run("UUIDv4 is random", func(t *testing.T) {
+		id, err := uuid.Parse(UUIDv4())
+		assert.NilError(t, err)
+		assert.Equal(t, id.Version(), uuid.Version(4))
+	})
+
+	t.Run("UUIDv7 is ordered by time", func(t *testing.T) {
+		id, err := uuid.Parse(UUIDv7())
+		assert.NilError(t, err)
+		assert.Equal(t, id.Version(), uuid.Version(7))
+	})
+
+	tests := []struct {
+		name     string
+		generate IDGenerator
+		format   *regexp.Regexp
+		interval time.Duration
+	}{
+		{name: "ULID", generate: ULID, format: regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), interval: 2 * time.Millisecond},
+		{name: "KSUID", generate: KSUID, format: regexp.MustCompile(`^[0-9A-Za-z]{27}$`), interval: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" is sortable", func(t *testing.T) {
+			first := tt.generate()
+			assert.Assert(t, tt.format.MatchString(first), "Unexpected %s %q", tt.name, first)
+
+			time.Sleep(tt.interval)
+
+			second := tt.generate()
+			assert.Assert(t, tt.format.MatchString(second), "Unexpected %s %q", tt.name, second)
+			assert.Assert(t, first < second, "Expected %q before %q", first, second)
+		})
+	}
+
+	t.Run("ULID encodes the millisecond timestamp", func(t *testing.T) {
+		before := time.Now().UnixMilli()
+		id := ULID()
+		after := time.Now().UnixMilli()
+
+		var ms int64
+
+		for _, c := range id[:10] {
+			ms = ms<<5 | int64(strings.IndexRune(crockfordAlphabet, c))
+		}
+
+		assert.Assert(t, before <= ms && ms <= after, "Unexpected timestamp %d", ms)
+	})
+}
+
+func TestServiceIDGenerator(t *testing.T) {
+	t.Run("IDs are prefixed with the service code", func(t *testing.T) {
+		generate, err := ServiceIDGenerator("bill", ULID)
+		assert.NilError(t, err)
+
+		id := generate()
+		service, generated, ok := ParseRequestID(id)
+
+		assert.Assert(t, ok, "Expected a prefixed ID %q", id)
+		assert.Equal(t, service, "bill")
+		assert.Equal(t, len(generated), 26)
+	})
+
+	t.Run("Generator defaults to UUIDv4", func(t *testing.T) {
+		generate, err := ServiceIDGenerator("auth", nil)
+		assert.NilError(t, err)
+
+		_, generated, ok := ParseRequestID(generate())
+		assert.Assert(t, ok)
+
+		_, err = uuid.Parse(generated)
+		assert.NilError(t, err)
+	})
+
+	t.Run("Invalid service codes are rejected", func(t *testing.T) {
+		for _, service := range []string{"", "Bill", "billing-v2", "toolongcode"} {
+			_, err := ServiceIDGenerator(service, nil)
+			assert.ErrorContains(t, err, "invalid service code")
+		}
+	})
+
+	t.Run("IDs without service code are not parsed", func(t *testing.T) {
+		for _, id := range []string{UUIDv4(), "bill_", "BILL_123", "toolongcode_123"} {
+			_, _, ok := ParseRequestID(id)
+			assert.Assert(t, !ok, "Unexpected service code in %q", id)
+		}
+	})
+}
@@ -1,30 +1,164 @@
 package glogger
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
 	"github.com/sirupsen/logrus"
 )
 
 // InitOptions is the struct of options to configure logger
 type InitOptions struct {
-	Level string
+	// Profile presets the options for an environment: ProfileProduction,
+	// ProfileDevelopment or ProfileTest. The Level and Format set take precedence, and the
+	// boolean options enabled by the profile can't be disabled. Defaults to no profile.
+	Profile string
+	Level   string
+	// ProcessFields adds the pid and instanceId fields to every entry, with an instance
+	// ID generated at Init.
+	ProcessFields bool
+	// GoroutineID adds the goroutineId field to every entry along with the process
+	// fields or the caller. It is costly and meant for debugging.
+	GoroutineID bool
+	// Caller adds the caller field to every entry, with the file, line and function of
+	// the code which logged it, glogger and logrus excepted. It is costly and meant for
+	// debugging.
+	Caller bool
+	// Format is the output format: FormatJSON, the default, or FormatConsole for local
+	// development. The console format falls back to JSON when the output is not a terminal.
+	Format string
+	// Output is the destination of the entries. Defaults to os.Stderr.
+	Output io.Writer
+	// Outputs are multiple destinations of the entries, each with its own minimum level
+	// and formatter, replacing Output and Format. SetOutput and SetFormatter on the
+	// returned logger remove the outputs.
+	Outputs []Output
+	// Dedup collapses the identical entries logged within a window, such as the errors
+	// of a crash looping dependency, into the first one. SetFormatter or SetOutput on the
+	// returned logger removes the deduplication.
+	Dedup DedupOptions
+	// StaticFields are added to every entry, such as the service name, version and
+	// environment, so that all the entries carry the service identity.
+	StaticFields logrus.Fields
+	// ComponentLevels are the minimum levels of the components named with Named, such as
+	// {"api.db": "debug"}, applying to their sub-components too. The level of the logger,
+	// which may change at runtime, applies to the other entries. The entries of the
+	// components are checked against their level before the hooks run. The components
+	// share the formatter and output of the logger: set them in the options rather than
+	// on the returned logger.
+	ComponentLevels map[string]string
+	// Constrained restricts the logger to the locked-down environments, such as the
+	// distroless images with a read-only filesystem and a seccomp profile: the entries
+	// are written to the provided writers only, and Init fails when an output would
+	// create files, such as a RotatingFile. The console format falls back to JSON without
+	// probing the output for a terminal. glogger makes no metadata request, nor hostname
+	// lookup unless enabled, such as with the ReverseDNS of the middleware.
+	Constrained bool
 }
 
 // Init function to init json logger
 func Init(option InitOptions) (*logrus.Logger, error) {
+	option, err := option.withProfile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if option.Constrained {
+		if err := option.checkConstrained(); err != nil {
+			return nil, err
+		}
+	}
+
 	logger := logrus.New()
-	logger.SetFormatter(&JSONFormatter{})
 
-	if option.Level == "" {
-		return logger, nil
+	if option.Output != nil {
+		logger.SetOutput(option.Output)
 	}
 
-	level, err := logrus.ParseLevel(option.Level)
+	switch option.Format {
+	case "", FormatJSON:
+		logger.SetFormatter(&JSONFormatter{})
+	case FormatConsole:
+		if file, ok := logger.Out.(*os.File); ok && !option.Constrained && isTerminal(file) {
+			logger.SetFormatter(&ConsoleFormatter{})
+		} else {
+			logger.SetFormatter(&JSONFormatter{})
+		}
+	default:
+		return nil, fmt.Errorf("invalid format %q", option.Format)
+	}
 
-	if err != nil {
-		return nil, err
+	if len(option.Outputs) > 0 {
+		formatter, err := newOutputsFormatter(option.Outputs)
+
+		if err != nil {
+			return nil, err
+		}
+
+		logger.SetFormatter(formatter)
+		logger.SetOutput(io.Discard)
+	}
+
+	if option.Dedup.Window > 0 {
+		dedup := newDedupFormatter(logger.Formatter, option.Dedup, time.Now)
+		logger.SetFormatter(dedup)
+		logger.SetOutput(dedup.output(logger.Out))
 	}
 
-	logger.SetLevel(level)
+	if len(option.StaticFields) > 0 {
+		logger.AddHook(NewStaticFieldsHook(option.StaticFields))
+	}
+
+	if option.ProcessFields {
+		logger.AddHook(NewProcessHook(option.GoroutineID))
+	}
+
+	if option.Caller {
+		logger.AddHook(NewCallerHook(option.GoroutineID && !option.ProcessFields))
+	}
+
+	if option.Level != "" {
+		if err := SetLevel(logger, option.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(option.ComponentLevels) > 0 {
+		if _, err := newComponentLevels(logger, option.ComponentLevels); err != nil {
+			return nil, err
+		}
+	}
 
 	return logger, nil
 }
+
+// checkConstrained checks that no output creates files, including the outputs wrapped
+// by an AsyncWriter or a CoalescingWriter.
+func (option InitOptions) checkConstrained() error {
+	writers := []io.Writer{option.Output}
+
+	for _, output := range option.Outputs {
+		writers = append(writers, output.Writer)
+	}
+
+	for _, writer := range writers {
+		for writer != nil {
+			switch wrapper := writer.(type) {
+			case *RotatingFile:
+				return errors.New("constrained: a rotating file creates files, provide a writer instead")
+			case *AsyncWriter:
+				writer = wrapper.writer
+			case *CoalescingWriter:
+				writer = wrapper.writer
+			default:
+				writer = nil
+			}
+		}
+	}
+
+	return nil
+}
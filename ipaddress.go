@@ -0,0 +1,100 @@
+package glogger
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// SplitHostPort splits a network address such as "example.com:80", "[::1]:80",
+// "::1" or "192.0.2.1" into host and port. Unlike net.SplitHostPort, a missing
+// port is not an error and the brackets of IPv6 literals are always removed.
+func SplitHostPort(hostport string) (host, port string) {
+	if strings.HasPrefix(hostport, "[") {
+		end := strings.Index(hostport, "]")
+
+		if end < 0 {
+			return hostport, ""
+		}
+
+		host, rest := hostport[1:end], hostport[end+1:]
+
+		return host, strings.TrimPrefix(rest, ":")
+	}
+
+	if strings.Count(hostport, ":") != 1 {
+		return hostport, ""
+	}
+
+	index := strings.LastIndex(hostport, ":")
+
+	return hostport[:index], hostport[index+1:]
+}
+
+// NormalizeIP returns the canonical form of an IP address: zone IDs are stripped,
+// IPv4-mapped IPv6 addresses are rendered as IPv4 and IPv6 addresses are compressed.
+// Values that are not IP addresses are returned unchanged.
+func NormalizeIP(ip string) string {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+
+	if err != nil {
+		return ip
+	}
+
+	return addr.WithZone("").Unmap().String()
+}
+
+func normalizeIPList(list string) string {
+	ips := strings.Split(list, ",")
+
+	for i, ip := range ips {
+		ips[i] = NormalizeIP(strings.TrimSpace(ip))
+	}
+
+	return strings.Join(ips, ", ")
+}
+
+// TruncateIP anonymizes an IP address by zeroing its host part: the last octet of IPv4
+// addresses and the last 64 bits of IPv6 addresses. Values that are not IP addresses
+// are returned unchanged.
+func TruncateIP(ip string) string {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+
+	if err != nil {
+		return ip
+	}
+
+	addr = addr.WithZone("").Unmap()
+	bits := 64
+
+	if addr.Is4() {
+		bits = 24
+	}
+
+	prefix, _ := addr.Prefix(bits)
+
+	return prefix.Addr().String()
+}
+
+// HashIP returns a function anonymizing the IP addresses with an HMAC-SHA256 keyed by the
+// salt, so that the requests of a client can still be correlated without storing its IP.
+// The salt must be kept secret and rotated to prevent the recovery of the addresses.
+func HashIP(salt string) func(ip string) string {
+	return func(ip string) string {
+		return keyedHash(salt, NormalizeIP(ip))
+	}
+}
+
+// anonymizeIPList anonymizes each IP address of a comma separated list.
+func anonymizeIPList(list string, anonymize func(string) string) string {
+	if list == "" {
+		return ""
+	}
+
+	ips := strings.Split(list, ",")
+
+	for i, ip := range ips {
+		ips[i] = anonymize(strings.TrimSpace(ip))
+	}
+
+	return strings.Join(ips, ", ")
+}
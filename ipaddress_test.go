@@ -0,0 +1,83 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestSplitHostPort(t *testing.T) {
+	cases := []struct {
+		hostport string
+		host     string
+		port     string
+	}{
+		{"localhost:3000", "localhost", "3000"},
+		{"localhost", "localhost", ""},
+		{"192.0.2.1:80", "192.0.2.1", "80"},
+		{"[::1]:3000", "::1", "3000"},
+		{"[fe80::1%eth0]", "fe80::1%eth0", ""},
+		{"2001:db8::1", "2001:db8::1", ""},
+	}
+
+	for _, c := range cases {
+		host, port := SplitHostPort(c.hostport)
+
+		assert.Equal(t, host, c.host, "Unexpected host for %s", c.hostport)
+		assert.Equal(t, port, c.port, "Unexpected port for %s", c.hostport)
+	}
+}
+
+func TestNormalizeIP(t *testing.T) {
+	cases := map[string]string{
+		"192.0.2.1":        "192.0.2.1",
+		"::ffff:192.0.2.1": "192.0.2.1",
+		"fe80::1%eth0":     "fe80::1",
+		"2001:0db8:0000:0000:0000:0000:0000:0001": "2001:db8::1",
+		"not-an-ip": "not-an-ip",
+	}
+
+	for ip, expected := range cases {
+		assert.Equal(t, NormalizeIP(ip), expected, "Unexpected normalization for %s", ip)
+	}
+
+	assert.Equal(t, normalizeIPList("::ffff:192.0.2.1,  2001:db8:0::1"), "192.0.2.1, 2001:db8::1")
+}
+
+func TestTruncateIP(t *testing.T) {
+	cases := map[string]string{
+		"192.0.2.123":             "192.0.2.0",
+		"::ffff:192.0.2.123":      "192.0.2.0",
+		"2001:db8:cafe:1:2:3:4:5": "2001:db8:cafe:1::",
+		"fe80::1%eth0":            "fe80::",
+		"not-an-ip":               "not-an-ip",
+	}
+
+	for ip, expected := range cases {
+		assert.Equal(t, TruncateIP(ip), expected, "Unexpected truncation for %s", ip)
+	}
+}
+
+func TestHashIP(t *testing.T) {
+	hash := HashIP("salt")
+
+	assert.Equal(t, len(hash("192.0.2.1")), 32)
+	assert.Equal(t, hash("192.0.2.1"), hash("::ffff:192.0.2.1"))
+	assert.Assert(t, hash("192.0.2.1") != hash("192.0.2.2"))
+	assert.Assert(t, hash("192.0.2.1") != HashIP("other")("192.0.2.1"))
+}
+
+func TestAnonymizeIP(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	options := MiddlewareOptions{AnonymizeIP: TruncateIP}
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Forwarded-For", "198.51.100.17, 2001:db8::1")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, hook.LastEntry().Data["host"].(Host).IP, "198.51.100.0, 2001:db8::")
+}
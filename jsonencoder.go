@@ -0,0 +1,514 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The JSON encoder of the JSONFormatter serializes the entries without reflection and
+// without building an intermediate map, appending to the logrus buffer. Its output is
+// byte-identical to encoding/json: the known types of the schema are encoded by hand,
+// every other value is encoded with encoding/json.
+
+const hexDigits = "0123456789abcdef"
+
+var (
+	// escapeShortControls reports whether encoding/json escapes \b and \f with their
+	// short form, which depends on the Go version.
+	escapeShortControls = func() bool {
+		b, _ := json.Marshal("\b")
+		return string(b) == `"\b"`
+	}()
+
+	// invalidUTF8 is the replacement of the invalid UTF-8 bytes by encoding/json, which
+	// also depends on the Go version.
+	invalidUTF8 = func() string {
+		b, _ := json.Marshal("\xff")
+		return string(b[1 : len(b)-1])
+	}()
+
+	// levelNames are the JSON encoding of the levels, precomputed to avoid the allocations
+	// of logrus.Level.MarshalText.
+	levelNames = func() []string {
+		names := make([]string, len(logrus.AllLevels))
+
+		for _, level := range logrus.AllLevels {
+			text, _ := level.MarshalText()
+			names[level] = string(appendJSONString(nil, string(text), true))
+		}
+
+		return names
+	}()
+
+	keysPool = sync.Pool{
+		New: func() interface{} {
+			keys := make([]string, 0, 16)
+			return &keys
+		},
+	}
+)
+
+// appendJSONString appends the JSON string s, escaped as encoding/json does.
+func appendJSONString(b []byte, s string, escapeHTML bool) []byte {
+	b = append(b, '"')
+	start := 0
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && (!escapeHTML || (c != '<' && c != '>' && c != '&')) {
+				i++
+				continue
+			}
+
+			b = append(b, s[start:i]...)
+
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c == '\b' && escapeShortControls:
+				b = append(b, '\\', 'b')
+			case c == '\f' && escapeShortControls:
+				b = append(b, '\\', 'f')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, invalidUTF8...)
+			i += size
+			start = i
+			continue
+		}
+
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+
+		i += size
+	}
+
+	b = append(b, s[start:]...)
+
+	return append(b, '"')
+}
+
+// appendJSONFloat appends the float as encoding/json does. It reports false for NaN and
+// infinite values, which are not valid JSON.
+func appendJSONFloat(b []byte, f float64, bits int) ([]byte, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return b, false
+	}
+
+	format := byte('f')
+
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+
+	if format == 'e' {
+		// Clean up e-09 to e-9, as encoding/json does.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+
+	return b, true
+}
+
+// jsonObject appends the fields of a JSON object, separated by commas.
+type jsonObject struct {
+	b          []byte
+	escapeHTML bool
+	empty      bool
+}
+
+func newJSONObject(b []byte, escapeHTML bool) jsonObject {
+	return jsonObject{b: append(b, '{'), escapeHTML: escapeHTML, empty: true}
+}
+
+func (object *jsonObject) key(key string) {
+	if !object.empty {
+		object.b = append(object.b, ',')
+	}
+
+	object.empty = false
+	object.b = appendJSONString(object.b, key, object.escapeHTML)
+	object.b = append(object.b, ':')
+}
+
+func (object *jsonObject) string(key string, value string) {
+	if value == "" {
+		return
+	}
+
+	object.key(key)
+	object.b = appendJSONString(object.b, value, object.escapeHTML)
+}
+
+func (object *jsonObject) bool(key string, value bool) {
+	if !value {
+		return
+	}
+
+	object.key(key)
+	object.b = append(object.b, "true"...)
+}
+
+func (object *jsonObject) int(key string, value int) {
+	if value == 0 {
+		return
+	}
+
+	object.key(key)
+	object.b = strconv.AppendInt(object.b, int64(value), 10)
+}
+
+func (object *jsonObject) float(key string, value float64) error {
+	if value == 0 {
+		return nil
+	}
+
+	object.key(key)
+
+	if result, ok := appendJSONFloat(object.b, value, 64); ok {
+		object.b = result
+		return nil
+	}
+
+	return object.value(value)
+}
+
+func (object *jsonObject) strings(key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	object.key(key)
+	object.b = append(object.b, '[')
+
+	for i, value := range values {
+		if i > 0 {
+			object.b = append(object.b, ',')
+		}
+
+		object.b = appendJSONString(object.b, value, object.escapeHTML)
+	}
+
+	object.b = append(object.b, ']')
+}
+
+// stringMap appends the map as a JSON object with sorted keys, as encoding/json does.
+func (object *jsonObject) stringMap(key string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+
+	keysPointer := keysPool.Get().(*[]string)
+	defer keysPool.Put(keysPointer)
+
+	keys := (*keysPointer)[:0]
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	*keysPointer = keys
+
+	object.key(key)
+	nested := newJSONObject(object.b, object.escapeHTML)
+
+	for _, key := range keys {
+		nested.key(key)
+		nested.b = appendJSONString(nested.b, values[key], nested.escapeHTML)
+	}
+
+	object.b = nested.close()
+}
+
+func (object *jsonObject) value(value interface{}) error {
+	var err error
+	object.b, err = appendJSONValue(object.b, value, object.escapeHTML)
+	return err
+}
+
+func (object *jsonObject) close() []byte {
+	return append(object.b, '}')
+}
+
+// appendJSONValue appends the JSON encoding of the value.
+func appendJSONValue(b []byte, value interface{}, escapeHTML bool) ([]byte, error) {
+	switch value := value.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case string:
+		return appendJSONString(b, value, escapeHTML), nil
+	case bool:
+		return strconv.AppendBool(b, value), nil
+	case int:
+		return strconv.AppendInt(b, int64(value), 10), nil
+	case int64:
+		return strconv.AppendInt(b, value, 10), nil
+	case int32:
+		return strconv.AppendInt(b, int64(value), 10), nil
+	case uint:
+		return strconv.AppendUint(b, uint64(value), 10), nil
+	case uint64:
+		return strconv.AppendUint(b, value, 10), nil
+	case float64:
+		if result, ok := appendJSONFloat(b, value, 64); ok {
+			return result, nil
+		}
+	case float32:
+		if result, ok := appendJSONFloat(b, float64(value), 32); ok {
+			return result, nil
+		}
+	case logrus.Level:
+		if int(value) < len(levelNames) {
+			return append(b, levelNames[value]...), nil
+		}
+	case HTTP:
+		return appendJSONHTTP(b, value, escapeHTML)
+	case *HTTP:
+		if value != nil {
+			return appendJSONHTTP(b, *value, escapeHTML)
+		}
+	case Host:
+		return appendJSONHost(b, value, escapeHTML), nil
+	case *Host:
+		if value != nil {
+			return appendJSONHost(b, *value, escapeHTML), nil
+		}
+	}
+
+	return appendJSONFallback(b, value, escapeHTML)
+}
+
+// appendJSONFallback appends the value encoded with encoding/json.
+func appendJSONFallback(b []byte, value interface{}, escapeHTML bool) ([]byte, error) {
+	if escapeHTML {
+		data, err := json.Marshal(value)
+		return append(b, data...), err
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return b, err
+	}
+
+	return append(b, bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))...), nil
+}
+
+func appendJSONHTTP(b []byte, value HTTP, escapeHTML bool) ([]byte, error) {
+	object := newJSONObject(b, escapeHTML)
+
+	if value.Request != nil {
+		object.key("request")
+
+		if err := object.appendRequest(value.Request); err != nil {
+			return object.b, err
+		}
+	}
+
+	if value.Response != nil {
+		object.key("response")
+
+		if err := object.appendResponse(value.Response); err != nil {
+			return object.b, err
+		}
+	}
+
+	return object.close(), nil
+}
+
+func (object *jsonObject) appendRequest(request *Request) error {
+	nested := newJSONObject(object.b, object.escapeHTML)
+
+	nested.string("path", request.Path)
+	nested.string("route", request.Route)
+	nested.string("method", request.Method)
+	nested.string("query", request.Query)
+	nested.string("content-type", request.ContentType)
+	nested.string("scheme", request.Scheme)
+	nested.string("protocol", request.Protocol)
+	nested.string("userAgent", request.UserAgent)
+
+	if len(request.Parts) > 0 {
+		nested.key("parts")
+
+		if err := nested.value(request.Parts); err != nil {
+			return err
+		}
+	}
+
+	nested.string("body", request.Body)
+	nested.bool("bodyTruncated", request.BodyTruncated)
+	nested.string("ifNoneMatch", request.IfNoneMatch)
+	nested.string("ifModifiedSince", request.IfModifiedSince)
+
+	if request.Range != nil {
+		nested.key("range")
+
+		if err := nested.value(request.Range); err != nil {
+			return err
+		}
+	}
+
+	nested.stringMap("headers", request.Headers)
+	nested.string("receivedAt", request.ReceivedAt)
+	nested.string("bodySha256", request.BodySHA256)
+
+	object.b = nested.close()
+
+	return nil
+}
+
+func (object *jsonObject) appendResponse(response *Response) error {
+	nested := newJSONObject(object.b, object.escapeHTML)
+
+	nested.int("statusCode", response.StatusCode)
+
+	if err := nested.float("responseTime", response.ResponseTime); err != nil {
+		return err
+	}
+
+	nested.int("bytes", response.Bytes)
+	nested.string("content-type", response.ContentType)
+	nested.string("body", response.Body)
+	nested.bool("bodyTruncated", response.BodyTruncated)
+	nested.string("etag", response.ETag)
+	nested.string("cacheControl", response.CacheControl)
+	nested.bool("notModified", response.NotModified)
+
+	if response.ContentRange != nil {
+		nested.key("contentRange")
+
+		if err := nested.value(response.ContentRange); err != nil {
+			return err
+		}
+	}
+
+	nested.bool("partial", response.Partial)
+	nested.strings("securityHeaders", response.SecurityHeaders)
+	nested.stringMap("headers", response.Headers)
+	nested.bool("hijacked", response.Hijacked)
+	nested.string("upgrade", response.Upgrade)
+	nested.bool("statusNotWritten", response.StatusNotWritten)
+	nested.bool("aborted", response.Aborted)
+	nested.string("contextError", response.ContextError)
+
+	object.b = nested.close()
+
+	return nil
+}
+
+func appendJSONHost(b []byte, value Host, escapeHTML bool) []byte {
+	object := newJSONObject(b, escapeHTML)
+
+	object.string("hostname", value.Hostname)
+	object.string("forwardedHostname", value.ForwardedHostname)
+	object.string("ip", value.IP)
+
+	return object.close()
+}
+
+// appendJSONEntry appends the entry as a JSON object with sorted keys, followed by a
+// newline, like the map of fields encoded by encoding/json. The fields of the entry
+// take precedence over time, message, level and tz, and entryBytes, if not negative, over
+// the fields. The object starts with the prefix, the bytes of a StaticPrefix, if not nil.
+func appendJSONEntry(b []byte, prefix []byte, entry *logrus.Entry, entryBytes int, timestamp timestampFormat, escapeHTML bool) ([]byte, error) {
+	keysPointer := keysPool.Get().(*[]string)
+	defer keysPool.Put(keysPointer)
+
+	keys := (*keysPointer)[:0]
+
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+
+	for _, key := range [...]string{timestamp.key, "message", "level", timeZoneKey} {
+		if _, ok := entry.Data[key]; !ok && (key != timeZoneKey || timestamp.zone) {
+			keys = append(keys, key)
+		}
+	}
+
+	if _, ok := entry.Data[entryBytesKey]; !ok && entryBytes >= 0 {
+		keys = append(keys, entryBytesKey)
+	}
+
+	slices.Sort(keys)
+	*keysPointer = keys
+
+	object := newJSONObject(b, escapeHTML)
+
+	if prefix != nil {
+		object.b = append(b, prefix...)
+	}
+
+	for _, key := range keys {
+		object.key(key)
+
+		var err error
+
+		switch value, ok := entry.Data[key]; {
+		case key == entryBytesKey && entryBytes >= 0:
+			object.b = strconv.AppendInt(object.b, int64(entryBytes), 10)
+		case ok:
+			if v, isError := value.(error); isError {
+				value = v.Error()
+			}
+
+			err = object.value(value)
+		case key == timestamp.key:
+			object.b = timestamp.append(object.b, entry.Time, escapeHTML)
+		case key == "message":
+			object.b = appendJSONString(object.b, entry.Message, escapeHTML)
+		case key == "level" && int(entry.Level) < len(levelNames):
+			object.b = append(object.b, levelNames[entry.Level]...)
+		case key == "level":
+			err = object.value(entry.Level)
+		case key == timeZoneKey:
+			object.b = appendJSONString(object.b, timestamp.location.String(), escapeHTML)
+		}
+
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return append(object.close(), '\n'), nil
+}
@@ -0,0 +1,194 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+// referenceFormat formats the entry with encoding/json, as the formatter did before the
+// JSON encoder.
+func referenceFormat(entry *logrus.Entry, escapeHTML bool, entryBytes int) string {
+	data := logrus.Fields{
+		"time":    entry.Time.Unix(),
+		"message": entry.Message,
+		"level":   entry.Level,
+	}
+
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+
+		data[k] = v
+	}
+
+	if entryBytes >= 0 {
+		data[entryBytesKey] = entryBytes
+	}
+
+	var b bytes.Buffer
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(escapeHTML)
+	encoder.Encode(data)
+
+	return b.String()
+}
+
+// fill sets every field of the value to a non-zero value, so that the fields added to
+// the schema without updating the JSON encoder are detected.
+func fill(value reflect.Value, seed int) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		value.Set(reflect.New(value.Type().Elem()))
+		fill(value.Elem(), seed)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				fill(value.Field(i), seed+i)
+			}
+		}
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 2, 2))
+		fill(value.Index(0), seed)
+		fill(value.Index(1), seed+1)
+	case reflect.Map:
+		value.Set(reflect.MakeMap(value.Type()))
+
+		for i := 0; i < 2; i++ {
+			key := reflect.New(value.Type().Key()).Elem()
+			element := reflect.New(value.Type().Elem()).Elem()
+			fill(key, seed+i+1)
+			fill(element, seed+i)
+			value.SetMapIndex(key, element)
+		}
+	case reflect.String:
+		value.SetString(strings.Repeat("<v&> \"", seed%3+1))
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		value.SetInt(int64(seed + 1))
+	case reflect.Float64:
+		value.SetFloat(float64(seed) + 0.000000125)
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	full := HTTP{}
+	fill(reflect.ValueOf(&full).Elem(), 0)
+
+	host := Host{}
+	fill(reflect.ValueOf(&host).Elem(), 3)
+
+	values := map[string]interface{}{
+		"fullHTTP":    full,
+		"fullHTTPPtr": &full,
+		"emptyHTTP":   HTTP{Request: &Request{}, Response: &Response{}},
+		"host":        host,
+		"strings":     "quote \" backslash \\ controls \n\r\t\b\f\x01\x1f html <&> unicode é 日本    invalid \xff\xfe",
+		"floats":      []float64{0, 1, -1.5, 1e-7, 1e21, 123456789.123, 1e-6},
+		"float32":     float32(3.14),
+		"smallFloat":  0.000000001,
+		"largeFloat":  1e22,
+		"ints":        int64(-42),
+		"uint":        uint(42),
+		"bool":        true,
+		"nil":         nil,
+		"error":       errors.New("failed <here>"),
+		"level":       logrus.WarnLevel,
+		"map":         map[string]interface{}{"b": 1, "a": "<x>"},
+		"time":        time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC),
+		"stream":      Stream{Events: 1, Bytes: 2},
+		"raw":         json.RawMessage(`{"b": 1, "a": "<x>"}`),
+	}
+
+	entry := &logrus.Entry{
+		Time:    time.Now(),
+		Level:   logrus.InfoLevel,
+		Message: "Completed <Request>",
+		Data:    logrus.Fields(values),
+	}
+
+	for _, escapeHTML := range []bool{true, false} {
+		formatter := JSONFormatter{DisableHTMLEscape: !escapeHTML}
+
+		actual, err := formatter.Format(entry)
+		assert.NilError(t, err)
+		assert.Equal(t, string(actual), referenceFormat(entry, escapeHTML, -1))
+	}
+
+	t.Run("Fields take precedence over time, message and level", func(t *testing.T) {
+		entry := &logrus.Entry{Time: time.Now(), Message: "Entry", Data: logrus.Fields{"message": "field", "level": 3}}
+
+		actual, err := (&JSONFormatter{}).Format(entry)
+		assert.NilError(t, err)
+		assert.Equal(t, string(actual), referenceFormat(entry, true, -1))
+	})
+
+	t.Run("Entry size is added above the threshold", func(t *testing.T) {
+		entry := &logrus.Entry{Time: time.Now(), Message: "Entry", Data: logrus.Fields{"http": full}}
+
+		first, err := (&JSONFormatter{}).Format(entry)
+		assert.NilError(t, err)
+
+		actual, err := (&JSONFormatter{EntrySizeThreshold: 10}).Format(entry)
+		assert.NilError(t, err)
+		assert.Equal(t, string(actual), referenceFormat(entry, true, len(first)))
+	})
+
+	t.Run("Unsupported values fail", func(t *testing.T) {
+		SetStrictMode(false)
+		defer SetStrictMode(true)
+
+		for _, value := range []interface{}{math.NaN(), math.Inf(1), logrus.Level(42)} {
+			_, err := (&JSONFormatter{}).Format(&logrus.Entry{Data: logrus.Fields{"value": value}})
+			assert.Assert(t, err != nil, "Expected error for %v", value)
+		}
+	})
+}
+
+func BenchmarkJSONFormatter(b *testing.B) {
+	entry := &logrus.Entry{
+		Time:    time.Now(),
+		Level:   logrus.InfoLevel,
+		Message: "Completed Request",
+		Buffer:  &bytes.Buffer{},
+		Data: logrus.Fields{
+			"correlationId": "4f0a2f1e-6d1b-4c1a-9e53-0b8d2f7c9e11",
+			"http": HTTP{
+				Request:  &Request{Path: "/api/orders/42?expand=items", Method: "GET", Query: "expand=items", Scheme: "http", Protocol: "HTTP/1.1", UserAgent: userAgent},
+				Response: &Response{StatusCode: 200, ResponseTime: 0.001234, Bytes: 28, ContentType: "application/json"},
+			},
+			"host": Host{Hostname: hostname, IP: "10.0.0.1"},
+		},
+	}
+
+	b.Run("compact", func(b *testing.B) {
+		formatter := JSONFormatter{}
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			entry.Buffer.Reset()
+			formatter.Format(entry)
+		}
+	})
+
+	b.Run("canonical", func(b *testing.B) {
+		formatter := JSONFormatter{Canonical: true}
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			entry.Buffer.Reset()
+			formatter.Format(entry)
+		}
+	})
+}
@@ -4,18 +4,90 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 // JSONFormatter struct
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	// Canonical enables a byte-stable output for identical entries: the keys are
+	// sorted at every level, struct fields included, and HTML characters are not escaped.
+	Canonical bool
+	// DisableHTMLEscape disables the escaping of &, < and > in JSON strings.
+	DisableHTMLEscape bool
+	// PrettyPrint indents the JSON output over multiple lines, for local development
+	// and test golden files. The output is no longer newline-delimited JSON (NDJSON)
+	// and must not be used with log collectors expecting one entry per line.
+	PrettyPrint bool
+	// EntrySizeThreshold, when greater than zero, adds the entryBytes field with the
+	// serialized size to the entries larger than the threshold, in bytes.
+	EntrySizeThreshold int
+	// ColorProfile colors the pretty printed output. If nil, the profile configured
+	// by the GLOGGER_COLORS environment variable is used, if any.
+	ColorProfile *ColorProfile
+	// TruncateValues truncates the string values of the pretty printed output to keep each
+	// line within LineWidth, with an ellipsis and the number of omitted characters.
+	TruncateValues bool
+	// LineWidth is the maximum line width of the pretty printed output. Defaults to the
+	// terminal width from the COLUMNS environment variable, or 120.
+	LineWidth int
+	// Strict panics with a *FormatError when the entry cannot be formatted, regardless
+	// of SetStrictMode.
+	Strict bool
+	// CompatAliases maps the dot-separated path of a field, such as
+	// "http.response.responseTime", to the path of an alias emitted with the same value,
+	// such as "http.response.duration", so that both names of a renamed field are emitted
+	// during a deprecation window while the consumers migrate. The objects on the path of
+	// an alias are emitted with sorted keys.
+	CompatAliases map[string]string
+	// TimestampFormat is the format of the time field: TimestampUnixMillis,
+	// TimestampUnixNanos or a time layout, such as time.RFC3339Nano. Defaults to unix
+	// seconds.
+	TimestampFormat string
+	// TimeField is the key of the time field, such as "@timestamp". Defaults to "time".
+	TimeField string
+	// Location is the location of the formatted time. Defaults to UTC, use time.Local for
+	// the local time.
+	Location *time.Location
+	// TimeZoneField adds the tz field with the name of the Location, such as "Europe/Paris".
+	TimeZoneField bool
+	// StaticPrefix adds its static fields to every entry. The compact entries start with
+	// its bytes, followed by the other fields, unless the entry has a field of the same key,
+	// which takes precedence.
+	StaticPrefix *StaticPrefix
+}
+
+const entryBytesKey = "entryBytes"
 
 // Format function will set how to format entry in JSON
 func (formatter *JSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if len(formatter.CompatAliases) > 0 {
+		aliased, err := withCompatAliases(entry, formatter.CompatAliases, formatter.timestampFormat())
+
+		if err != nil {
+			return nil, formatError(entry, formatter.Strict, fmt.Errorf("failed to alias fields: %v", err))
+		}
+
+		entry = aliased
+	}
+
+	if !formatter.Canonical && !formatter.PrettyPrint {
+		return formatter.formatCompact(entry)
+	}
+
+	if formatter.StaticPrefix != nil {
+		entry = formatter.StaticPrefix.withFields(entry)
+	}
+
 	data := make(logrus.Fields, len(entry.Data)+4)
 
-	data["time"] = entry.Time.Unix()
+	timestamp := formatter.timestampFormat()
+	data[timestamp.key] = timestamp.value(entry.Time)
+
+	if timestamp.zone {
+		data[timeZoneKey] = timestamp.location.String()
+	}
 	data["message"] = entry.Message
 	data["level"] = entry.Level
 
@@ -36,11 +108,154 @@ func (formatter *JSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		b = &bytes.Buffer{}
 	}
 
+	start := b.Len()
+
+	if err := formatter.encode(b, data); err != nil {
+		return nil, formatError(entry, formatter.Strict, err)
+	}
+
+	size := b.Len() - start
+
+	if formatter.EntrySizeThreshold > 0 && size > formatter.EntrySizeThreshold {
+		b.Truncate(start)
+		data[entryBytesKey] = size
+
+		if err := formatter.encode(b, data); err != nil {
+			return nil, formatError(entry, formatter.Strict, err)
+		}
+
+		size = b.Len() - start
+	}
+
+	recordEntrySize(size)
+
+	if formatter.PrettyPrint {
+		output := b.Bytes()[start:]
+
+		if formatter.TruncateValues {
+			output = truncateLines(output, formatter.lineWidth())
+		}
+
+		if profile := formatter.colorProfile(); profile != nil {
+			output = profile.colorize(output, entry.Level)
+		}
+
+		output = append([]byte(nil), output...)
+		b.Truncate(start)
+		b.Write(output)
+	}
+
+	return b.Bytes(), nil
+}
+
+// formatCompact formats the entry with the JSON encoder appending to the buffer, which
+// avoids building the map of fields and the reflection of encoding/json.
+func (formatter *JSONFormatter) formatCompact(entry *logrus.Entry) ([]byte, error) {
+	b := entry.Buffer
+
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	escapeHTML := !formatter.DisableHTMLEscape
+	timestamp := formatter.timestampFormat()
+
+	var prefix []byte
+
+	if static := formatter.StaticPrefix; static != nil {
+		if static.collides(entry, timestamp) {
+			entry = static.withFields(entry)
+		} else {
+			prefix = static.Bytes()
+		}
+	}
+
+	output, err := appendJSONEntry(b.AvailableBuffer(), prefix, entry, -1, timestamp, escapeHTML)
+
+	if err != nil {
+		return nil, formatError(entry, formatter.Strict, fmt.Errorf("failed to marshal fields to JSON: %v", err))
+	}
+
+	if formatter.EntrySizeThreshold > 0 && len(output) > formatter.EntrySizeThreshold {
+		output, err = appendJSONEntry(output[:0], prefix, entry, len(output), timestamp, escapeHTML)
+
+		if err != nil {
+			return nil, formatError(entry, formatter.Strict, fmt.Errorf("failed to marshal fields to JSON: %v", err))
+		}
+	}
+
+	recordEntrySize(len(output))
+	b.Write(output)
+
+	return b.Bytes(), nil
+}
+
+func (formatter *JSONFormatter) lineWidth() int {
+	if formatter.LineWidth > 0 {
+		return formatter.LineWidth
+	}
+
+	return terminalWidth()
+}
+
+func (formatter *JSONFormatter) colorProfile() *ColorProfile {
+	if formatter.ColorProfile != nil {
+		return formatter.ColorProfile
+	}
+
+	return getEnvColorProfile()
+}
+
+func (formatter *JSONFormatter) encode(b *bytes.Buffer, data logrus.Fields) error {
+	if formatter.Canonical {
+		return formatter.encodeCanonical(b, data)
+	}
+
 	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(!formatter.DisableHTMLEscape)
+
+	if formatter.PrettyPrint {
+		encoder.SetIndent("", "  ")
+	}
 
 	if err := encoder.Encode(data); err != nil {
-		return nil, fmt.Errorf("failed to marshal fields to JSON: %v", err)
+		return fmt.Errorf("failed to marshal fields to JSON: %v", err)
 	}
 
-	return b.Bytes(), nil
+	return nil
+}
+
+// encodeCanonical encodes the data in canonical form. The data is encoded and decoded
+// once to turn every struct into a map, whose keys are always sorted by encoding/json.
+func (formatter *JSONFormatter) encodeCanonical(b *bytes.Buffer, data interface{}) error {
+	var intermediate bytes.Buffer
+
+	encoder := json.NewEncoder(&intermediate)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to marshal fields to JSON: %v", err)
+	}
+
+	var generic interface{}
+
+	decoder := json.NewDecoder(&intermediate)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&generic); err != nil {
+		return fmt.Errorf("failed to canonicalize JSON: %v", err)
+	}
+
+	encoder = json.NewEncoder(b)
+	encoder.SetEscapeHTML(false)
+
+	if formatter.PrettyPrint {
+		encoder.SetIndent("", "  ")
+	}
+
+	if err := encoder.Encode(generic); err != nil {
+		return fmt.Errorf("failed to marshal fields to JSON: %v", err)
+	}
+
+	return nil
 }
@@ -2,6 +2,7 @@ package glogger
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,4 +40,156 @@ func TestJsonFormatter(t *testing.T) {
 		assert.Assert(t, err == nil, "Error is nil")
 		assert.Equal(t, actualResult, expected)
 	})
+	t.Run("Canonical output has sorted keys and no HTML escaping", func(t *testing.T) {
+		now := time.Now()
+		entry := logrus.Entry{
+			Level:   logrus.InfoLevel,
+			Time:    now,
+			Message: "Completed Request",
+			Data: logrus.Fields{
+				"host": Host{IP: "192.0.2.1", Hostname: "localhost"},
+				"http": HTTP{Request: &Request{Path: "/users?a=1&b=<2>", Method: "GET"}},
+			},
+		}
+
+		formatter := JSONFormatter{Canonical: true}
+
+		first, err := formatter.Format(&entry)
+		assert.Assert(t, err == nil, "Error is nil")
+
+		second, _ := formatter.Format(&entry)
+
+		expected := fmt.Sprintf("{\"host\":{\"hostname\":\"localhost\",\"ip\":\"192.0.2.1\"},\"http\":{\"request\":{\"method\":\"GET\",\"path\":\"/users?a=1&b=<2>\"}},\"level\":\"info\",\"message\":\"Completed Request\",\"time\":%d}\n", now.Unix())
+
+		assert.Equal(t, string(first), expected)
+		assert.Equal(t, string(first), string(second))
+	})
+	t.Run("HTML escaping can be disabled", func(t *testing.T) {
+		entry := logrus.Entry{
+			Level:   logrus.InfoLevel,
+			Message: "/users?a=1&b=<2>",
+		}
+
+		escaped, _ := (&JSONFormatter{}).Format(&entry)
+		unescaped, err := (&JSONFormatter{DisableHTMLEscape: true}).Format(&entry)
+
+		assert.Assert(t, err == nil, "Error is nil")
+		assert.Assert(t, strings.Contains(string(escaped), `/users?a=1\u0026b=\u003c2\u003e`), "Unexpected unescaped message")
+		assert.Assert(t, strings.Contains(string(unescaped), `/users?a=1&b=<2>`), "Unexpected escaped message")
+	})
+	t.Run("Pretty print indents the output", func(t *testing.T) {
+		now := time.Now()
+		entry := logrus.Entry{
+			Level:   logrus.InfoLevel,
+			Time:    now,
+			Message: "Incoming Request",
+			Data: logrus.Fields{
+				"host": Host{Hostname: "localhost"},
+			},
+		}
+
+		data, err := (&JSONFormatter{PrettyPrint: true}).Format(&entry)
+
+		expected := fmt.Sprintf("{\n  \"host\": {\n    \"hostname\": \"localhost\"\n  },\n  \"level\": \"info\",\n  \"message\": \"Incoming Request\",\n  \"time\": %d\n}\n", now.Unix())
+
+		assert.Assert(t, err == nil, "Error is nil")
+		assert.Equal(t, string(data), expected)
+	})
+}
+
+func TestJSONFormatterTimeZone(t *testing.T) {
+	paris := time.FixedZone("Europe/Paris", 3600)
+
+	entry := &logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Time:    time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Message: "Audited",
+	}
+
+	cases := []struct {
+		name      string
+		formatter JSONFormatter
+		expected  string
+	}{
+		{
+			name:      "Time is formatted in UTC by default",
+			formatter: JSONFormatter{TimestampFormat: time.RFC3339},
+			expected:  `{"level":"info","message":"Audited","time":"2024-01-02T15:04:05Z"}`,
+		},
+		{
+			name:      "Time is formatted in the location with the tz field",
+			formatter: JSONFormatter{TimestampFormat: time.RFC3339, Location: paris, TimeZoneField: true},
+			expected:  `{"level":"info","message":"Audited","time":"2024-01-02T16:04:05+01:00","tz":"Europe/Paris"}`,
+		},
+		{
+			name:      "Unix time is kept by default",
+			formatter: JSONFormatter{TimeZoneField: true},
+			expected:  `{"level":"info","message":"Audited","time":1704207845,"tz":"UTC"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compact, err := c.formatter.Format(entry)
+			assert.NilError(t, err)
+			assert.Equal(t, string(compact), c.expected+"\n")
+
+			c.formatter.Canonical = true
+			canonical, err := c.formatter.Format(entry)
+			assert.NilError(t, err)
+			assert.Equal(t, string(canonical), c.expected+"\n")
+		})
+	}
+}
+
+func TestJSONFormatterTimestampFormat(t *testing.T) {
+	entry := &logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Time:    time.Date(2024, 1, 2, 15, 4, 5, 123456789, time.UTC),
+		Message: "Formatted",
+	}
+
+	cases := []struct {
+		name      string
+		formatter JSONFormatter
+		expected  string
+	}{
+		{
+			name:      "Unix milliseconds",
+			formatter: JSONFormatter{TimestampFormat: TimestampUnixMillis},
+			expected:  `{"level":"info","message":"Formatted","time":1704207845123}`,
+		},
+		{
+			name:      "Unix nanoseconds",
+			formatter: JSONFormatter{TimestampFormat: TimestampUnixNanos},
+			expected:  `{"level":"info","message":"Formatted","time":1704207845123456789}`,
+		},
+		{
+			name:      "RFC 3339 with nanoseconds",
+			formatter: JSONFormatter{TimestampFormat: time.RFC3339Nano},
+			expected:  `{"level":"info","message":"Formatted","time":"2024-01-02T15:04:05.123456789Z"}`,
+		},
+		{
+			name:      "Custom layout and time field",
+			formatter: JSONFormatter{TimestampFormat: "2006-01-02 15:04:05.000", TimeField: "@timestamp"},
+			expected:  `{"@timestamp":"2024-01-02 15:04:05.123","level":"info","message":"Formatted"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compact, err := c.formatter.Format(entry)
+			assert.NilError(t, err)
+			assert.Equal(t, string(compact), c.expected+"\n")
+
+			c.formatter.Canonical = true
+			canonical, err := c.formatter.Format(entry)
+			assert.NilError(t, err)
+			assert.Equal(t, string(canonical), c.expected+"\n")
+		})
+	}
+
+	aliased, err := (&JSONFormatter{TimestampFormat: TimestampUnixMillis, TimeField: "@timestamp", CompatAliases: map[string]string{"@timestamp": "time"}}).Format(entry)
+	assert.NilError(t, err)
+	assert.Equal(t, string(aliased), `{"@timestamp":1704207845123,"level":"info","message":"Formatted","time":1704207845123}`+"\n")
 }
@@ -0,0 +1,69 @@
+package glogger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+)
+
+// keyedHashSize is the size of the keyed hashes logged, 128 bits.
+const keyedHashSize = 16
+
+// KeyedHash hashes the values with a secret key, such as an HMAC computed locally or by a
+// key management service holding the key. The regulated deployments can select a
+// FIPS-approved algorithm, or keep the key out of the process with a KMS.
+type KeyedHash func(value []byte) ([]byte, error)
+
+// HMAC returns the KeyedHash computing the HMAC of the values with the hash function, such
+// as sha256.New, the default of HashIP and HashSessionID, or sha512.New384.
+func HMAC(h func() hash.Hash, key []byte) KeyedHash {
+	return func(value []byte) ([]byte, error) {
+		mac := hmac.New(h, key)
+		mac.Write(value)
+
+		return mac.Sum(nil), nil
+	}
+}
+
+// HashIPWith is like HashIP, with the keyed hash. The addresses whose hash fails are
+// logged masked.
+func HashIPWith(keyed KeyedHash) func(ip string) string {
+	return func(ip string) string {
+		return keyed.hexSum(NormalizeIP(ip))
+	}
+}
+
+// HashSessionIDWith is like HashSessionID, with the keyed hash. The session IDs whose
+// hash fails are logged masked.
+func HashSessionIDWith(keyed KeyedHash, sessionID SessionIDFunc) SessionIDFunc {
+	return func(r *http.Request) string {
+		if id := sessionID(r); id != "" {
+			return keyed.hexSum(id)
+		}
+
+		return ""
+	}
+}
+
+// hexSum returns the hex encoded hash of the value, truncated to 128 bits. The value is
+// never logged in clear: it is masked when the hash fails, such as when the KMS is
+// unavailable.
+func (keyed KeyedHash) hexSum(value string) string {
+	sum, err := keyed([]byte(value))
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash a logged value, %v\n", err)
+		return defaultRedactionMask
+	}
+
+	return hex.EncodeToString(sum[:min(len(sum), keyedHashSize)])
+}
+
+// keyedHash returns the hex encoded HMAC-SHA256 of the value, truncated to 128 bits.
+func keyedHash(key string, value string) string {
+	return HMAC(sha256.New, []byte(key)).hexSum(value)
+}
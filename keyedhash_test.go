@@ -0,0 +1,45 @@
+package glogger
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestKeyedHash(t *testing.T) {
+	t.Run("HMAC with a FIPS-approved hash function", func(t *testing.T) {
+		mac := hmac.New(sha512.New384, []byte("key"))
+		mac.Write([]byte("192.0.2.1"))
+		expected := hex.EncodeToString(mac.Sum(nil)[:16])
+
+		assert.Equal(t, HashIPWith(HMAC(sha512.New384, []byte("key")))("::ffff:192.0.2.1"), expected)
+	})
+
+	t.Run("Key management service", func(t *testing.T) {
+		var hashed []string
+
+		kms := KeyedHash(func(value []byte) ([]byte, error) {
+			hashed = append(hashed, string(value))
+			return []byte{0xca, 0xfe}, nil
+		})
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("X-Session-Id", "session")
+
+		assert.Equal(t, HashSessionIDWith(kms, SessionHeader("X-Session-Id"))(request), "cafe")
+		assert.DeepEqual(t, hashed, []string{"session"})
+	})
+
+	t.Run("Values are masked when the hash fails", func(t *testing.T) {
+		unavailable := KeyedHash(func(value []byte) ([]byte, error) {
+			return nil, errors.New("kms unavailable")
+		})
+
+		assert.Equal(t, HashIPWith(unavailable)("192.0.2.1"), "[REDACTED]")
+	})
+}
@@ -0,0 +1,138 @@
+package glogger
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// KeyRing holds the keys of the keyed hashes, identified by IDs, so that the keys can be
+// rotated: the active key hashes the values, logged prefixed by its ID such as
+// "2024-06:3f2a…", and the previous keys are kept to match the values hashed before the
+// rotation until they are retired.
+type KeyRing struct {
+	hash   func() hash.Hash
+	mutex  sync.RWMutex
+	keys   map[string][]byte
+	active string
+}
+
+// NewKeyRing returns a KeyRing computing the HMAC of the values with the hash function,
+// such as sha256.New, and the key as the active key.
+func NewKeyRing(h func() hash.Hash, id string, key []byte) (*KeyRing, error) {
+	ring := &KeyRing{hash: h, keys: map[string][]byte{}}
+
+	if err := ring.Rotate(id, key); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+// Rotate adds the key and makes it the active key. The previous keys are kept.
+func (ring *KeyRing) Rotate(id string, key []byte) error {
+	if id == "" || strings.Contains(id, ":") {
+		return fmt.Errorf("keyring: invalid key ID %q", id)
+	}
+
+	if len(key) == 0 {
+		return errors.New("keyring: empty key")
+	}
+
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	if _, ok := ring.keys[id]; ok {
+		return fmt.Errorf("keyring: duplicate key ID %q", id)
+	}
+
+	ring.keys[id] = append([]byte(nil), key...)
+	ring.active = id
+
+	return nil
+}
+
+// Retire removes a previous key, once the values hashed with it are no longer needed.
+func (ring *KeyRing) Retire(id string) error {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+
+	if id == ring.active {
+		return fmt.Errorf("keyring: key %q is active", id)
+	}
+
+	if _, ok := ring.keys[id]; !ok {
+		return fmt.Errorf("keyring: unknown key %q", id)
+	}
+
+	delete(ring.keys, id)
+
+	return nil
+}
+
+// ActiveKeyID returns the ID of the active key.
+func (ring *KeyRing) ActiveKeyID() string {
+	ring.mutex.RLock()
+	defer ring.mutex.RUnlock()
+
+	return ring.active
+}
+
+// Hash returns the hash of the value with the active key, prefixed by the key ID.
+func (ring *KeyRing) Hash(value string) string {
+	ring.mutex.RLock()
+	id, key := ring.active, ring.keys[ring.active]
+	ring.mutex.RUnlock()
+
+	return id + ":" + HMAC(ring.hash, key).hexSum(value)
+}
+
+// Match reports whether the hashed value, as logged, is the hash of the value with one of
+// the keys of the ring, such as to find the entries of a client whose IP is known.
+func (ring *KeyRing) Match(value string, hashed string) bool {
+	id, sum, ok := strings.Cut(hashed, ":")
+
+	if !ok {
+		return false
+	}
+
+	ring.mutex.RLock()
+	key, ok := ring.keys[id]
+	ring.mutex.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	expected, err := hex.DecodeString(sum)
+
+	if err != nil {
+		return false
+	}
+
+	actual, _ := HMAC(ring.hash, key)([]byte(value))
+
+	return hmac.Equal(actual[:min(len(actual), keyedHashSize)], expected)
+}
+
+// HashIP is like HashIP with the keys of the ring. The IP addresses are normalized before
+// they are hashed, match them with Match(NormalizeIP(ip), hashed).
+func (ring *KeyRing) HashIP(ip string) string {
+	return ring.Hash(NormalizeIP(ip))
+}
+
+// HashSessionID is like HashSessionID with the keys of the ring.
+func (ring *KeyRing) HashSessionID(sessionID SessionIDFunc) SessionIDFunc {
+	return func(r *http.Request) string {
+		if id := sessionID(r); id != "" {
+			return ring.Hash(id)
+		}
+
+		return ""
+	}
+}
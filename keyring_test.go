@@ -0,0 +1,40 @@
+package glogger
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestKeyRing(t *testing.T) {
+	ring, err := NewKeyRing(sha256.New, "2024-01", []byte("first key"))
+	assert.NilError(t, err)
+
+	before := ring.HashIP("::ffff:192.0.2.1")
+	assert.Assert(t, strings.HasPrefix(before, "2024-01:"), before)
+	assert.Equal(t, before, "2024-01:"+keyedHash("first key", "192.0.2.1"))
+
+	assert.NilError(t, ring.Rotate("2024-02", []byte("second key")))
+	assert.Equal(t, ring.ActiveKeyID(), "2024-02")
+
+	after := ring.HashIP("192.0.2.1")
+	assert.Assert(t, strings.HasPrefix(after, "2024-02:"), after)
+
+	// The values hashed before the rotation still match.
+	assert.Assert(t, ring.Match("192.0.2.1", before))
+	assert.Assert(t, ring.Match("192.0.2.1", after))
+	assert.Assert(t, !ring.Match("192.0.2.2", after))
+	assert.Assert(t, !ring.Match("192.0.2.1", "unknown:"+strings.TrimPrefix(after, "2024-02:")))
+
+	assert.NilError(t, ring.Retire("2024-01"))
+	assert.Assert(t, !ring.Match("192.0.2.1", before))
+
+	assert.ErrorContains(t, ring.Retire("2024-02"), "is active")
+	assert.ErrorContains(t, ring.Rotate("2024-02", []byte("key")), "duplicate key ID")
+	assert.ErrorContains(t, ring.Rotate("a:b", []byte("key")), "invalid key ID")
+
+	_, err = NewKeyRing(sha256.New, "empty", nil)
+	assert.ErrorContains(t, err, "empty key")
+}
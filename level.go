@@ -0,0 +1,105 @@
+package glogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LevelEnv is the default environment variable read to reload the level on SIGHUP.
+const LevelEnv = "GLOGGER_LEVEL"
+
+// levelBody is the body of the level handler requests and responses.
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// SetLevel parses and sets the level of the logger. It is safe for concurrent use.
+func SetLevel(logger *logrus.Logger, level string) error {
+	parsed, err := logrus.ParseLevel(level)
+
+	if err != nil {
+		return err
+	}
+
+	logger.SetLevel(parsed)
+
+	return nil
+}
+
+// LevelHandler returns an http.Handler to read the level of the logger with GET and to
+// change it with PUT. Both use a JSON body like {"level":"debug"}.
+func LevelHandler(logger *logrus.Logger) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelBody
+
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(rw, "invalid body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if err := SetLevel(logger, body.Level); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			logger.WithField("level", body.Level).Warn("Log level changed")
+		default:
+			rw.Header().Set("Allow", "GET, PUT")
+			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		rw.Header().Set(contentTypeKey, "application/json")
+		json.NewEncoder(rw).Encode(levelBody{Level: logger.GetLevel().String()})
+	})
+}
+
+// ReloadLevelOnSignal sets the level of the logger from the env environment variable,
+// LevelEnv if empty, every time the process receives SIGHUP, on the platforms supporting
+// it. Invalid or empty levels are logged and ignored. The returned function stops the
+// reload.
+func ReloadLevelOnSignal(logger *logrus.Logger, env string) (stop func()) {
+	if env == "" {
+		env = LevelEnv
+	}
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	if len(reloadSignals) > 0 {
+		signal.Notify(signals, reloadSignals...)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				reloadLevel(logger, env)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+func reloadLevel(logger *logrus.Logger, env string) {
+	level := os.Getenv(env)
+
+	if err := SetLevel(logger, level); err != nil {
+		logger.WithError(err).WithField("env", env).Error("Failed to reload log level")
+		return
+	}
+
+	logger.WithField("level", level).Warn("Log level changed")
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package glogger
+
+import "os"
+
+// reloadSignals is empty: SIGHUP is only supported on Unix.
+var reloadSignals []os.Signal
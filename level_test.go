@@ -0,0 +1,78 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLevelHandler(t *testing.T) {
+	t.Run("GET returns the current level", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		logger.SetLevel(logrus.WarnLevel)
+		recorder := httptest.NewRecorder()
+
+		LevelHandler(logger).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/level", nil))
+
+		assert.Equal(t, recorder.Code, http.StatusOK)
+		assert.Equal(t, recorder.Body.String(), "{\"level\":\"warning\"}\n")
+	})
+
+	t.Run("PUT changes the level", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		recorder := httptest.NewRecorder()
+
+		LevelHandler(logger).ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"level":"debug"}`)))
+
+		assert.Equal(t, recorder.Code, http.StatusOK)
+		assert.Equal(t, recorder.Body.String(), "{\"level\":\"debug\"}\n")
+		assert.Equal(t, logger.GetLevel(), logrus.DebugLevel)
+		assert.Equal(t, hook.LastEntry().Message, "Log level changed")
+	})
+
+	t.Run("PUT with an invalid level is rejected", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		recorder := httptest.NewRecorder()
+
+		LevelHandler(logger).ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"level":"loud"}`)))
+
+		assert.Equal(t, recorder.Code, http.StatusBadRequest)
+		assert.Equal(t, logger.GetLevel(), logrus.InfoLevel)
+	})
+
+	t.Run("Other methods are not allowed", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		recorder := httptest.NewRecorder()
+
+		LevelHandler(logger).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/level", nil))
+
+		assert.Equal(t, recorder.Code, http.StatusMethodNotAllowed)
+		assert.Equal(t, recorder.Header().Get("Allow"), "GET, PUT")
+	})
+}
+
+func TestReloadLevel(t *testing.T) {
+	t.Run("Level is read from the environment", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		t.Setenv(LevelEnv, "trace")
+
+		reloadLevel(logger, LevelEnv)
+
+		assert.Equal(t, logger.GetLevel(), logrus.TraceLevel)
+	})
+
+	t.Run("Invalid level is ignored", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		t.Setenv(LevelEnv, "")
+
+		reloadLevel(logger, LevelEnv)
+
+		assert.Equal(t, logger.GetLevel(), logrus.InfoLevel)
+		assert.Equal(t, hook.LastEntry().Message, "Failed to reload log level")
+	})
+}
@@ -0,0 +1,24 @@
+package glogger
+
+import (
+	"net"
+	"net/http"
+)
+
+const listenerKey = "listener"
+
+// listener returns the label of the listener which accepted the request: the label of its
+// local port in ListenerByPort, or Listener.
+func (options MiddlewareOptions) listener(r *http.Request) string {
+	if len(options.ListenerByPort) > 0 {
+		if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+			if _, port, err := net.SplitHostPort(addr.String()); err == nil {
+				if label, ok := options.ListenerByPort[port]; ok {
+					return label
+				}
+			}
+		}
+	}
+
+	return options.Listener
+}
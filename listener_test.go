@@ -0,0 +1,64 @@
+package glogger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestListener(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   MiddlewareOptions
+		localAddr net.Addr
+		expected  interface{}
+	}{
+		{
+			name:     "Listener is logged",
+			options:  MiddlewareOptions{Listener: "public"},
+			expected: "public",
+		},
+		{
+			name:      "Label of the local port takes precedence",
+			options:   MiddlewareOptions{Listener: "public", ListenerByPort: map[string]string{"9090": "admin"}},
+			localAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9090},
+			expected:  "admin",
+		},
+		{
+			name:      "Listener is logged for unmapped ports",
+			options:   MiddlewareOptions{Listener: "public", ListenerByPort: map[string]string{"9090": "admin"}},
+			localAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8080},
+			expected:  "public",
+		},
+		{
+			name:     "Listener is not logged by default",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+
+			var handlerListener interface{}
+			handler := LoggingMiddlewareWithOptions(logger, tt.options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				handlerListener = Get(r.Context()).Data["listener"]
+			}))
+			request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+
+			if tt.localAddr != nil {
+				request = request.WithContext(context.WithValue(request.Context(), http.LocalAddrContextKey, tt.localAddr))
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			assert.Equal(t, handlerListener, tt.expected)
+			assert.Equal(t, hook.LastEntry().Data["listener"], tt.expected)
+		})
+	}
+}
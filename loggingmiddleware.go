@@ -1,38 +1,73 @@
 package glogger
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
-	"strings"
+	"path"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	correlationIDKey = "X-Request-Id"
-	contentTypeKey   = "Content-Type"
-	userAgentKey     = "user-agent"
-	forwardedHostKey = "X-Forwarded-Host"
-	forwardedForKey  = "X-Forwarded-For"
+	correlationIDKey   = "X-Request-Id"
+	contentTypeKey     = "Content-Type"
+	userAgentKey       = "user-agent"
+	forwardedHostKey   = "X-Forwarded-Host"
+	forwardedForKey    = "X-Forwarded-For"
+	ifNoneMatchKey     = "If-None-Match"
+	ifModifiedSinceKey = "If-Modified-Since"
+	etagKey            = "ETag"
+	cacheControlKey    = "Cache-Control"
+	upgradeKey         = "Upgrade"
 )
 
+const identityKey = "identity"
+
 // Request struct contains items of request info log.
 type Request struct {
-	Path        string `json:"path,omitempty"`
-	Method      string `json:"method,omitempty"`
-	Query       string `json:"query,omitempty"`
-	ContentType string `json:"content-type,omitempty"`
-	Scheme      string `json:"scheme,omitempty"`
-	Protocol    string `json:"protocol,omitempty"`
-	UserAgent   string `json:"userAgent,omitempty"`
+	Path            string            `json:"path,omitempty"`
+	Route           string            `json:"route,omitempty"`
+	Method          string            `json:"method,omitempty"`
+	Query           string            `json:"query,omitempty"`
+	ContentType     string            `json:"content-type,omitempty"`
+	Scheme          string            `json:"scheme,omitempty"`
+	Protocol        string            `json:"protocol,omitempty"`
+	UserAgent       string            `json:"userAgent,omitempty"`
+	Parts           []Part            `json:"parts,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	BodyTruncated   bool              `json:"bodyTruncated,omitempty"`
+	IfNoneMatch     string            `json:"ifNoneMatch,omitempty"`
+	IfModifiedSince string            `json:"ifModifiedSince,omitempty"`
+	Range           *Range            `json:"range,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	ReceivedAt      string            `json:"receivedAt,omitempty"`
+	BodySHA256      string            `json:"bodySha256,omitempty"`
 }
 
 // Response struct contains items of response info log.
 type Response struct {
-	StatusCode   int     `json:"statusCode,omitempty"`
-	ResponseTime float64 `json:"responseTime,omitempty"`
+	StatusCode       int               `json:"statusCode,omitempty"`
+	ResponseTime     float64           `json:"responseTime,omitempty"`
+	Bytes            int               `json:"bytes,omitempty"`
+	ContentType      string            `json:"content-type,omitempty"`
+	Body             string            `json:"body,omitempty"`
+	BodyTruncated    bool              `json:"bodyTruncated,omitempty"`
+	ETag             string            `json:"etag,omitempty"`
+	CacheControl     string            `json:"cacheControl,omitempty"`
+	NotModified      bool              `json:"notModified,omitempty"`
+	ContentRange     *ContentRange     `json:"contentRange,omitempty"`
+	Partial          bool              `json:"partial,omitempty"`
+	SecurityHeaders  []string          `json:"securityHeaders,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	Hijacked         bool              `json:"hijacked,omitempty"`
+	Upgrade          string            `json:"upgrade,omitempty"`
+	StatusNotWritten bool              `json:"statusNotWritten,omitempty"`
+	Aborted          bool              `json:"aborted,omitempty"`
+	ContextError     string            `json:"contextError,omitempty"`
 }
 
 // Host struct contains items of host info log.
@@ -48,92 +83,707 @@ type HTTP struct {
 	Response *Response `json:"response,omitempty"`
 }
 
-func getCorrelationID(header http.Header) string {
-	if correlationID := header.Get(correlationIDKey); correlationID != "" {
-		return correlationID
+func removePort(host string) string {
+	host, _ = SplitHostPort(host)
+	return host
+}
+
+func getIP(request *http.Request) string {
+	if result := getProxySourceIP(request); result != "" {
+		return NormalizeIP(result)
+	}
+
+	if result := request.Header.Get(forwardedForKey); result != "" {
+		return normalizeIPList(result)
+	}
+
+	if _, ok := getUnixSocket(request); ok {
+		return ""
+	}
+
+	return NormalizeIP(removePort(request.RemoteAddr))
+}
+
+func newRequest(r *http.Request) *Request {
+	return &Request{
+		Path:            r.URL.RequestURI(),
+		Method:          r.Method,
+		ContentType:     r.Header.Get(contentTypeKey),
+		UserAgent:       r.Header.Get(userAgentKey),
+		Query:           r.URL.RawQuery,
+		Scheme:          getScheme(r),
+		Protocol:        r.Proto,
+		IfNoneMatch:     r.Header.Get(ifNoneMatchKey),
+		IfModifiedSince: r.Header.Get(ifModifiedSinceKey),
+		Range:           parseRange(r.Header.Get(rangeKey)),
+	}
+}
+
+func newHost(r *http.Request) Host {
+	if socket, ok := getUnixSocket(r); ok {
+		return Host{
+			Hostname:          socket,
+			ForwardedHostname: r.Header.Get(forwardedHostKey),
+			IP:                getIP(r),
+		}
+	}
+
+	return Host{
+		Hostname:          removePort(r.Host),
+		ForwardedHostname: r.Header.Get(forwardedHostKey),
+		IP:                getIP(r),
+	}
+}
+
+// MiddlewareOptions is the struct of options to configure the logging middleware
+type MiddlewareOptions struct {
+	// StreamProgressInterval is the minimum interval between two progress
+	// entries of a server-sent events stream. Defaults to 30 seconds.
+	StreamProgressInterval time.Duration
+	// LogConnections logs the hijacked connections, such as the websockets, from the
+	// upgrade to their closing: a "Connection established" entry when the handler hijacks
+	// the connection, "Connection heartbeat" entries every ConnectionHeartbeatInterval
+	// with the bytes transferred so far, and a "Connection closed" entry with the
+	// duration and close reason when the handler closes it.
+	LogConnections bool
+	// ConnectionHeartbeatInterval is the interval between two heartbeat entries of a
+	// hijacked connection. Defaults to 30 seconds.
+	ConnectionHeartbeatInterval time.Duration
+	// LogMultipartParts enables logging of the parts metadata (field name, file name,
+	// content type and size) of multipart/form-data requests. Parts content is never logged.
+	LogMultipartParts bool
+	// ReverseDNS enables the enrichment of the completed request entry with the
+	// client domain name, resolved in background from the client IP. The forwarded
+	// client IP is only resolved through the TrustedProxies, so that the clients can't
+	// pick the names looked up.
+	ReverseDNS *ReverseDNS
+	// Redaction masks sensitive query parameters and headers before they are logged.
+	Redaction RedactionOptions
+	// TraceContext adds the traceId and spanId fields to the request scoped logger, so that
+	// every entry produced by Get can be joined with the traces. The IDs are taken from
+	// the span returned by SpanContext, if any, or from the W3C traceparent header.
+	TraceContext bool
+	// SpanContext returns the IDs of the span active in the request context.
+	SpanContext SpanContextFunc
+	// ExcludedPaths are the path patterns (as in path.Match, e.g. "/-/*") of the requests
+	// which are not logged, such as health checks and metrics scraping.
+	ExcludedPaths []string
+	// Skip returns true for the requests which are not logged.
+	Skip func(r *http.Request) bool
+	// LevelByStatus returns the level of the completed request entry for the response
+	// status code. Defaults to DefaultLevelByStatus.
+	LevelByStatus func(status int) logrus.Level
+	// BodyCapture enables the capture of the request and response bodies, up to a
+	// maximum size and for the allowed content types only. The Redaction value
+	// patterns are applied to the captured bodies.
+	BodyCapture BodyCaptureOptions
+	// BodyHash logs the SHA-256 of the request bodies in http.request.bodySha256, to detect
+	// the duplicate submissions. The body is hashed as the handler reads it, whether it is
+	// captured or not, and the hash is logged only if the handler read it to its end.
+	BodyHash bool
+	// RecoverPanics recovers the panics of the handlers, logging an error entry with the
+	// panic value and stack trace. The response status is set to 500 if the handler did
+	// not write it, and the completed request entry is logged as usual.
+	RecoverPanics bool
+	// RoutePattern returns the route pattern matched by the request, such as /users/{id},
+	// logged in the completed request entry to aggregate the requests by endpoint. It is
+	// called once the handler returned, and before the handler to resolve the owner of
+	// the request with the RouteOwnership. Defaults to MuxRoutePattern.
+	RoutePattern func(r *http.Request) string
+	// Sampling drops a fraction of the request entries under high traffic, always keeping
+	// the completed request entries at warning level or above. The sampled entries have
+	// a sampling field to rescale the counts.
+	Sampling SamplingOptions
+	// Metrics records the metrics of the requests, such as the Prometheus metrics of
+	// prometheuslogger, which are not sampled. The excluded requests are not recorded.
+	Metrics MetricsRecorder
+	// TrustedProxies enables the validation of the forwarding headers: the client IP is
+	// taken from the Forwarded, X-Forwarded-For or X-Real-IP headers, and the forwarded
+	// host from X-Forwarded-Host or Forwarded, only for the requests received from the
+	// trusted proxies. By default the headers are logged as received.
+	TrustedProxies TrustedProxies
+	// AnonymizeIP anonymizes each client and forwarded IP address of host.ip before the
+	// entries are logged, such as TruncateIP or HashIP. The reverse DNS lookup, if enabled,
+	// still uses the original address.
+	AnonymizeIP func(ip string) string
+	// WireDump logs the request and response lines and headers, as sent on the wire, in
+	// "Request dump" and "Response dump" entries at trace level. It is meant for
+	// development and has no cost unless the trace level is enabled. The Redaction
+	// options are applied to the dumps.
+	WireDump bool
+	// SecurityHeaders are the names of the security response headers, such as
+	// DefaultSecurityHeaders, whose presence is logged in the completed request entry,
+	// to audit the coverage of the security headers per route.
+	SecurityHeaders []string
+	// RequestIDHeaders are the names of the request headers holding the request ID, such
+	// as "X-Correlation-Id", in order of preference. The "traceparent" name takes the
+	// trace ID of the W3C traceparent header. A new ID is generated by RequestIDGenerator
+	// when none of the headers is present. Defaults to X-Request-Id.
+	RequestIDHeaders []string
+	// RequestIDGenerator generates the request IDs, such as UUIDv7, ULID or KSUID for IDs
+	// ordered by time. Defaults to UUIDv4.
+	RequestIDGenerator IDGenerator
+	// SessionID returns the session ID of the request, such as SessionCookie or
+	// SessionHeader, added as session.id to every entry of the request to reconstruct
+	// the user sessions across requests. Session cookies are often credentials: use
+	// HashSessionID to log a hash instead.
+	SessionID SessionIDFunc
+	// AbuseDetectors are run on each request which is not excluded. Their signals are
+	// added as abuse.signals to the entries of the request, and passed to OnAbuse.
+	AbuseDetectors []AbuseDetector
+	// OnAbuse is called with the abuse signals of the requests having any, before the
+	// handler, e.g. to ban the client IP.
+	OnAbuse AbuseFunc
+	// HoneypotPaths are the path patterns (as in path.Match) of honeypot endpoints, which
+	// no legitimate client requests. Their completed request entries are logged at warning
+	// level at least, with the request headers and an event.kind "honeypot" field,
+	// regardless of the sampling and the exclusions. The headers are those of
+	// CaptureRequestHeaders if set, or all of them, the credential headers excepted and
+	// the Redaction options applied.
+	HoneypotPaths []string
+	// RouteOwnership adds the owner team and cost center of the request, if any, to every
+	// entry of the request, for the chargeback of the log volume and traffic per team.
+	// The completed request entry also has the service tier of the route.
+	RouteOwnership *RouteOwnership
+	// SlowRequestThreshold, when greater than zero, logs the completed request entries of
+	// the requests slower than the threshold at warning level at least, with a slow field.
+	// Streams are not considered.
+	SlowRequestThreshold time.Duration
+	// SlowRequestEntry also logs a separate "Slow request detected" warning entry for
+	// the slow requests, before the completed request entry.
+	SlowRequestEntry bool
+	// CaptureRequestHeaders are the names of the request headers logged in the
+	// http.request.headers of the completed request entry, such as "Accept". The
+	// Authorization, Proxy-Authorization and Cookie headers are never captured, and the
+	// Redaction options are applied.
+	CaptureRequestHeaders []string
+	// CaptureResponseHeaders are the names of the response headers logged in the
+	// http.response.headers of the completed request entry. The Set-Cookie header is
+	// never captured, and the Redaction options are applied.
+	CaptureResponseHeaders []string
+	// AuditLogger is the audit logger of the events recorded with Audit by the handlers.
+	AuditLogger *AuditLogger
+	// IdempotencyWindow is the duration for which the Idempotency-Key headers are
+	// remembered, to count the retries with the same key in idempotency.attempt, along
+	// with the request ID of the first attempt. The idempotency key is logged anyway.
+	IdempotencyWindow time.Duration
+	// IdentityExtractor returns the identity of the authenticated caller, such as
+	// {"userId": "42", "tenant": "acme"}, added to the completed request entry in the
+	// identity field. It is called once the handler returned, with the request passed
+	// to the handler, so that it reads the context values set by the authentication
+	// middlewares running before the logging middleware.
+	IdentityExtractor func(r *http.Request) map[string]interface{}
+	// Backpressure sets a response header and sheds the low-priority requests while the
+	// log pipeline is saturated. The shed requests are logged with the 503 status.
+	Backpressure BackpressureOptions
+	// IncomingRequestMessage is the message of the incoming request entries. Defaults to
+	// "Incoming Request".
+	IncomingRequestMessage string
+	// CompletedRequestMessage is the message of the completed request entries. Defaults
+	// to "Completed Request".
+	CompletedRequestMessage string
+	// Timing adds the timing field to the completed request entries, with the wall clock
+	// time at which the request was received and the duration in nanoseconds. Like
+	// http.response.responseTime, the duration is measured with the monotonic clock.
+	// The segments recorded with RecordTiming are added to the field. The start of the
+	// field replaces the http.request.receivedAt of the completed request entries.
+	Timing bool
+	// ServerTiming sets the Server-Timing response header, so that the browsers and the
+	// upstream gateways see the breakdown logged: the duration of the handler until the
+	// header is written, as app, followed by the segments recorded with RecordTiming by
+	// then, in milliseconds.
+	ServerTiming bool
+	// Listener is the label of the listener serving the requests, such as "public",
+	// "admin" or "metrics", added as listener to every entry of the request, to separate
+	// the internal traffic from the customer traffic of servers listening on several
+	// ports.
+	Listener string
+	// ListenerByPort maps the local ports of the listeners, such as "9090", to their
+	// labels, for a handler served by several listeners. The label of the port of the
+	// request takes precedence over Listener.
+	ListenerByPort map[string]string
+}
+
+func (options MiddlewareOptions) newHost(r *http.Request) Host {
+	host := newHost(r)
+
+	if len(options.TrustedProxies) > 0 {
+		host.IP, host.ForwardedHostname = options.TrustedProxies.client(r)
 	}
 
-	correlationID, err := uuid.NewRandom()
+	if options.AnonymizeIP != nil {
+		host.IP = anonymizeIPList(host.IP, options.AnonymizeIP)
+	}
+
+	return host
+}
+
+func (options MiddlewareOptions) getIP(r *http.Request) string {
+	if len(options.TrustedProxies) > 0 {
+		ip, _ := options.TrustedProxies.client(r)
+		return ip
+	}
+
+	return getIP(r)
+}
+
+// peerIP returns the client IP which the client cannot forge: resolved through the
+// trusted proxies or, without trusted proxies, the IP of the peer, given by the PROXY
+// protocol header of the listener or the remote address.
+func (options MiddlewareOptions) peerIP(r *http.Request) string {
+	if len(options.TrustedProxies) > 0 {
+		return options.getIP(r)
+	}
+
+	if result := getProxySourceIP(r); result != "" {
+		return NormalizeIP(result)
+	}
+
+	if _, ok := getUnixSocket(r); ok {
+		return ""
+	}
+
+	return NormalizeIP(removePort(r.RemoteAddr))
+}
+
+func (options MiddlewareOptions) routePattern(r *http.Request) string {
+	if options.RoutePattern != nil {
+		return options.RoutePattern(r)
+	}
+
+	return MuxRoutePattern(r)
+}
+
+// MuxRoutePattern returns the path template of the gorilla/mux route matched by the request.
+func MuxRoutePattern(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+
+	if route == nil {
+		return ""
+	}
+
+	template, err := route.GetPathTemplate()
 
 	if err != nil {
 		return ""
 	}
 
-	return correlationID.String()
+	return template
 }
 
-func removePort(host string) string {
-	return strings.Split(host, ":")[0]
+// DefaultLevelByStatus logs the completed requests at error level for 5xx status
+// codes, at warning level for 4xx status codes and at info level otherwise.
+func DefaultLevelByStatus(status int) logrus.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return logrus.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return logrus.WarnLevel
+	default:
+		return logrus.InfoLevel
+	}
 }
 
-func getIP(request *http.Request) string {
-	result := request.Header.Get(forwardedForKey)
+func (options MiddlewareOptions) levelByStatus(status int) logrus.Level {
+	if options.LevelByStatus != nil {
+		return options.LevelByStatus(status)
+	}
+
+	return DefaultLevelByStatus(status)
+}
 
-	if result == "" {
-		result = request.RemoteAddr
+// requestLogger returns the request scoped logger injected in the request context.
+func (options MiddlewareOptions) requestLogger(logger *logrus.Logger, r *http.Request, requestID string, ownership *OwnershipRule) *logrus.Entry {
+	fields := logrus.Fields{
+		"correlationId": requestID,
 	}
 
-	return result
+	// The trace context is only used by the GCPFormatter, which maps it to the trace
+	// fields, the other formatters would log it as is.
+	if usesGCPFormatter(logger.Formatter) {
+		if traceContext := r.Header.Get(cloudTraceContextHeaderKey); traceContext != "" {
+			fields[cloudTraceContextKey] = traceContext
+		}
+	}
+
+	if options.TraceContext {
+		addTraceFields(fields, r, options.SpanContext)
+	}
+
+	if ownership != nil {
+		fields[ownerKey] = ownership.owner()
+	}
+
+	if options.SessionID != nil {
+		if sessionID := options.SessionID(r); sessionID != "" {
+			fields[sessionKey] = Session{ID: sessionID}
+		}
+	}
+
+	if listener := options.listener(r); listener != "" {
+		fields[listenerKey] = listener
+	}
+
+	return logrus.NewEntry(logger).WithFields(fields)
+}
+
+func (options MiddlewareOptions) incomingRequestMessage() string {
+	if options.IncomingRequestMessage == "" {
+		return "Incoming Request"
+	}
+
+	return options.IncomingRequestMessage
+}
+
+func (options MiddlewareOptions) completedRequestMessage() string {
+	if options.CompletedRequestMessage == "" {
+		return "Completed Request"
+	}
+
+	return options.CompletedRequestMessage
+}
+
+// skip returns true if the incoming and completed entries of the request are suppressed.
+// The request scoped logger is injected in the context anyway.
+func (options MiddlewareOptions) skip(r *http.Request) bool {
+	for _, pattern := range options.ExcludedPaths {
+		if matched, _ := path.Match(pattern, r.URL.Path); matched {
+			return true
+		}
+	}
+
+	return options.Skip != nil && options.Skip(r)
 }
 
 // LoggingMiddleware is a gorilla/mux middleware to log all requests
 // It logs the incoming request and when request is completed.
 func LoggingMiddleware(logger *logrus.Logger) mux.MiddlewareFunc {
+	return LoggingMiddlewareWithOptions(logger, MiddlewareOptions{})
+}
+
+// LoggingMiddlewareWithOptions is like LoggingMiddleware, configured with the provided options.
+//
+// Responses with a text/event-stream content type are logged as streams: a
+// "Stream opened" entry when the handler writes the header, periodic "Stream progress"
+// entries with the events and bytes sent so far, and a "Stream closed" entry with
+// the stream duration and close reason in place of the completed request entry.
+func LoggingMiddlewareWithOptions(logger *logrus.Logger, options MiddlewareOptions) mux.MiddlewareFunc {
+	sampler := options.Sampling.newSampler()
+	idempotencyKeys := newIdempotencyTracker(options.IdempotencyWindow, time.Now)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 			start := time.Now()
+			requestID := options.requestID(r)
+			ctx := withRequestID(withDependencies(withRequestSequence(withFieldsAccumulator(r.Context()))), requestID)
+			var ownership *OwnershipRule
+
+			if options.RouteOwnership != nil {
+				// The owner is resolved once, so that all the entries of the request have
+				// the same, with the route pattern if the router already matched it.
+				ownership = options.RouteOwnership.rule(options.routePattern(r), r.URL.Path)
+			}
+
+			entry := options.requestLogger(logger, r, requestID, ownership)
+
+			if idempotency, ok := idempotencyKeys.idempotency(r, requestID); ok {
+				entry = entry.WithField(idempotencyKey, idempotency)
+			}
+
+			ctx = WithLogger(ctx, entry.WithContext(ctx))
+
+			if options.AuditLogger != nil {
+				ctx = WithAuditLogger(ctx, options.AuditLogger)
+			}
+
+			if requestID != "" {
+				rw.Header().Set(correlationIDKey, requestID)
+			}
+
+			next := options.Backpressure.handler(next, rw, r)
+			honeypot := options.isHoneypot(r)
+
+			if !honeypot && options.skip(r) {
+				next.ServeHTTP(rw, r.WithContext(ctx))
+				return
+			}
+
+			if options.ReverseDNS != nil {
+				options.ReverseDNS.Lookup(options.peerIP(r))
+			}
+
+			if len(options.AbuseDetectors) > 0 {
+				ip := options.peerIP(r)
+
+				if signals := detectAbuse(options.AbuseDetectors, r, ip); len(signals) > 0 {
+					AddFields(ctx, logrus.Fields{abuseKey: Abuse{Signals: signals}})
+
+					if options.OnAbuse != nil {
+						options.OnAbuse(r, ip, signals)
+					}
+				}
+			}
+
+			loggedRequest := options.Redaction.redactRequest(r)
+			sampled, sampling := true, (*Sampling)(nil)
 
-			correlationID := getCorrelationID(r.Header)
-			ctx := WithLogger(r.Context(), logrus.NewEntry(logger).WithFields(logrus.Fields{
-				"correlationId": correlationID,
-			}))
+			if !honeypot {
+				sampled, sampling = sampler.sample(r, options.routePattern)
+			}
+
+			if options.Timing || options.ServerTiming {
+				ctx = withTimingSegments(ctx)
+			}
 
 			writer := readableResponseWriter{writer: rw, statusCode: http.StatusOK}
 
-			Get(ctx).WithFields(logrus.Fields{
-				"http": HTTP{
-					Request: &Request{
-						Path:        r.URL.RequestURI(),
-						Method:      r.Method,
-						ContentType: r.Header.Get(contentTypeKey),
-						UserAgent:   r.Header.Get(userAgentKey),
-						Query:       r.URL.RawQuery,
-						Scheme:      r.URL.Scheme,
-						Protocol:    r.Proto,
-					},
-				},
-				"host": Host{
-					Hostname:          removePort(r.Host),
-					ForwardedHostname: r.Header.Get(forwardedHostKey),
-					IP:                getIP(r),
-				},
-			}).Trace("Incoming Request")
+			if options.ServerTiming {
+				writer.beforeHeader = func(header http.Header) {
+					header.Set(serverTimingKey, serverTiming(time.Since(start), timingSegmentsFromContext(ctx)))
+				}
+			}
+			writer.openStream = func(header http.Header) *eventStream {
+				if !isEventStream(header) {
+					return nil
+				}
 
-			next.ServeHTTP(&writer, r.WithContext(ctx))
+				if !sampled {
+					return newEventStream(nil, options.StreamProgressInterval)
+				}
 
-			Get(ctx).WithFields(logrus.Fields{
-				"http": HTTP{
-					Request: &Request{
-						Path:        r.URL.RequestURI(),
-						Method:      r.Method,
-						ContentType: r.Header.Get(contentTypeKey),
-						UserAgent:   r.Header.Get(userAgentKey),
-						Scheme:      r.URL.Scheme,
-						Query:       r.URL.RawQuery,
-						Protocol:    r.Proto,
+				Get(ctx).WithFields(withSampling(logrus.Fields{
+					"http": HTTP{
+						Request:  newRequest(loggedRequest),
+						Response: &Response{StatusCode: writer.statusCode},
 					},
-					Response: &Response{
-						StatusCode:   writer.statusCode,
-						ResponseTime: float64(time.Since(start).Seconds()),
+					"host": options.newHost(loggedRequest),
+				}, sampling)).Info("Stream opened")
+
+				return newEventStream(Get(ctx), options.StreamProgressInterval)
+			}
+			writer.openCapture = options.BodyCapture.newCapture
+
+			if options.LogConnections && sampled {
+				writer.openConnection = func(conn net.Conn) net.Conn {
+					Get(ctx).WithFields(withSampling(logrus.Fields{
+						"http": HTTP{
+							Request:  newRequest(loggedRequest),
+							Response: &Response{StatusCode: writer.statusCode, Hijacked: true, Upgrade: r.Header.Get(upgradeKey)},
+						},
+						"host": options.newHost(loggedRequest),
+					}, sampling)).Info("Connection established")
+
+					return newLoggedConn(conn, Get(ctx), options.ConnectionHeartbeatInterval)
+				}
+			}
+
+			if sampled {
+				Get(ctx).WithFields(withSampling(logrus.Fields{
+					"http": HTTP{
+						Request: newRequest(loggedRequest),
 					},
+					"host": options.newHost(loggedRequest),
+				}, sampling)).Trace(options.incomingRequestMessage())
+			}
+
+			wireDump := sampled && options.WireDump && logger.IsLevelEnabled(logrus.TraceLevel)
+
+			if wireDump {
+				Get(ctx).WithField("dump", dumpRequest(loggedRequest)).Trace("Request dump")
+			}
+
+			request := r.WithContext(ctx)
+
+			var inspector *multipartInspector
+
+			if options.LogMultipartParts {
+				if inspector = newMultipartInspector(r); inspector != nil {
+					request.Body = inspector
+				}
+			}
+
+			requestCapture := options.BodyCapture.newCapture(r.Header)
+
+			if requestCapture != nil {
+				request.Body = &capturingReader{ReadCloser: request.Body, capture: requestCapture}
+			}
+
+			var bodyHash *hashingReader
+
+			if options.BodyHash && request.Body != nil && request.Body != http.NoBody {
+				bodyHash = newHashingReader(request.Body)
+				request.Body = bodyHash
+			}
+
+			if options.RecoverPanics {
+				if recovered := serveRecovering(next, writer.responseWriter(), request); recovered != nil {
+					Get(ctx).WithFields(logrus.Fields{
+						"panic": recovered,
+						"http": HTTP{
+							Request: newRequest(loggedRequest),
+						},
+						"host": options.newHost(loggedRequest),
+					}).Error("Panic Recovered")
+
+					if !writer.wroteHeader {
+						writer.WriteHeader(http.StatusInternalServerError)
+					}
+				}
+			} else {
+				next.ServeHTTP(writer.responseWriter(), request)
+			}
+
+			// The implicit response of the handlers writing nothing is written once the
+			// middleware returned, with the headers set until then.
+			if !writer.wroteHeader && writer.beforeHeader != nil {
+				writer.beforeHeader(writer.Header())
+			}
+
+			if wireDump {
+				header := options.Redaction.redactHeader(writer.Header())
+				Get(ctx).WithField("dump", dumpResponse(r, writer.statusCode, header)).Trace("Response dump")
+			}
+
+			completedRequest := newRequest(loggedRequest)
+
+			completedRequest.Route = options.routePattern(request)
+
+			// With Timing, the start of the timing field is the time the request was received.
+			if !options.Timing {
+				completedRequest.ReceivedAt = start.UTC().Format(time.RFC3339Nano)
+			}
+
+			if inspector != nil {
+				completedRequest.Parts = inspector.Parts()
+			}
+
+			if requestCapture != nil {
+				completedRequest.Body = options.Redaction.redactValue(requestCapture.buffer.String())
+				completedRequest.BodyTruncated = requestCapture.truncated
+			}
+
+			if bodyHash != nil {
+				completedRequest.BodySHA256 = bodyHash.sum()
+			}
+
+			// time.Since uses the monotonic clock reading of start, unaffected by the wall
+			// clock steps.
+			duration := time.Since(start)
+
+			response := &Response{
+				StatusCode:   writer.statusCode,
+				ResponseTime: duration.Seconds(),
+				Bytes:        writer.Length(),
+				ContentType:  writer.Header().Get(contentTypeKey),
+				ETag:         writer.Header().Get(etagKey),
+				CacheControl: writer.Header().Get(cacheControlKey),
+				NotModified:  writer.statusCode == http.StatusNotModified,
+				ContentRange: parseContentRange(writer.Header().Get(contentRangeKey)),
+				Partial:      writer.statusCode == http.StatusPartialContent,
+			}
+
+			if writer.hijacked {
+				response.Hijacked = true
+				response.Upgrade = r.Header.Get(upgradeKey)
+			}
+
+			if !writer.wroteHeader {
+				response.StatusNotWritten = true
+			}
+
+			// The request context is canceled when the client disconnects, or by the
+			// timeout of an outer middleware.
+			if err := r.Context().Err(); err != nil {
+				response.Aborted = errors.Is(err, context.Canceled)
+				response.ContextError = err.Error()
+			}
+
+			completedRequest.Headers = captureHeaders(options.CaptureRequestHeaders, loggedRequest.Header)
+
+			if len(options.CaptureResponseHeaders) > 0 {
+				response.Headers = captureHeaders(options.CaptureResponseHeaders, options.Redaction.redactHeader(writer.Header()))
+			}
+
+			if len(options.SecurityHeaders) > 0 {
+				response.SecurityHeaders = presentSecurityHeaders(options.SecurityHeaders, writer.Header())
+			}
+
+			if writer.capture != nil {
+				response.Body = options.Redaction.redactValue(writer.capture.buffer.String())
+				response.BodyTruncated = writer.capture.truncated
+			}
+
+			fields := logrus.Fields{
+				"http": HTTP{
+					Request:  completedRequest,
+					Response: response,
 				},
-				"host": Host{
-					Hostname:          removePort(r.Host),
-					ForwardedHostname: r.Header.Get(forwardedHostKey),
-					IP:                getIP(r),
-				},
-			}).Info("Completed Request")
+				"host": options.newHost(loggedRequest),
+			}
+
+			if options.ReverseDNS != nil {
+				if domain := options.ReverseDNS.Lookup(options.peerIP(r)); domain != "" {
+					fields["client"] = Client{Domain: domain}
+				}
+			}
+
+			if options.IdentityExtractor != nil {
+				if identity := options.IdentityExtractor(request); len(identity) > 0 {
+					fields[identityKey] = identity
+				}
+			}
+
+			if options.Timing {
+				fields[timingKey] = newTiming(start, duration, timingSegmentsFromContext(ctx))
+			}
+
+			if dependencies, ok := dependenciesFromContext(ctx); ok {
+				fields[dependenciesKey] = dependencies
+			}
+
+			ownership.withTier(fields)
+
+			if options.Metrics != nil {
+				options.Metrics.ObserveRequest(completedRequest, response)
+			}
+
+			level := options.levelByStatus(writer.statusCode)
+
+			if honeypot {
+				level = withHoneypot(fields, honeypotHeaders(options.CaptureRequestHeaders, loggedRequest.Header), level)
+			}
+
+			if options.SlowRequestThreshold > 0 && writer.stream == nil && response.ResponseTime > options.SlowRequestThreshold.Seconds() {
+				fields["slow"] = true
+
+				if level > logrus.WarnLevel {
+					level = logrus.WarnLevel
+				}
+
+				if options.SlowRequestEntry {
+					Get(ctx).WithFields(logrus.Fields{
+						"http": HTTP{
+							Request:  completedRequest,
+							Response: response,
+						},
+						"threshold": options.SlowRequestThreshold.Seconds(),
+					}).Warn("Slow request detected")
+				}
+			}
+
+			if !sampled && level > logrus.WarnLevel {
+				return
+			}
+
+			withSampling(fields, sampling)
+
+			if writer.stream != nil {
+				fields["stream"] = writer.stream.info(streamCloseReason(r.Context()))
+				Get(ctx).WithFields(fields).Log(level, "Stream closed")
+				return
+			}
 
+			Get(ctx).WithFields(fields).Log(level, options.completedRequestMessage())
 		})
 	}
 }
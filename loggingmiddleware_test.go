@@ -1,15 +1,21 @@
 package glogger
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"gotest.tools/assert"
@@ -316,3 +322,653 @@ func completedRequestAssertions(t *testing.T, actual *logrus.Entry, expected Exp
 	assert.Equal(t, host.Hostname, expected.Host.Hostname, "Unexpected hostname for log in completed request")
 	assert.Equal(t, host.ForwardedHostname, expected.Host.ForwardedHostname, "Unexpected forwarded-hostname for log in completed request")
 }
+
+func TestConditionalRequestFields(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+	request.Header.Set("If-None-Match", `"v1"`)
+	request.Header.Set("If-Modified-Since", "Wed, 21 Oct 2015 07:28:00 GMT")
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.WriteHeader(http.StatusNotModified)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	httpField := hook.LastEntry().Data["http"].(HTTP)
+
+	assert.Equal(t, httpField.Request.IfNoneMatch, `"v1"`)
+	assert.Equal(t, httpField.Request.IfModifiedSince, "Wed, 21 Oct 2015 07:28:00 GMT")
+	assert.Equal(t, httpField.Response.ETag, `"v1"`)
+	assert.Equal(t, httpField.Response.CacheControl, "max-age=60")
+	assert.Assert(t, httpField.Response.NotModified, "Unexpected response not marked as not modified")
+}
+
+func TestExcludedRequests(t *testing.T) {
+	options := MiddlewareOptions{
+		ExcludedPaths: []string{"/-/*", "/metrics"},
+		Skip: func(r *http.Request) bool {
+			return r.Header.Get("X-Probe") != ""
+		},
+	}
+
+	invoke := func(target string, probe bool) (int, bool) {
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.TraceLevel)
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+
+		if probe {
+			request.Header.Set("X-Probe", "1")
+		}
+
+		var hasLogger bool
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			_, hasLogger = Get(r.Context()).Data["correlationId"]
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		return len(hook.AllEntries()), hasLogger
+	}
+
+	for _, c := range []struct {
+		target  string
+		probe   bool
+		entries int
+	}{
+		{"/-/healthz", false, 0},
+		{"/metrics", false, 0},
+		{"/metrics/extra", false, 2},
+		{"/api/v1/users", true, 0},
+		{"/api/v1/users", false, 2},
+	} {
+		entries, hasLogger := invoke(c.target, c.probe)
+
+		assert.Equal(t, entries, c.entries, "Unexpected entries length for %s", c.target)
+		assert.Assert(t, hasLogger, "Unexpected missing context logger for %s", c.target)
+	}
+}
+
+func TestLevelByStatus(t *testing.T) {
+	invoke := func(options MiddlewareOptions, statusCode int) logrus.Level {
+		logger, hook := test.NewNullLogger()
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(statusCode)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		return hook.LastEntry().Level
+	}
+
+	t.Run("Default levels depend on status class", func(t *testing.T) {
+		assert.Equal(t, invoke(MiddlewareOptions{}, http.StatusOK), logrus.InfoLevel)
+		assert.Equal(t, invoke(MiddlewareOptions{}, http.StatusFound), logrus.InfoLevel)
+		assert.Equal(t, invoke(MiddlewareOptions{}, http.StatusNotFound), logrus.WarnLevel)
+		assert.Equal(t, invoke(MiddlewareOptions{}, http.StatusBadGateway), logrus.ErrorLevel)
+	})
+
+	t.Run("Levels can be overridden", func(t *testing.T) {
+		options := MiddlewareOptions{
+			LevelByStatus: func(status int) logrus.Level {
+				if status == http.StatusNotFound {
+					return logrus.InfoLevel
+				}
+
+				return DefaultLevelByStatus(status)
+			},
+		}
+
+		assert.Equal(t, invoke(options, http.StatusNotFound), logrus.InfoLevel)
+		assert.Equal(t, invoke(options, http.StatusInternalServerError), logrus.ErrorLevel)
+	})
+}
+
+func TestResponseBytesAndContentType(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", contenType)
+		rw.Write([]byte(`{"users":`))
+		rw.Write([]byte(`[]}`))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+	response := hook.LastEntry().Data["http"].(HTTP).Response
+
+	assert.Equal(t, response.Bytes, 12)
+	assert.Equal(t, response.ContentType, contenType)
+}
+
+func TestAccumulatedFields(t *testing.T) {
+	t.Run("Fields added during the request are logged in the completed request entry", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		authentication := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				AddFields(r.Context(), logrus.Fields{"userId": "42"})
+				next.ServeHTTP(rw, r)
+			})
+		}
+
+		handler := LoggingMiddleware(logger)(authentication(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			AddFields(r.Context(), logrus.Fields{"tenant": "acme"})
+			Get(r.Context()).Info("Handling Request")
+		})))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		entries := hook.AllEntries()
+		assert.Equal(t, entries[0].Message, "Handling Request")
+		assert.Equal(t, entries[0].Data["userId"], "42")
+		assert.Equal(t, entries[0].Data["tenant"], "acme")
+
+		assert.Equal(t, entries[1].Message, "Completed Request")
+		assert.Equal(t, entries[1].Data["userId"], "42")
+		assert.Equal(t, entries[1].Data["tenant"], "acme")
+	})
+
+	t.Run("Fields are ignored outside of the middleware", func(t *testing.T) {
+		ctx := context.Background()
+		AddFields(ctx, logrus.Fields{"userId": "42"})
+
+		assert.Equal(t, len(Get(ctx).Data), 0)
+	})
+}
+
+func TestRoutePattern(t *testing.T) {
+	t.Run("Gorilla mux path template is logged by default", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		router := mux.NewRouter()
+		router.Use(LoggingMiddleware(logger))
+		router.HandleFunc("/api/v1/users/{id}", func(rw http.ResponseWriter, r *http.Request) {})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+
+		request := hook.LastEntry().Data["http"].(HTTP).Request
+		assert.Equal(t, request.Path, "/api/v1/users/42")
+		assert.Equal(t, request.Route, "/api/v1/users/{id}")
+	})
+
+	t.Run("Route pattern hook overrides the default", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{
+			RoutePattern: func(r *http.Request) string {
+				return "/users/:id"
+			},
+		}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		assert.Equal(t, hook.LastEntry().Data["http"].(HTTP).Request.Route, "/users/:id")
+	})
+
+	t.Run("Route is empty without router", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		assert.Equal(t, hook.LastEntry().Data["http"].(HTTP).Request.Route, "")
+	})
+}
+
+func TestWireDump(t *testing.T) {
+	t.Run("Headers are dumped at trace level", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.TraceLevel)
+		options := MiddlewareOptions{
+			WireDump:  true,
+			Redaction: RedactionOptions{Headers: []string{"Authorization", "Set-Cookie"}},
+		}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Set-Cookie", "session=secret")
+			rw.WriteHeader(http.StatusCreated)
+		}))
+		request := httptest.NewRequest(http.MethodPost, "/users?id=1", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		request.Header.Set("X-Request-Id", "request-id")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		entries := hook.AllEntries()
+		assert.Equal(t, len(entries), 4)
+
+		assert.Equal(t, entries[1].Message, "Request dump")
+		assert.Equal(t, entries[1].Level, logrus.TraceLevel)
+		assert.DeepEqual(t, entries[1].Data["dump"], []string{
+			"POST /users?id=1 HTTP/1.1",
+			"Host: example.com",
+			"Authorization: [REDACTED]",
+			"X-Request-Id: request-id",
+		})
+
+		assert.Equal(t, entries[2].Message, "Response dump")
+		assert.DeepEqual(t, entries[2].Data["dump"], []string{
+			"HTTP/1.1 201 Created",
+			"Set-Cookie: [REDACTED]",
+			"X-Request-Id: request-id",
+		})
+	})
+
+	t.Run("Nothing is dumped above trace level", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.DebugLevel)
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{WireDump: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, len(hook.AllEntries()), 1)
+	})
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	options := MiddlewareOptions{SecurityHeaders: DefaultSecurityHeaders}
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Strict-Transport-Security", "max-age=63072000")
+		rw.Header().Set("x-content-type-options", "nosniff")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+	response := hook.LastEntry().Data["http"].(HTTP).Response
+	assert.DeepEqual(t, response.SecurityHeaders, []string{"Strict-Transport-Security", "X-Content-Type-Options"})
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  MiddlewareOptions
+		header   http.Header
+		expected string
+	}{
+		{
+			name:     "Request ID header is used",
+			header:   http.Header{"X-Request-Id": {"request-id"}},
+			expected: "request-id",
+		},
+		{
+			name:     "Alternative headers are used in order",
+			options:  MiddlewareOptions{RequestIDHeaders: []string{"X-Correlation-Id", "X-Request-Id"}},
+			header:   http.Header{"X-Request-Id": {"request-id"}, "X-Correlation-Id": {"correlation-id"}},
+			expected: "correlation-id",
+		},
+		{
+			name:     "Trace ID is used for traceparent",
+			options:  MiddlewareOptions{RequestIDHeaders: []string{"X-Request-Id", "traceparent"}},
+			header:   http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+			expected: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+
+			var requestID string
+			handler := LoggingMiddlewareWithOptions(logger, tt.options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				requestID = RequestIDFromContext(r.Context())
+			}))
+			request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+			request.Header = tt.header
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, requestID, tt.expected)
+			assert.Equal(t, recorder.Header().Get("X-Request-Id"), tt.expected)
+			assert.Equal(t, hook.LastEntry().Data["correlationId"], tt.expected)
+		})
+	}
+
+	t.Run("UUID is generated when missing", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+
+		var requestID string
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requestID = RequestIDFromContext(r.Context())
+		}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		_, err := uuid.Parse(requestID)
+		assert.NilError(t, err)
+		assert.Equal(t, recorder.Header().Get("X-Request-Id"), requestID)
+	})
+
+	t.Run("Request ID generator is used when missing", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+
+		var requestID string
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{RequestIDGenerator: ULID})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requestID = RequestIDFromContext(r.Context())
+		}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		assert.Equal(t, len(requestID), 26)
+		assert.Equal(t, recorder.Header().Get("X-Request-Id"), requestID)
+	})
+
+	t.Run("Request ID is empty outside of the middleware", func(t *testing.T) {
+		assert.Equal(t, RequestIDFromContext(context.Background()), "")
+	})
+}
+
+func TestSlowRequestThreshold(t *testing.T) {
+	slowHandler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	})
+
+	t.Run("Slow requests are logged at warning level", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{SlowRequestThreshold: 10 * time.Millisecond, SlowRequestEntry: true}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(slowHandler)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		entries := hook.AllEntries()
+		assert.Equal(t, len(entries), 2)
+		assert.Equal(t, entries[0].Message, "Slow request detected")
+		assert.Equal(t, entries[0].Data["threshold"], 0.01)
+		assert.Equal(t, entries[1].Message, "Completed Request")
+		assert.Equal(t, entries[1].Level, logrus.WarnLevel)
+		assert.Equal(t, entries[1].Data["slow"], true)
+	})
+
+	t.Run("Fast requests are not marked", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{SlowRequestThreshold: time.Second}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(slowHandler)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		assert.Equal(t, hook.LastEntry().Level, logrus.InfoLevel)
+		_, ok := hook.LastEntry().Data["slow"]
+		assert.Assert(t, !ok)
+	})
+}
+
+func TestHeaderCapture(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	options := MiddlewareOptions{
+		CaptureRequestHeaders:  []string{"accept", "X-Tenant-Id", "Authorization", "Cookie"},
+		CaptureResponseHeaders: []string{"Vary", "Set-Cookie", "X-Missing"},
+		Redaction:              RedactionOptions{Headers: []string{"X-Tenant-Id"}},
+	}
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Add("Vary", "Accept")
+		rw.Header().Add("Vary", "Accept-Encoding")
+		rw.Header().Set("Set-Cookie", "session=secret")
+	}))
+	request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("X-Tenant-Id", "acme")
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set("Cookie", "session=secret")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	fields := hook.LastEntry().Data["http"].(HTTP)
+	assert.DeepEqual(t, fields.Request.Headers, map[string]string{"Accept": "application/json", "X-Tenant-Id": "[REDACTED]"})
+	assert.DeepEqual(t, fields.Response.Headers, map[string]string{"Vary": "Accept, Accept-Encoding"})
+}
+
+func TestResponseWriterInterfaces(t *testing.T) {
+	t.Run("Hijacked connections are logged as upgrades", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			conn, buffer, err := http.NewResponseController(rw).Hijack()
+			assert.NilError(t, err)
+			defer conn.Close()
+
+			buffer.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+			buffer.Flush()
+		}))
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(rw, r)
+			close(done)
+		}))
+		defer server.Close()
+
+		request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		request.Header.Set("Connection", "Upgrade")
+		request.Header.Set("Upgrade", "websocket")
+
+		response, err := http.DefaultClient.Do(request)
+		assert.NilError(t, err)
+		response.Body.Close()
+		assert.Equal(t, response.StatusCode, http.StatusSwitchingProtocols)
+		<-done
+
+		completed := hook.LastEntry().Data["http"].(HTTP).Response
+		assert.Equal(t, completed.StatusCode, http.StatusSwitchingProtocols)
+		assert.Assert(t, completed.Hijacked)
+		assert.Equal(t, completed.Upgrade, "websocket")
+	})
+
+	t.Run("Unsupported interfaces are not implemented", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			_, ok := rw.(http.Pusher)
+			assert.Assert(t, !ok, "Unexpected http.Pusher")
+
+			_, ok = rw.(http.Hijacker)
+			assert.Assert(t, !ok, "Unexpected http.Hijacker")
+
+			_, _, err := http.NewResponseController(rw).Hijack()
+			assert.Assert(t, errors.Is(err, http.ErrNotSupported))
+
+			assert.Assert(t, http.NewResponseController(rw).Flush() == nil)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("Supported interfaces are implemented", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			_, ok := rw.(http.Pusher)
+			assert.Assert(t, ok, "Missing http.Pusher")
+
+			_, ok = rw.(http.Hijacker)
+			assert.Assert(t, ok, "Missing http.Hijacker")
+		}))
+		handler.ServeHTTP(pushingRecorder{hijackingRecorder{httptest.NewRecorder()}}, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+type hijackingRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackingRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, http.ErrNotSupported
+}
+
+type pushingRecorder struct {
+	hijackingRecorder
+}
+
+func (pushingRecorder) Push(target string, opts *http.PushOptions) error {
+	return http.ErrNotSupported
+}
+
+func TestTiming(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	before := time.Now()
+
+	handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{Timing: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+	entry := hook.LastEntry()
+	timing := entry.Data["timing"].(Timing)
+	response := entry.Data["http"].(HTTP).Response
+
+	assert.Assert(t, !timing.Start.Before(before.Round(0)) && timing.Start.Location() == time.UTC)
+	assert.Assert(t, timing.Duration >= time.Millisecond)
+	assert.Equal(t, response.ResponseTime, timing.Duration.Seconds())
+}
+
+func TestServerTiming(t *testing.T) {
+	t.Run("Header has the handler duration and the segments", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{Timing: true, ServerTiming: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			RecordTiming(r.Context(), "db", 1500*time.Microsecond)
+			RecordTiming(r.Context(), "cache miss", time.Millisecond)
+			rw.WriteHeader(http.StatusCreated)
+			RecordTiming(r.Context(), "render", time.Millisecond)
+		}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		header := recorder.Header().Get("Server-Timing")
+		assert.Assert(t, strings.HasPrefix(header, "app;dur="), "Unexpected header %q", header)
+		assert.Assert(t, strings.HasSuffix(header, ", db;dur=1.5, cache_miss;dur=1"), "Unexpected header %q", header)
+
+		timing := hook.LastEntry().Data["timing"].(Timing)
+		assert.DeepEqual(t, timing.Segments, []TimingSegment{
+			{Name: "db", Duration: 1500 * time.Microsecond},
+			{Name: "cache miss", Duration: time.Millisecond},
+			{Name: "render", Duration: time.Millisecond},
+		})
+	})
+
+	t.Run("Header is set on the implicit response", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{ServerTiming: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			RecordTiming(r.Context(), "db", time.Millisecond)
+		}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		header := recorder.Header().Get("Server-Timing")
+		assert.Assert(t, strings.HasPrefix(header, "app;dur="), "Unexpected header %q", header)
+		assert.Assert(t, strings.HasSuffix(header, ", db;dur=1"), "Unexpected header %q", header)
+	})
+
+	t.Run("Header is not set by default", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			RecordTiming(r.Context(), "db", time.Millisecond)
+		}))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		assert.Equal(t, recorder.Header().Get("Server-Timing"), "")
+	})
+}
+
+func TestReceivedAt(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	before := time.Now()
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+	entry := hook.LastEntry()
+	receivedAt, err := time.Parse(time.RFC3339Nano, entry.Data["http"].(HTTP).Request.ReceivedAt)
+	assert.NilError(t, err)
+
+	assert.Assert(t, !receivedAt.Before(before.Round(0)) && !receivedAt.After(entry.Time))
+
+	t.Run("The timing start replaces the received time", func(t *testing.T) {
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{Timing: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		entry := hook.LastEntry()
+		assert.Equal(t, entry.Data["http"].(HTTP).Request.ReceivedAt, "")
+		assert.Assert(t, !entry.Data["timing"].(Timing).Start.Before(before.Round(0)))
+	})
+}
+
+func TestCustomMessages(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.TraceLevel)
+
+	options := MiddlewareOptions{IncomingRequestMessage: "request started", CompletedRequestMessage: "request finished"}
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+	entries := hook.AllEntries()
+	assert.Equal(t, entries[0].Message, "request started")
+	assert.Equal(t, entries[1].Message, "request finished")
+}
+
+func TestIdentityExtractor(t *testing.T) {
+	type subjectKey struct{}
+
+	logger, hook := test.NewNullLogger()
+
+	options := MiddlewareOptions{
+		IdentityExtractor: func(r *http.Request) map[string]interface{} {
+			if subject, ok := r.Context().Value(subjectKey{}).(string); ok {
+				return map[string]interface{}{"userId": subject}
+			}
+
+			return nil
+		},
+	}
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+
+	authenticated := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), subjectKey{}, "42")))
+	})
+
+	authenticated.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+	assert.DeepEqual(t, hook.LastEntry().Data["identity"], map[string]interface{}{"userId": "42"})
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+	_, ok := hook.LastEntry().Data["identity"]
+	assert.Assert(t, !ok, "Unexpected identity field")
+}
+
+func TestAbortedRequests(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	t.Run("Client disconnection", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil).WithContext(ctx))
+
+		response := hook.LastEntry().Data["http"].(HTTP).Response
+		assert.Assert(t, response.Aborted)
+		assert.Equal(t, response.ContextError, "context canceled")
+		assert.Assert(t, response.StatusNotWritten)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil).WithContext(ctx))
+
+		response := hook.LastEntry().Data["http"].(HTTP).Response
+		assert.Assert(t, !response.Aborted)
+		assert.Equal(t, response.ContextError, "context deadline exceeded")
+	})
+
+	t.Run("Completed request", func(t *testing.T) {
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		response := hook.LastEntry().Data["http"].(HTTP).Response
+		assert.Assert(t, !response.Aborted && response.ContextError == "" && !response.StatusNotWritten)
+	})
+}
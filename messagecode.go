@@ -0,0 +1,23 @@
+package glogger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+const messageCodeKey = "messageCode"
+
+// WithCode adds the messageCode field to the entry, a stable machine-readable code of the
+// message, such as "payment.declined", so that the alerting rules key on the code rather
+// than on the message text, which may be reworded or translated.
+func WithCode(entry *logrus.Entry, code string) *logrus.Entry {
+	return entry.WithField(messageCodeKey, code)
+}
+
+// Code returns the logger of the context with the messageCode field.
+//
+//	glogger.Code(ctx, "payment.declined").Warn("The payment was declined")
+func Code(ctx context.Context, code string) *logrus.Entry {
+	return WithCode(Get(ctx), code)
+}
@@ -0,0 +1,23 @@
+package glogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestCode(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	ctx := WithLogger(context.Background(), logger.WithField("correlationId", "id"))
+
+	Code(ctx, "payment.declined").Warn("The payment was declined")
+
+	data := hook.LastEntry().Data
+	assert.Equal(t, data["messageCode"], "payment.declined")
+	assert.Equal(t, data["correlationId"], "id")
+
+	WithCode(logger.WithField("user", "alice"), "user.locked").Info("The user is locked")
+	assert.Equal(t, hook.LastEntry().Data["messageCode"], "user.locked")
+}
@@ -0,0 +1,10 @@
+package glogger
+
+// MetricsRecorder records the metrics of the requests logged by the middleware, from the
+// response time and status code it already measures, such as the Prometheus metrics of
+// prometheuslogger.Metrics.
+type MetricsRecorder interface {
+	// ObserveRequest records the completed request, whose route is the route pattern of
+	// the request, empty if not matched.
+	ObserveRequest(request *Request, response *Response)
+}
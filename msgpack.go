@@ -0,0 +1,158 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+var errNotJSONObject = errors.New("entry is not a JSON object")
+
+// appendMsgpackJSON appends the msgpack encoding of the JSON object of entry, keeping the
+// order of its keys.
+func appendMsgpackJSON(b []byte, entry []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(entry))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+
+	if err != nil || token != json.Delim('{') {
+		return b, errNotJSONObject
+	}
+
+	b, err = appendMsgpackValue(b, decoder, token)
+
+	if err != nil {
+		return b, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return b, errNotJSONObject
+	}
+
+	return b, nil
+}
+
+func appendMsgpackValue(b []byte, decoder *json.Decoder, token json.Token) ([]byte, error) {
+	switch value := token.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if value {
+			return append(b, 0xc3), nil
+		}
+
+		return append(b, 0xc2), nil
+	case string:
+		return appendMsgpackString(b, value), nil
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return appendMsgpackInt(b, i), nil
+		}
+
+		f, err := value.Float64()
+
+		if err != nil {
+			return b, err
+		}
+
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(f)), nil
+	case json.Delim:
+		// The length of the objects and arrays precedes their elements, which are
+		// encoded apart first.
+		var elements []byte
+		var length int
+
+		for decoder.More() {
+			token, err := decoder.Token()
+
+			if err != nil {
+				return b, err
+			}
+
+			if elements, err = appendMsgpackValue(elements, decoder, token); err != nil {
+				return b, err
+			}
+
+			if value == '{' {
+				token, err := decoder.Token()
+
+				if err != nil {
+					return b, err
+				}
+
+				if elements, err = appendMsgpackValue(elements, decoder, token); err != nil {
+					return b, err
+				}
+			}
+
+			length++
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return b, err
+		}
+
+		if value == '{' {
+			b = appendMsgpackLength(b, length, 0x80, 0xde)
+		} else {
+			b = appendMsgpackLength(b, length, 0x90, 0xdc)
+		}
+
+		return append(b, elements...), nil
+	}
+
+	return b, errNotJSONObject
+}
+
+// appendMsgpackLength appends the header of a map or an array, in its fix format of
+// up to 15 elements, or its 16 or 32 bits format.
+func appendMsgpackLength(b []byte, length int, fix, format16 byte) []byte {
+	switch {
+	case length < 16:
+		return append(b, fix|byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, format16), uint16(length))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, format16+1), uint32(length))
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch length := len(s); {
+	case length < 32:
+		b = append(b, 0xa0|byte(length))
+	case length <= math.MaxUint8:
+		b = append(b, 0xd9, byte(length))
+	case length <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(length))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(length))
+	}
+
+	return append(b, s...)
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i < 128:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+// appendMsgpackEventTime appends the EventTime extension of the Fluentd forward protocol,
+// with a nanosecond precision.
+func appendMsgpackEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
@@ -0,0 +1,97 @@
+package glogger
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+const multipartFormDataContentType = "multipart/form-data"
+
+// Part struct contains items of multipart request part info log.
+type Part struct {
+	FieldName   string `json:"fieldName,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int64  `json:"size"`
+}
+
+// multipartInspector wraps a multipart/form-data request body and parses the
+// parts metadata while the handler reads it. The content of the parts is discarded.
+type multipartInspector struct {
+	body  io.ReadCloser
+	pipe  *io.PipeWriter
+	parts []Part
+	done  chan struct{}
+}
+
+func newMultipartInspector(r *http.Request) *multipartInspector {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get(contentTypeKey))
+
+	if err != nil || mediaType != multipartFormDataContentType || params["boundary"] == "" {
+		return nil
+	}
+
+	reader, writer := io.Pipe()
+	inspector := &multipartInspector{
+		body: r.Body,
+		pipe: writer,
+		done: make(chan struct{}),
+	}
+
+	go inspector.inspect(reader, params["boundary"])
+
+	return inspector
+}
+
+func (inspector *multipartInspector) inspect(reader *io.PipeReader, boundary string) {
+	defer close(inspector.done)
+
+	multipartReader := multipart.NewReader(reader, boundary)
+
+	for {
+		part, err := multipartReader.NextRawPart()
+
+		if err != nil {
+			break
+		}
+
+		size, _ := io.Copy(io.Discard, part)
+
+		inspector.parts = append(inspector.parts, Part{
+			FieldName:   part.FormName(),
+			FileName:    part.FileName(),
+			ContentType: part.Header.Get(contentTypeKey),
+			Size:        size,
+		})
+	}
+
+	io.Copy(io.Discard, reader)
+}
+
+func (inspector *multipartInspector) Read(p []byte) (int, error) {
+	n, err := inspector.body.Read(p)
+
+	if n > 0 {
+		inspector.pipe.Write(p[:n])
+	}
+
+	if err == io.EOF {
+		inspector.pipe.Close()
+	}
+
+	return n, err
+}
+
+func (inspector *multipartInspector) Close() error {
+	return inspector.body.Close()
+}
+
+// Parts stops the inspection and returns the metadata of the parts read by the handler.
+func (inspector *multipartInspector) Parts() []Part {
+	inspector.pipe.Close()
+	<-inspector.done
+
+	return inspector.parts
+}
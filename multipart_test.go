@@ -0,0 +1,56 @@
+package glogger
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestMultipartParts(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("name", "glogger")
+	file, _ := writer.CreateFormFile("upload", "report.csv")
+	file.Write([]byte("a,b,c\n1,2,3\n"))
+	writer.Close()
+
+	invoke := func(options MiddlewareOptions) *logrus.Entry {
+		logger, hook := test.NewNullLogger()
+		request := httptest.NewRequest(http.MethodPost, defaultRequestPath, bytes.NewReader(body.Bytes()))
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			err := r.ParseMultipartForm(1 << 20)
+			assert.Assert(t, err == nil, "Unexpected error parsing multipart form")
+			assert.Equal(t, r.FormValue("name"), "glogger")
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		return hook.LastEntry()
+	}
+
+	t.Run("Parts are not logged by default", func(t *testing.T) {
+		entry := invoke(MiddlewareOptions{})
+
+		assert.Assert(t, entry.Data["http"].(HTTP).Request.Parts == nil, "Unexpected parts logged")
+	})
+
+	t.Run("Parts metadata is logged when enabled", func(t *testing.T) {
+		entry := invoke(MiddlewareOptions{LogMultipartParts: true})
+		parts := entry.Data["http"].(HTTP).Request.Parts
+
+		assert.Equal(t, len(parts), 2, "Unexpected number of parts")
+		assert.Equal(t, parts[0].FieldName, "name")
+		assert.Equal(t, parts[0].Size, int64(7))
+		assert.Equal(t, parts[1].FieldName, "upload")
+		assert.Equal(t, parts[1].FileName, "report.csv")
+		assert.Equal(t, parts[1].ContentType, "application/octet-stream")
+		assert.Equal(t, parts[1].Size, int64(12))
+	})
+}
@@ -0,0 +1,209 @@
+package glogger
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNetTimeout = 5 * time.Second
+	minNetBackoff     = 100 * time.Millisecond
+	maxNetBackoff     = 30 * time.Second
+)
+
+// netWriter writes to a connection, dialed on the first write and redialed with an
+// exponential backoff when a write fails, until the write succeeds or the writer is
+// closing. It is written from the goroutine of an AsyncWriter only, so that a collector
+// outage fills the buffer of the AsyncWriter, which then blocks or drops the entries.
+type netWriter struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	conn      net.Conn
+	backoff   time.Duration
+	closing   chan struct{}
+}
+
+func newNetWriter(network, address string, tlsConfig *tls.Config, timeout time.Duration) *netWriter {
+	if timeout <= 0 {
+		timeout = defaultNetTimeout
+	}
+
+	return &netWriter{
+		network:   network,
+		address:   address,
+		tlsConfig: tlsConfig,
+		timeout:   timeout,
+		closing:   make(chan struct{}),
+	}
+}
+
+func (w *netWriter) Write(p []byte) (int, error) {
+	var n int
+
+	err := w.retry(func() (err error) {
+		n, err = w.write(p)
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// WriteBuffers writes the entries in a single writev system call over the stream
+// connections, and in a datagram each otherwise. After a failed write, the retry starts
+// with the first entry not written entirely.
+func (w *netWriter) WriteBuffers(buffers *net.Buffers) (int64, error) {
+	var written int64
+
+	err := w.retry(func() error {
+		n, err := w.writeBuffers(buffers)
+		written += n
+		return err
+	})
+
+	return written, err
+}
+
+// retry calls write until it succeeds or the writer is closing, with an exponential backoff.
+func (w *netWriter) retry(write func() error) error {
+	for {
+		err := write()
+
+		if err == nil {
+			w.backoff = 0
+			return nil
+		}
+
+		select {
+		case <-w.closing:
+			return err
+		default:
+		}
+
+		w.backoff = min(max(2*w.backoff, minNetBackoff), maxNetBackoff)
+
+		select {
+		case <-w.closing:
+			return err
+		case <-time.After(w.backoff):
+		}
+	}
+}
+
+func (w *netWriter) write(p []byte) (int, error) {
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+
+	n, err := w.conn.Write(p)
+
+	if err != nil {
+		// The connection is dropped so that the retry starts on a new one, without the
+		// partially written message.
+		w.disconnect()
+	}
+
+	return n, err
+}
+
+func (w *netWriter) writeBuffers(buffers *net.Buffers) (int64, error) {
+	var written int64
+
+	if w.datagram() {
+		for len(*buffers) > 0 {
+			n, err := w.write((*buffers)[0])
+
+			if err != nil {
+				return written, err
+			}
+
+			written += int64(n)
+			*buffers = (*buffers)[1:]
+		}
+
+		return written, nil
+	}
+
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+
+	// WriteTo consumes a copy of the buffers, so that the partially written entry can be
+	// written again entirely on the next connection.
+	vector := append(net.Buffers(nil), *buffers...)
+	n, err := vector.WriteTo(w.conn)
+
+	if err != nil {
+		w.disconnect()
+	}
+
+	for len(*buffers) > 0 && n >= int64(len((*buffers)[0])) {
+		n -= int64(len((*buffers)[0]))
+		written += int64(len((*buffers)[0]))
+		*buffers = (*buffers)[1:]
+	}
+
+	return written, err
+}
+
+func (w *netWriter) connect() error {
+	if w.conn == nil {
+		conn, err := w.dial()
+
+		if err != nil {
+			return err
+		}
+
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+
+	return nil
+}
+
+func (w *netWriter) disconnect() {
+	w.conn.Close()
+	w.conn = nil
+}
+
+// datagram reports whether the messages are sent in a datagram each, which must not be
+// coalesced by the vectored writes.
+func (w *netWriter) datagram() bool {
+	return strings.HasPrefix(w.network, "udp") || w.network == "unixgram"
+}
+
+func (w *netWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: w.timeout}
+
+	if w.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, w.network, w.address, w.tlsConfig)
+	}
+
+	return dialer.Dial(w.network, w.address)
+}
+
+// shutdown stops the retries of the writes, so that the buffered entries are written
+// once at most when the collector is down.
+func (w *netWriter) shutdown() {
+	close(w.closing)
+}
+
+// Close closes the connection. It must be called once the writes are done.
+func (w *netWriter) Close() error {
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
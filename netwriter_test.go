@@ -0,0 +1,59 @@
+package glogger
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestNetWriterWriteBuffers(t *testing.T) {
+	t.Run("Entries are written at once over TCP", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		defer listener.Close()
+
+		writer := newNetWriter("tcp", listener.Addr().String(), nil, 0)
+		defer writer.Close()
+
+		buffers := net.Buffers{[]byte("first\n"), []byte("second\n")}
+		n, err := writer.WriteBuffers(&buffers)
+		assert.NilError(t, err)
+		assert.Equal(t, n, int64(13))
+		assert.Equal(t, len(buffers), 0)
+
+		conn, err := listener.Accept()
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		received := make([]byte, n)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = io.ReadFull(conn, received)
+		assert.NilError(t, err)
+		assert.Equal(t, string(received), "first\nsecond\n")
+	})
+
+	t.Run("Entries are sent in a datagram each over UDP", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		writer := newNetWriter("udp", conn.LocalAddr().String(), nil, 0)
+		defer writer.Close()
+
+		buffers := net.Buffers{[]byte("first"), []byte("second")}
+		_, err = writer.WriteBuffers(&buffers)
+		assert.NilError(t, err)
+
+		b := make([]byte, 1024)
+
+		for _, expected := range []string{"first", "second"} {
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, _, err := conn.ReadFrom(b)
+			assert.NilError(t, err)
+			assert.Equal(t, string(b[:n]), expected)
+		}
+	})
+}
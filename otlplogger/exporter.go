@@ -0,0 +1,661 @@
+// Package otlplogger provides a logrus hook exporting the entries to an OpenTelemetry
+// Collector with the OTLP protocol, over gRPC or HTTP.
+package otlplogger
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	logs "go.opentelemetry.io/proto/otlp/logs/v1"
+	resource "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProtocolGRPC exports the entries with OTLP/gRPC.
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP exports the entries with OTLP/HTTP, encoded with protobuf.
+	ProtocolHTTP = "http"
+
+	defaultBatchSize  = 512
+	defaultBufferSize = 2048
+	defaultInterval   = time.Second
+	defaultTimeout    = 10 * time.Second
+
+	scopeName      = "github.com/platform-horizon/glogger"
+	traceIDKey     = "traceId"
+	spanIDKey      = "spanId"
+	requestIDKey   = "correlationId"
+	serviceNameKey = "service.name"
+)
+
+// Options is the struct of options to configure an Exporter.
+type Options struct {
+	// Protocol is ProtocolGRPC, the default, or ProtocolHTTP.
+	Protocol string
+	// Endpoint is the address of the collector, such as "localhost:4317" with gRPC, or
+	// the URL of the logs, such as "http://localhost:4318/v1/logs", with HTTP.
+	Endpoint string
+	// TLSConfig enables TLS with gRPC, such as the config of glogger.TLSOptions with a
+	// private CA or a client certificate. With HTTP, TLS is enabled by the https scheme
+	// and configured by TLSConfig, unless HTTPClient is set.
+	TLSConfig *tls.Config
+	// Proxy returns the URL of the proxy of the exports, such as http.ProxyURL of a
+	// corporate egress proxy. The gRPC connections are tunneled with CONNECT. Defaults to
+	// the HTTPS_PROXY and NO_PROXY environment variables. Not used if HTTPClient is set.
+	Proxy func(*http.Request) (*url.URL, error)
+	// Headers are sent with each export, such as an authentication token.
+	Headers map[string]string
+	// HeaderSources are the headers whose values are resolved before each export, such as
+	// a token read from Vault and cached with glogger.CachedSecret. The exports fail
+	// while a header can't be resolved.
+	HeaderSources map[string]glogger.SecretSource
+	// ServiceName is the service.name attribute of the resource.
+	ServiceName string
+	// ResourceAttributes are the other attributes of the resource, such as
+	// deployment.environment.
+	ResourceAttributes map[string]string
+	// BatchSize is the maximum number of entries exported at once. Defaults to 512.
+	BatchSize int
+	// BufferSize is the maximum number of entries waiting to be exported. The entries
+	// are dropped beyond. Defaults to 2048.
+	BufferSize int
+	// Interval is the maximum delay before the entries are exported. Defaults to 1 second.
+	Interval time.Duration
+	// Timeout is the timeout of each export. Defaults to 10 seconds.
+	Timeout time.Duration
+	// HTTPClient is the client of the HTTP exports. Defaults to http.DefaultClient, or a
+	// client with the TLSConfig and the Proxy when they are set.
+	HTTPClient *http.Client
+	// Compression compresses the exports. With gRPC, only glogger.CodecGzip is
+	// supported, at the default level.
+	Compression glogger.Compression
+}
+
+// Exporter is a logrus hook exporting the entries to an OpenTelemetry Collector. The
+// level is mapped to the severity, the message to the body, the traceId and spanId
+// fields, or a correlationId which is a trace ID, to the trace context, and the other
+// fields to the attributes, such as http.request.path for the http field. The entries
+// are exported in batches from a background goroutine. Close it on shutdown so that no
+// entry is lost.
+type Exporter struct {
+	options    Options
+	resource   *resource.Resource
+	export     func(ctx context.Context, request *collogs.ExportLogsServiceRequest) error
+	conn       *grpc.ClientConn
+	httpClient *http.Client
+	records    chan *logs.LogRecord
+	flushes    chan chan struct{}
+	mutex      sync.Mutex
+	closed     bool
+	dropped    int64
+	done       chan struct{}
+}
+
+// NewExporter returns an Exporter, to be added to the hooks of the logger:
+//
+//	exporter, err := otlplogger.NewExporter(otlplogger.Options{Endpoint: "localhost:4317", ServiceName: "api"})
+//	defer exporter.Close()
+//	logger.AddHook(exporter)
+func NewExporter(options Options) (*Exporter, error) {
+	if options.Endpoint == "" {
+		return nil, errors.New("otlp: endpoint is required")
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBatchSize
+	}
+
+	if options.BufferSize <= 0 {
+		options.BufferSize = defaultBufferSize
+	}
+
+	if options.Interval <= 0 {
+		options.Interval = defaultInterval
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	exporter := &Exporter{
+		options:  options,
+		resource: newResource(options),
+		records:  make(chan *logs.LogRecord, options.BufferSize),
+		flushes:  make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	switch options.Protocol {
+	case "", ProtocolGRPC:
+		credential := insecure.NewCredentials()
+
+		if options.TLSConfig != nil {
+			credential = credentials.NewTLS(options.TLSConfig)
+		}
+
+		dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(credential)}
+
+		switch options.Compression.Codec {
+		case "":
+		case glogger.CodecGzip:
+			dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+		default:
+			return nil, fmt.Errorf("otlp: unsupported compression %q with gRPC", options.Compression.Codec)
+		}
+
+		target := options.Endpoint
+
+		if options.Proxy != nil {
+			// The endpoint is resolved by the proxy.
+			dialOptions = append(dialOptions, grpc.WithContextDialer(proxyDialer(options.Proxy)))
+
+			if !strings.Contains(target, "://") {
+				target = "passthrough:///" + target
+			}
+		}
+
+		conn, err := grpc.NewClient(target, dialOptions...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		client := collogs.NewLogsServiceClient(conn)
+		exporter.conn = conn
+		exporter.export = func(ctx context.Context, request *collogs.ExportLogsServiceRequest) error {
+			headers, err := exporter.headers(ctx)
+
+			if err != nil {
+				return err
+			}
+
+			ctx = metadata.NewOutgoingContext(ctx, metadata.New(headers))
+			_, err = client.Export(ctx, request)
+
+			return err
+		}
+	case ProtocolHTTP:
+		exporter.httpClient = newHTTPClient(options)
+		exporter.export = exporter.exportHTTP
+	default:
+		return nil, fmt.Errorf("otlp: invalid protocol %q", options.Protocol)
+	}
+
+	go exporter.run()
+
+	return exporter, nil
+}
+
+func newResource(options Options) *resource.Resource {
+	attributes := make(map[string]string, len(options.ResourceAttributes)+1)
+
+	for key, value := range options.ResourceAttributes {
+		attributes[key] = value
+	}
+
+	if options.ServiceName != "" {
+		attributes[serviceNameKey] = options.ServiceName
+	}
+
+	keys := make([]string, 0, len(attributes))
+
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	resource := &resource.Resource{}
+
+	for _, key := range keys {
+		resource.Attributes = append(resource.Attributes, &common.KeyValue{Key: key, Value: stringValue(attributes[key])})
+	}
+
+	return resource
+}
+
+// Levels returns every level, the level of the exported entries being the level of the logger.
+func (exporter *Exporter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire converts the entry to a log record, and buffers it. The entry is dropped if the
+// buffer is full.
+func (exporter *Exporter) Fire(entry *logrus.Entry) error {
+	record := newLogRecord(entry, time.Now())
+
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	if exporter.closed {
+		return nil
+	}
+
+	select {
+	case exporter.records <- record:
+	default:
+		exporter.dropped++
+	}
+
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (exporter *Exporter) Dropped() int64 {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	return exporter.dropped
+}
+
+// Saturation returns the number of entries buffered over BufferSize, from 0 to 1 when the
+// entries are dropped, such as for the Backpressure of the logging middleware.
+func (exporter *Exporter) Saturation() float64 {
+	return float64(len(exporter.records)) / float64(cap(exporter.records))
+}
+
+// Flush blocks until the buffered entries are exported.
+func (exporter *Exporter) Flush() {
+	flushed := make(chan struct{})
+
+	select {
+	case exporter.flushes <- flushed:
+		<-flushed
+	case <-exporter.done:
+	}
+}
+
+// Close exports the buffered entries and closes the connection. The following entries
+// are not exported.
+func (exporter *Exporter) Close() error {
+	exporter.mutex.Lock()
+
+	if exporter.closed {
+		exporter.mutex.Unlock()
+		return nil
+	}
+
+	exporter.closed = true
+	close(exporter.records)
+	exporter.mutex.Unlock()
+
+	<-exporter.done
+
+	if exporter.conn != nil {
+		return exporter.conn.Close()
+	}
+
+	return nil
+}
+
+func (exporter *Exporter) run() {
+	defer close(exporter.done)
+
+	ticker := time.NewTicker(exporter.options.Interval)
+	defer ticker.Stop()
+
+	batch := make([]*logs.LogRecord, 0, exporter.options.BatchSize)
+
+	send := func() {
+		if len(batch) > 0 {
+			exporter.send(batch)
+			batch = make([]*logs.LogRecord, 0, exporter.options.BatchSize)
+		}
+	}
+
+	for {
+		select {
+		case record, ok := <-exporter.records:
+			if !ok {
+				send()
+				return
+			}
+
+			if batch = append(batch, record); len(batch) >= exporter.options.BatchSize {
+				send()
+			}
+		case flushed := <-exporter.flushes:
+			for len(exporter.records) > 0 {
+				record, ok := <-exporter.records
+
+				if !ok {
+					break
+				}
+
+				if batch = append(batch, record); len(batch) >= exporter.options.BatchSize {
+					send()
+				}
+			}
+
+			send()
+			close(flushed)
+		case <-ticker.C:
+			send()
+		}
+	}
+}
+
+func (exporter *Exporter) send(batch []*logs.LogRecord) {
+	request := &collogs.ExportLogsServiceRequest{
+		ResourceLogs: []*logs.ResourceLogs{{
+			Resource: exporter.resource,
+			ScopeLogs: []*logs.ScopeLogs{{
+				Scope:      &common.InstrumentationScope{Name: scopeName},
+				LogRecords: batch,
+			}},
+		}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exporter.options.Timeout)
+	defer cancel()
+
+	if err := exporter.export(ctx, request); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export %d log records, %v\n", len(batch), err)
+	}
+}
+
+// headers returns the static headers and the resolved header sources.
+func (exporter *Exporter) headers(ctx context.Context) (map[string]string, error) {
+	if len(exporter.options.HeaderSources) == 0 {
+		return exporter.options.Headers, nil
+	}
+
+	headers := make(map[string]string, len(exporter.options.Headers)+len(exporter.options.HeaderSources))
+
+	for key, value := range exporter.options.Headers {
+		headers[key] = value
+	}
+
+	for key, source := range exporter.options.HeaderSources {
+		value, err := source(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", key, err)
+		}
+
+		headers[key] = value
+	}
+
+	return headers, nil
+}
+
+func (exporter *Exporter) exportHTTP(ctx context.Context, request *collogs.ExportLogsServiceRequest) error {
+	body, err := proto.Marshal(request)
+
+	if err != nil {
+		return err
+	}
+
+	if compression := exporter.options.Compression; compression.Enabled() {
+		if body, err = compress(body, compression); err != nil {
+			return err
+		}
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, exporter.options.Endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/x-protobuf")
+
+	if exporter.options.Compression.Enabled() {
+		httpRequest.Header.Set("Content-Encoding", string(exporter.options.Compression.Codec))
+	}
+
+	headers, err := exporter.headers(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for key, value := range headers {
+		httpRequest.Header.Set(key, value)
+	}
+
+	response, err := exporter.httpClient.Do(httpRequest)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	return nil
+}
+
+func compress(body []byte, compression glogger.Compression) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer, err := compression.NewWriter(&buffer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+var severities = map[logrus.Level]logs.SeverityNumber{
+	logrus.TraceLevel: logs.SeverityNumber_SEVERITY_NUMBER_TRACE,
+	logrus.DebugLevel: logs.SeverityNumber_SEVERITY_NUMBER_DEBUG,
+	logrus.InfoLevel:  logs.SeverityNumber_SEVERITY_NUMBER_INFO,
+	logrus.WarnLevel:  logs.SeverityNumber_SEVERITY_NUMBER_WARN,
+	logrus.ErrorLevel: logs.SeverityNumber_SEVERITY_NUMBER_ERROR,
+	logrus.FatalLevel: logs.SeverityNumber_SEVERITY_NUMBER_FATAL,
+	logrus.PanicLevel: logs.SeverityNumber_SEVERITY_NUMBER_FATAL4,
+}
+
+// newLogRecord converts an entry to a log record. The fields are converted while the
+// hook fires, since the entry is reused once the hooks returned.
+func newLogRecord(entry *logrus.Entry, observed time.Time) *logs.LogRecord {
+	record := &logs.LogRecord{
+		TimeUnixNano:         uint64(entry.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(observed.UnixNano()),
+		SeverityNumber:       severities[entry.Level],
+		SeverityText:         entry.Level.String(),
+		Body:                 stringValue(entry.Message),
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := entry.Data[key]
+
+		switch key {
+		case traceIDKey:
+			if traceID, ok := decodeID(value, 16); ok {
+				record.TraceId = traceID
+				continue
+			}
+		case spanIDKey:
+			if spanID, ok := decodeID(value, 8); ok {
+				record.SpanId = spanID
+				continue
+			}
+		case requestIDKey:
+			if _, ok := entry.Data[traceIDKey]; !ok {
+				if traceID, ok := decodeID(value, 16); ok {
+					record.TraceId = traceID
+				}
+			}
+		}
+
+		record.Attributes = appendAttributes(record.Attributes, key, value)
+	}
+
+	return record
+}
+
+// decodeID returns the bytes of a non-zero hex trace or span ID.
+func decodeID(value interface{}, length int) ([]byte, bool) {
+	s, ok := value.(string)
+
+	if !ok || len(s) != 2*length {
+		return nil, false
+	}
+
+	id, err := hex.DecodeString(s)
+
+	if err != nil || bytes.Equal(id, make([]byte, length)) {
+		return nil, false
+	}
+
+	return id, true
+}
+
+// appendAttributes appends the attributes of a field. The objects, such as the http and
+// host fields, are flattened with dotted keys.
+func appendAttributes(attributes []*common.KeyValue, key string, value interface{}) []*common.KeyValue {
+	switch value := value.(type) {
+	case string:
+		return append(attributes, &common.KeyValue{Key: key, Value: stringValue(value)})
+	case bool:
+		return append(attributes, &common.KeyValue{Key: key, Value: &common.AnyValue{Value: &common.AnyValue_BoolValue{BoolValue: value}}})
+	case int:
+		return append(attributes, &common.KeyValue{Key: key, Value: intValue(int64(value))})
+	case int64:
+		return append(attributes, &common.KeyValue{Key: key, Value: intValue(value)})
+	case float64:
+		return append(attributes, &common.KeyValue{Key: key, Value: doubleValue(value)})
+	case error:
+		return append(attributes, &common.KeyValue{Key: key, Value: stringValue(value.Error())})
+	}
+
+	b, err := json.Marshal(value)
+
+	if err != nil {
+		return append(attributes, &common.KeyValue{Key: key, Value: stringValue(fmt.Sprint(value))})
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	var decoded interface{}
+
+	if err := decoder.Decode(&decoded); err != nil {
+		return append(attributes, &common.KeyValue{Key: key, Value: stringValue(string(b))})
+	}
+
+	return appendJSONAttributes(attributes, key, decoded)
+}
+
+func appendJSONAttributes(attributes []*common.KeyValue, key string, value interface{}) []*common.KeyValue {
+	object, ok := value.(map[string]interface{})
+
+	if !ok {
+		return append(attributes, &common.KeyValue{Key: key, Value: jsonValue(value)})
+	}
+
+	keys := make([]string, 0, len(object))
+
+	for objectKey := range object {
+		keys = append(keys, objectKey)
+	}
+
+	sort.Strings(keys)
+
+	for _, objectKey := range keys {
+		attributes = appendJSONAttributes(attributes, key+"."+objectKey, object[objectKey])
+	}
+
+	return attributes
+}
+
+func jsonValue(value interface{}) *common.AnyValue {
+	switch value := value.(type) {
+	case string:
+		return stringValue(value)
+	case bool:
+		return &common.AnyValue{Value: &common.AnyValue_BoolValue{BoolValue: value}}
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return intValue(i)
+		}
+
+		f, _ := value.Float64()
+
+		return doubleValue(f)
+	case []interface{}:
+		values := make([]*common.AnyValue, len(value))
+
+		for i, element := range value {
+			values[i] = jsonValue(element)
+		}
+
+		return &common.AnyValue{Value: &common.AnyValue_ArrayValue{ArrayValue: &common.ArrayValue{Values: values}}}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+
+		for key := range value {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		values := make([]*common.KeyValue, len(keys))
+
+		for i, key := range keys {
+			values[i] = &common.KeyValue{Key: key, Value: jsonValue(value[key])}
+		}
+
+		return &common.AnyValue{Value: &common.AnyValue_KvlistValue{KvlistValue: &common.KeyValueList{Values: values}}}
+	}
+
+	return &common.AnyValue{}
+}
+
+func stringValue(s string) *common.AnyValue {
+	return &common.AnyValue{Value: &common.AnyValue_StringValue{StringValue: s}}
+}
+
+func intValue(i int64) *common.AnyValue {
+	return &common.AnyValue{Value: &common.AnyValue_IntValue{IntValue: i}}
+}
+
+func doubleValue(f float64) *common.AnyValue {
+	return &common.AnyValue{Value: &common.AnyValue_DoubleValue{DoubleValue: f}}
+}
@@ -0,0 +1,186 @@
+package otlplogger
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/platform-horizon/glogger"
+	"github.com/sirupsen/logrus"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	common "go.opentelemetry.io/proto/otlp/common/v1"
+	logs "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"gotest.tools/assert"
+)
+
+type logsServer struct {
+	collogs.UnimplementedLogsServiceServer
+	requests chan *collogs.ExportLogsServiceRequest
+	tokens   chan []string
+}
+
+func (server *logsServer) Export(ctx context.Context, request *collogs.ExportLogsServiceRequest) (*collogs.ExportLogsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	server.tokens <- md.Get("authorization")
+	server.requests <- request
+
+	return &collogs.ExportLogsServiceResponse{}, nil
+}
+
+func attributes(keyValues []*common.KeyValue) map[string]*common.AnyValue {
+	values := make(map[string]*common.AnyValue, len(keyValues))
+
+	for _, keyValue := range keyValues {
+		values[keyValue.Key] = keyValue.Value
+	}
+
+	return values
+}
+
+func TestNewLogRecord(t *testing.T) {
+	logger := logrus.New()
+
+	entry := logger.WithFields(logrus.Fields{
+		"correlationId": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"spanId":        "00f067aa0ba902b7",
+		"http":          glogger.HTTP{Request: &glogger.Request{Path: "/users", Method: http.MethodGet}, Response: &glogger.Response{StatusCode: http.StatusOK}},
+		"tags":          []string{"a", "b"},
+		"retries":       2,
+	})
+	entry.Level = logrus.WarnLevel
+	entry.Message = "Completed Request"
+
+	record := newLogRecord(entry, entry.Time)
+
+	assert.Equal(t, record.SeverityNumber, logs.SeverityNumber_SEVERITY_NUMBER_WARN)
+	assert.Equal(t, record.SeverityText, "warning")
+	assert.Equal(t, record.Body.GetStringValue(), "Completed Request")
+	assert.DeepEqual(t, record.TraceId, []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36})
+	assert.DeepEqual(t, record.SpanId, []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7})
+
+	values := attributes(record.Attributes)
+	assert.Equal(t, values["correlationId"].GetStringValue(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, values["http.request.path"].GetStringValue(), "/users")
+	assert.Equal(t, values["http.request.method"].GetStringValue(), http.MethodGet)
+	assert.Equal(t, values["http.response.statusCode"].GetIntValue(), int64(http.StatusOK))
+	assert.Equal(t, len(values["tags"].GetArrayValue().Values), 2)
+	assert.Equal(t, values["retries"].GetIntValue(), int64(2))
+	assert.Assert(t, values["spanId"] == nil)
+}
+
+func TestExporterGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+
+	server := &logsServer{requests: make(chan *collogs.ExportLogsServiceRequest, 1), tokens: make(chan []string, 1)}
+	grpcServer := grpc.NewServer()
+	collogs.RegisterLogsServiceServer(grpcServer, server)
+
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	exporter, err := NewExporter(Options{
+		Endpoint:    listener.Addr().String(),
+		Headers:     map[string]string{"authorization": "Bearer token"},
+		ServiceName: "api",
+	})
+	assert.NilError(t, err)
+
+	logger, _ := glogger.Init(glogger.InitOptions{Output: io.Discard})
+	logger.AddHook(exporter)
+	logger.WithField("user", "alice").Info("Hello")
+
+	exporter.Flush()
+	assert.NilError(t, exporter.Close())
+
+	assert.DeepEqual(t, <-server.tokens, []string{"Bearer token"})
+
+	request := <-server.requests
+	resourceLogs := request.ResourceLogs[0]
+	assert.Equal(t, attributes(resourceLogs.Resource.Attributes)["service.name"].GetStringValue(), "api")
+
+	records := resourceLogs.ScopeLogs[0].LogRecords
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].Body.GetStringValue(), "Hello")
+	assert.Equal(t, attributes(records[0].Attributes)["user"].GetStringValue(), "alice")
+}
+
+func TestExporterHTTP(t *testing.T) {
+	requests := make(chan *collogs.ExportLogsServiceRequest, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/v1/logs")
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/x-protobuf")
+		assert.Equal(t, r.Header.Get("Authorization"), "Bearer rotated")
+		assert.Equal(t, r.Header.Get("X-Scope-OrgID"), "tenant")
+
+		body, _ := io.ReadAll(r.Body)
+		request := &collogs.ExportLogsServiceRequest{}
+		assert.NilError(t, proto.Unmarshal(body, request))
+
+		requests <- request
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Options{
+		Protocol:      ProtocolHTTP,
+		Endpoint:      server.URL + "/v1/logs",
+		Headers:       map[string]string{"X-Scope-OrgID": "tenant"},
+		HeaderSources: map[string]glogger.SecretSource{"Authorization": glogger.StaticSecret("Bearer rotated")},
+	})
+	assert.NilError(t, err)
+
+	logger, _ := glogger.Init(glogger.InitOptions{Output: io.Discard})
+	logger.AddHook(exporter)
+	logger.Error("Failed")
+
+	assert.NilError(t, exporter.Close())
+
+	record := (<-requests).ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, record.Body.GetStringValue(), "Failed")
+	assert.Equal(t, record.SeverityNumber, logs.SeverityNumber_SEVERITY_NUMBER_ERROR)
+
+	_, err = NewExporter(Options{Protocol: "udp", Endpoint: server.URL})
+	assert.Assert(t, err != nil, "Expected error")
+}
+
+func TestExporterCompression(t *testing.T) {
+	requests := make(chan *collogs.ExportLogsServiceRequest, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Content-Encoding"), "zstd")
+
+		reader, err := zstd.NewReader(r.Body)
+		assert.NilError(t, err)
+		defer reader.Close()
+
+		body, _ := io.ReadAll(reader)
+		request := &collogs.ExportLogsServiceRequest{}
+		assert.NilError(t, proto.Unmarshal(body, request))
+
+		requests <- request
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(Options{Protocol: ProtocolHTTP, Endpoint: server.URL, Compression: glogger.Compression{Codec: glogger.CodecZstd}})
+	assert.NilError(t, err)
+
+	logger, _ := glogger.Init(glogger.InitOptions{Output: io.Discard})
+	logger.AddHook(exporter)
+	logger.Warn("Compressed")
+
+	assert.NilError(t, exporter.Close())
+
+	record := (<-requests).ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, record.Body.GetStringValue(), "Compressed")
+
+	_, err = NewExporter(Options{Endpoint: "localhost:4317", Compression: glogger.Compression{Codec: glogger.CodecZstd}})
+	assert.ErrorContains(t, err, "unsupported compression")
+}
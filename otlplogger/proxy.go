@@ -0,0 +1,134 @@
+package otlplogger
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// newHTTPClient returns the client of the HTTP exports.
+func newHTTPClient(options Options) *http.Client {
+	if options.HTTPClient != nil {
+		return options.HTTPClient
+	}
+
+	if options.TLSConfig == nil && options.Proxy == nil {
+		return http.DefaultClient
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = options.TLSConfig
+
+	if options.Proxy != nil {
+		transport.Proxy = options.Proxy
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// proxyDialer returns the dialer of the gRPC connections tunneled through the proxy with
+// CONNECT, or dialed directly when the proxy returns no URL for the address.
+func proxyDialer(proxy func(*http.Request) (*url.URL, error)) func(ctx context.Context, address string) (net.Conn, error) {
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		dialer := &net.Dialer{}
+		proxyURL, err := proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: address}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if proxyURL == nil {
+			return dialer.DialContext(ctx, "tcp", address)
+		}
+
+		proxyAddress := proxyURL.Host
+
+		if proxyURL.Port() == "" {
+			port := "80"
+
+			if proxyURL.Scheme == "https" {
+				port = "443"
+			}
+
+			proxyAddress = net.JoinHostPort(proxyURL.Hostname(), port)
+		}
+
+		conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if proxyURL.Scheme == "https" {
+			conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		}
+
+		reader, err := connect(ctx, conn, proxyURL, address)
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return &tunnelConn{Conn: conn, reader: reader}, nil
+	}
+}
+
+// tunnelConn is a connection tunneled through the proxy, reading first the bytes
+// buffered with the response of the proxy.
+type tunnelConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// connect asks the proxy to tunnel the connection to the address, returning the reader
+// of the tunnel.
+func connect(ctx context.Context, conn net.Conn, proxyURL *url.URL, address string) (*bufio.Reader, error) {
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if err := request.Write(conn); err != nil {
+		return nil, err
+	}
+
+	// The server may speak first, such as with the settings of HTTP/2, so the bytes after
+	// the response may be buffered by the reader.
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The body of a successful response is the tunnel, so it is not closed.
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy: CONNECT %s: %s", address, response.Status)
+	}
+
+	return reader, nil
+}
@@ -0,0 +1,121 @@
+package glogger
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Output is a destination of the entries, with its own minimum level and formatter.
+type Output struct {
+	// Writer is the destination of the entries.
+	Writer io.Writer
+	// Level is the minimum level of the entries written, such as "error". Defaults to
+	// every level enabled on the logger.
+	Level string
+	// Formatter formats the entries written. Defaults to a JSONFormatter.
+	Formatter logrus.Formatter
+	// Capabilities are the constraints of the sink, such as the flat keys or the maximum
+	// line size, applied to the Formatter.
+	Capabilities SinkCapabilities
+	// SampleRate is the fraction of the entries below warning level written, between 0
+	// and 1, sampled independently of the other outputs, such as while a new log backend
+	// is evaluated along with the legacy one. Zero writes every entry.
+	SampleRate float64
+}
+
+type output struct {
+	writer     io.Writer
+	level      logrus.Level
+	formatter  logrus.Formatter
+	sampleRate float64
+	random     func() float64
+}
+
+// outputsFormatter writes each entry to the outputs whose level is enabled, formatted with
+// their formatter. It is set as the formatter of the logger, whose output is discarded,
+// so that the entries are formatted once every hook has fired.
+type outputsFormatter struct {
+	outputs []output
+}
+
+func newOutputsFormatter(outputs []Output) (*outputsFormatter, error) {
+	formatter := &outputsFormatter{outputs: make([]output, len(outputs))}
+
+	for i, options := range outputs {
+		if options.Writer == nil {
+			return nil, fmt.Errorf("output %d has no writer", i)
+		}
+
+		level := logrus.TraceLevel
+
+		if options.Level != "" {
+			parsed, err := logrus.ParseLevel(options.Level)
+
+			if err != nil {
+				return nil, fmt.Errorf("output %d: %w", i, err)
+			}
+
+			level = parsed
+		}
+
+		entryFormatter := options.Formatter
+
+		if entryFormatter == nil {
+			entryFormatter = &JSONFormatter{}
+		}
+
+		formatter.outputs[i] = output{
+			writer:     options.Writer,
+			level:      level,
+			formatter:  options.Capabilities.formatter(entryFormatter),
+			sampleRate: options.SampleRate,
+			random:     rand.Float64,
+		}
+	}
+
+	return formatter, nil
+}
+
+// Format writes the entry to the outputs and returns no bytes. The logger lock is held,
+// so that the outputs are written sequentially.
+func (formatter *outputsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	for _, output := range formatter.outputs {
+		if entry.Level > output.level || !output.sampled(entry) {
+			continue
+		}
+
+		if entry.Buffer != nil {
+			entry.Buffer.Reset()
+		}
+
+		serialized, err := output.formatter.Format(entry)
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to obtain reader, %v\n", err)
+			continue
+		}
+
+		if _, err := output.writer.Write(serialized); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		}
+	}
+
+	if entry.Buffer != nil {
+		entry.Buffer.Reset()
+	}
+
+	return nil, nil
+}
+
+// sampled returns true if the entry is written to the output.
+func (output output) sampled(entry *logrus.Entry) bool {
+	if output.sampleRate <= 0 || output.sampleRate >= 1 || entry.Level <= logrus.WarnLevel {
+		return true
+	}
+
+	return output.random() < output.sampleRate
+}
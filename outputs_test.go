@@ -0,0 +1,71 @@
+package glogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestOutputs(t *testing.T) {
+	var all, errors bytes.Buffer
+
+	logger, err := Init(InitOptions{
+		Level: "debug",
+		Outputs: []Output{
+			{Writer: &all},
+			{Writer: &errors, Level: "error", Formatter: &ConsoleFormatter{DisableColors: true}},
+		},
+	})
+	assert.NilError(t, err)
+
+	logger.Debug("Debugging")
+	logger.WithField("userId", "42").Error("Failed")
+
+	lines := strings.Split(strings.TrimSpace(all.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.HasPrefix(lines[0], `{"level":"debug","message":"Debugging"`), lines[0])
+	assert.Assert(t, strings.HasPrefix(lines[1], `{"level":"error","message":"Failed"`), lines[1])
+
+	assert.Assert(t, strings.HasSuffix(errors.String(), " ERROR Failed userId=42\n"), errors.String())
+	assert.Equal(t, strings.Count(errors.String(), "\n"), 1)
+
+	_, err = Init(InitOptions{Outputs: []Output{{Writer: &all, Level: "verbose"}}})
+	assert.ErrorContains(t, err, "output 0")
+
+	_, err = Init(InitOptions{Outputs: []Output{{}}})
+	assert.ErrorContains(t, err, "output 0 has no writer")
+}
+
+func TestOutputsSampling(t *testing.T) {
+	var legacy, evaluated bytes.Buffer
+
+	formatter, err := newOutputsFormatter([]Output{
+		{Writer: &legacy, Formatter: &ConsoleFormatter{DisableColors: true}},
+		{Writer: &evaluated, SampleRate: 0.5},
+	})
+	assert.NilError(t, err)
+
+	randoms := []float64{0.2, 0.7}
+	formatter.outputs[1].random = func() float64 {
+		random := randoms[0]
+		randoms = randoms[1:]
+		return random
+	}
+
+	logger, err := Init(InitOptions{})
+	assert.NilError(t, err)
+	logger.SetFormatter(formatter)
+
+	logger.Info("Kept")
+	logger.Info("Dropped")
+	logger.Error("Failed")
+
+	assert.Equal(t, strings.Count(legacy.String(), "\n"), 3)
+
+	lines := strings.Split(strings.TrimSpace(evaluated.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.Contains(lines[0], `"message":"Kept"`), lines[0])
+	assert.Assert(t, strings.Contains(lines[1], `"message":"Failed"`), lines[1])
+}
@@ -0,0 +1,134 @@
+package glogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ownerKey = "owner"
+	tierKey  = "tier"
+)
+
+// Owner struct contains items of owner info log.
+type Owner struct {
+	Team       string `json:"team,omitempty"`
+	CostCenter string `json:"costCenter,omitempty"`
+}
+
+// OwnershipRule assigns an owner and a service tier to the requests whose route pattern,
+// such as "/api/v1/users/{id}", or whose path or one of its parent paths, matches the
+// pattern (as in path.Match), such as "/api/v1/users".
+type OwnershipRule struct {
+	Pattern    string `json:"pattern"`
+	Team       string `json:"team"`
+	CostCenter string `json:"costCenter,omitempty"`
+	// Tier is the service tier of the route, such as "critical", for the on-call routing.
+	Tier string `json:"tier,omitempty"`
+}
+
+// RouteOwnership maps the requests to their owners, with the first matching rule.
+type RouteOwnership struct {
+	rules []OwnershipRule
+}
+
+// NewRouteOwnership returns the route ownership of the rules, validating their patterns.
+func NewRouteOwnership(rules []OwnershipRule) (*RouteOwnership, error) {
+	for i, rule := range rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil || !strings.HasPrefix(rule.Pattern, "/") {
+			return nil, fmt.Errorf("invalid pattern %q of rule %d", rule.Pattern, i)
+		}
+	}
+
+	return &RouteOwnership{rules: rules}, nil
+}
+
+// LoadRouteOwnership loads the route ownership from a JSON file like:
+//
+//	{"routes": [{"pattern": "/api/v1/users", "team": "identity", "costCenter": "cc-1042"}]}
+func LoadRouteOwnership(filename string) (*RouteOwnership, error) {
+	data, err := os.ReadFile(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Routes []OwnershipRule `json:"routes"`
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid route ownership file %s: %w", filename, err)
+	}
+
+	return NewRouteOwnership(config.Routes)
+}
+
+// Owner returns the owner of the request path.
+func (ownership *RouteOwnership) Owner(requestPath string) (Owner, bool) {
+	rule := ownership.rule("", requestPath)
+
+	if rule == nil {
+		return Owner{}, false
+	}
+
+	return rule.owner(), true
+}
+
+// rule returns the first rule matching the route pattern, if any, or else the first rule
+// matching the request path, or nil.
+func (ownership *RouteOwnership) rule(route string, requestPath string) *OwnershipRule {
+	if ownership == nil {
+		return nil
+	}
+
+	if route != "" {
+		for i, rule := range ownership.rules {
+			if matched, _ := path.Match(rule.Pattern, route); matched {
+				return &ownership.rules[i]
+			}
+		}
+	}
+
+	for i, rule := range ownership.rules {
+		if matchPathOrParent(rule.Pattern, requestPath) {
+			return &ownership.rules[i]
+		}
+	}
+
+	return nil
+}
+
+func (rule *OwnershipRule) owner() Owner {
+	return Owner{Team: rule.Team, CostCenter: rule.CostCenter}
+}
+
+// withTier adds the owner and the tier of the rule, if any, to the fields of the
+// completed request.
+func (rule *OwnershipRule) withTier(fields logrus.Fields) {
+	if rule == nil {
+		return
+	}
+
+	fields[ownerKey] = rule.owner()
+
+	if rule.Tier != "" {
+		fields[tierKey] = rule.Tier
+	}
+}
+
+// matchPathOrParent returns true if the path, or one of its parent paths, matches the pattern.
+func matchPathOrParent(pattern string, requestPath string) bool {
+	for end := len(requestPath); end > 0; end = strings.LastIndex(requestPath[:end], "/") {
+		if matched, _ := path.Match(pattern, requestPath[:end]); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestRouteOwnership(t *testing.T) {
+	t.Run("First matching rule owns the request", func(t *testing.T) {
+		ownership, err := NewRouteOwnership([]OwnershipRule{
+			{Pattern: "/api/v1/users/*/billing", Team: "billing", CostCenter: "cc-2001"},
+			{Pattern: "/api/v1/users", Team: "identity", CostCenter: "cc-1042"},
+		})
+		assert.NilError(t, err)
+
+		cases := map[string]Owner{
+			"/api/v1/users":                  {Team: "identity", CostCenter: "cc-1042"},
+			"/api/v1/users/42":               {Team: "identity", CostCenter: "cc-1042"},
+			"/api/v1/users/42/billing/cards": {Team: "billing", CostCenter: "cc-2001"},
+		}
+
+		for requestPath, expected := range cases {
+			owner, ok := ownership.Owner(requestPath)
+			assert.Assert(t, ok, requestPath)
+			assert.Equal(t, owner, expected, "Unexpected owner for %s", requestPath)
+		}
+
+		_, ok := ownership.Owner("/api/v1/orders")
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("Invalid patterns are rejected", func(t *testing.T) {
+		_, err := NewRouteOwnership([]OwnershipRule{{Pattern: "/api/[", Team: "identity"}})
+		assert.ErrorContains(t, err, `invalid pattern "/api/[" of rule 0`)
+
+		_, err = NewRouteOwnership([]OwnershipRule{{Pattern: "api", Team: "identity"}})
+		assert.ErrorContains(t, err, "invalid pattern")
+	})
+
+	t.Run("Ownership is loaded from a file and logged", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "ownership.json")
+		os.WriteFile(filename, []byte(`{"routes":[{"pattern":"/api/v1/users","team":"identity","costCenter":"cc-1042"}]}`), 0o644)
+
+		ownership, err := LoadRouteOwnership(filename)
+		assert.NilError(t, err)
+
+		logger, hook := test.NewNullLogger()
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{RouteOwnership: ownership})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			Get(r.Context()).Info("Handling Request")
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+
+		for _, entry := range hook.AllEntries() {
+			assert.Equal(t, entry.Data["owner"], Owner{Team: "identity", CostCenter: "cc-1042"}, entry.Message)
+		}
+	})
+}
+
+func TestRouteOwnershipTier(t *testing.T) {
+	ownership, err := NewRouteOwnership([]OwnershipRule{
+		{Pattern: "/api/v1/users", Team: "identity"},
+		{Pattern: "/api/v1/users/{id}/payments", Team: "payments", Tier: "critical"},
+	})
+	assert.NilError(t, err)
+
+	logger, hook := test.NewNullLogger()
+	options := MiddlewareOptions{
+		RouteOwnership: ownership,
+		RoutePattern: func(r *http.Request) string {
+			return "/api/v1/users/{id}/payments"
+		},
+	}
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		Get(r.Context()).Info("Handling Request")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42/payments", nil))
+
+	entries := hook.AllEntries()
+	assert.Equal(t, entries[0].Data["owner"], Owner{Team: "payments"})
+	assert.Equal(t, entries[0].Data["tier"], nil)
+
+	assert.Equal(t, entries[1].Message, "Completed Request")
+	assert.Equal(t, entries[1].Data["owner"], Owner{Team: "payments"})
+	assert.Equal(t, entries[1].Data["tier"], "critical")
+
+	t.Run("The owner is resolved once per request", func(t *testing.T) {
+		hook.Reset()
+
+		// The route pattern is only known once the handler returned.
+		var handled bool
+		options.RoutePattern = func(r *http.Request) string {
+			if handled {
+				return "/api/v1/users/{id}/payments"
+			}
+
+			return ""
+		}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			Get(r.Context()).Info("Handling Request")
+			handled = true
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42/payments", nil))
+
+		for _, entry := range hook.AllEntries() {
+			assert.Equal(t, entry.Data["owner"], Owner{Team: "identity"}, entry.Message)
+		}
+	})
+}
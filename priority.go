@@ -0,0 +1,56 @@
+package glogger
+
+import (
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Priority is the importance of an entry. When the buffer of an AsyncWriter dropping the
+// entries is full, the entries of the lowest priority are dropped first.
+type Priority int
+
+const (
+	// PriorityDebug is the priority of the debug and trace entries.
+	PriorityDebug Priority = iota
+	// PriorityAccess is the priority of the access entries and the other info entries.
+	PriorityAccess
+	// PriorityError is the priority of the warning entries and above.
+	PriorityError
+	// PriorityAudit is the priority of the audit entries.
+	PriorityAudit
+)
+
+// jsonPriorityKeys are the keys of a JSON entry deciding its priority. The other keys
+// are skipped while decoding.
+type jsonPriorityKeys struct {
+	Audit json.RawMessage `json:"audit"`
+	Level string          `json:"level"`
+}
+
+// JSONPriority returns the priority of an entry of the JSONFormatter, from its top-level
+// audit and level keys. The entries which can't be decoded have the access priority.
+func JSONPriority(entry []byte) Priority {
+	var keys jsonPriorityKeys
+
+	if err := json.Unmarshal(entry, &keys); err != nil {
+		return PriorityAccess
+	}
+
+	if len(keys.Audit) > 0 && string(keys.Audit) != "null" {
+		return PriorityAudit
+	}
+
+	level, err := logrus.ParseLevel(keys.Level)
+
+	switch {
+	case err != nil:
+		return PriorityAccess
+	case level <= logrus.WarnLevel:
+		return PriorityError
+	case level >= logrus.DebugLevel:
+		return PriorityDebug
+	default:
+		return PriorityAccess
+	}
+}
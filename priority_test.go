@@ -0,0 +1,43 @@
+package glogger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestJSONPriority(t *testing.T) {
+	for level, expected := range map[logrus.Level]Priority{
+		logrus.TraceLevel: PriorityDebug,
+		logrus.DebugLevel: PriorityDebug,
+		logrus.InfoLevel:  PriorityAccess,
+		logrus.WarnLevel:  PriorityError,
+		logrus.ErrorLevel: PriorityError,
+	} {
+		var output bytes.Buffer
+
+		logger := logrus.New()
+		logger.SetLevel(logrus.TraceLevel)
+		logger.SetFormatter(&JSONFormatter{PrettyPrint: true})
+		logger.SetOutput(&output)
+		logger.Log(level, "Entry")
+
+		assert.Equal(t, JSONPriority(output.Bytes()), expected, level.String())
+	}
+
+	var output bytes.Buffer
+
+	ctx := WithAuditLogger(context.Background(), NewAuditLogger(AuditOptions{Output: &output}))
+	Audit(ctx).Record(AuditEvent{Actor: "alice", Action: "delete", Resource: "invoice/42", Outcome: AuditOutcomeSuccess})
+	assert.Equal(t, JSONPriority(output.Bytes()), PriorityAudit)
+
+	t.Run("Only the top-level keys are decoded", func(t *testing.T) {
+		entry := `{"message":"\"level\":\"error\", \"audit\":","http":{"level":"error","audit":{}},"level":"info"}` + "\n"
+		assert.Equal(t, JSONPriority([]byte(entry)), PriorityAccess)
+		assert.Equal(t, JSONPriority([]byte(`{"level":"debug","message":"\"level\":\"error\""}`)), PriorityDebug)
+		assert.Equal(t, JSONPriority([]byte(`{"level":"warn`)), PriorityAccess)
+	})
+}
@@ -0,0 +1,76 @@
+package glogger
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pidKey         = "pid"
+	instanceIDKey  = "instanceId"
+	goroutineIDKey = "goroutineId"
+)
+
+// ProcessHook is a logrus hook adding the identity of the process to every entry, to
+// disambiguate the entries of several processes writing to the same stream: the pid, an
+// instance ID generated with the hook and, for debugging, the ID of the logging goroutine.
+type ProcessHook struct {
+	pid         int
+	instanceID  string
+	goroutineID bool
+}
+
+// NewProcessHook returns a ProcessHook with a new instance ID. Getting the goroutine ID
+// requires a stack trace on every entry and should only be enabled for debugging.
+func NewProcessHook(goroutineID bool) *ProcessHook {
+	return &ProcessHook{
+		pid:         os.Getpid(),
+		instanceID:  uuid.NewString(),
+		goroutineID: goroutineID,
+	}
+}
+
+// InstanceID returns the instance ID added to the entries.
+func (hook *ProcessHook) InstanceID() string {
+	return hook.instanceID
+}
+
+// Levels returns the levels of the entries enriched by the hook, which are all of them.
+func (hook *ProcessHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the process identity fields to the entry.
+func (hook *ProcessHook) Fire(entry *logrus.Entry) error {
+	data := withField(entry.Data, pidKey, hook.pid)
+	data[instanceIDKey] = hook.instanceID
+
+	if hook.goroutineID {
+		data[goroutineIDKey] = currentGoroutineID()
+	}
+
+	entry.Data = data
+
+	return nil
+}
+
+// currentGoroutineID parses the ID of the current goroutine from the header of its
+// stack trace, "goroutine 42 [running]:".
+func currentGoroutineID() uint64 {
+	buffer := make([]byte, 64)
+	buffer = buffer[:runtime.Stack(buffer, false)]
+	buffer = bytes.TrimPrefix(buffer, []byte("goroutine "))
+
+	if i := bytes.IndexByte(buffer, ' '); i >= 0 {
+		buffer = buffer[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(buffer), 10, 64)
+
+	return id
+}
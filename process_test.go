@@ -0,0 +1,52 @@
+package glogger
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestProcessHook(t *testing.T) {
+	t.Run("Process fields are added to every entry", func(t *testing.T) {
+		processHook := NewProcessHook(false)
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		logger.AddHook(processHook)
+		hook := test.NewLocal(logger)
+
+		logger.Info("Entry")
+
+		data := hook.LastEntry().Data
+		assert.Equal(t, data[pidKey], os.Getpid())
+		assert.Equal(t, data[instanceIDKey], processHook.InstanceID())
+		_, ok := data[goroutineIDKey]
+		assert.Assert(t, !ok, "Unexpected goroutineId field")
+	})
+
+	t.Run("Goroutine ID is added when enabled", func(t *testing.T) {
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		logger.AddHook(NewProcessHook(true))
+		hook := test.NewLocal(logger)
+
+		logger.Info("Entry")
+
+		assert.Equal(t, hook.LastEntry().Data[goroutineIDKey], currentGoroutineID())
+		assert.Assert(t, currentGoroutineID() > 0, "Unexpected goroutine ID")
+	})
+
+	t.Run("Init adds the hook with a new instance ID", func(t *testing.T) {
+		first, err := Init(InitOptions{ProcessFields: true})
+		assert.NilError(t, err)
+		second, err := Init(InitOptions{ProcessFields: true})
+		assert.NilError(t, err)
+
+		firstHook := first.Hooks[logrus.InfoLevel][0].(*ProcessHook)
+		secondHook := second.Hooks[logrus.InfoLevel][0].(*ProcessHook)
+		assert.Assert(t, firstHook.InstanceID() != secondHook.InstanceID(), "Expected different instance IDs")
+	})
+}
@@ -0,0 +1,288 @@
+package glogger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ProcessSinkProtocol is the handshake line written by a sink process on its standard
+	// output once it is ready to read the entries, and the value of the
+	// GLOGGER_SINK_PROTOCOL environment variable of the process.
+	ProcessSinkProtocol = "glogger-sink/1"
+	processSinkEnv      = "GLOGGER_SINK_PROTOCOL"
+)
+
+// ProcessSinkOptions is the struct of options to configure a ProcessWriter.
+type ProcessSinkOptions struct {
+	// Path is the path of the sink executable.
+	Path string
+	// Args are the arguments of the sink executable.
+	Args []string
+	// Env are the environment variables of the sink process, such as "KEY=value", added
+	// to the environment of the service.
+	Env []string
+	// Timeout is the time the process has to answer the handshake, to read an entry
+	// written to its standard input, after which it is killed and restarted, and to exit
+	// once its standard input is closed. Defaults to 5 seconds.
+	Timeout time.Duration
+	// Buffer configures the buffer of the entries written while the process is slow or
+	// restarting.
+	Buffer AsyncWriterOptions
+}
+
+// ProcessWriter is an io.WriteCloser shipping the entries to a sink process, such as a
+// closed-source shipper, without recompiling the service against its code. The process
+// is started with the GLOGGER_SINK_PROTOCOL environment variable, answers the handshake
+// by writing the ProcessSinkProtocol line on its standard output, and then reads the
+// entries as NDJSON on its standard input until it is closed. Its standard error is
+// written to the standard error of the service.
+//
+// A process reading slowly fills the pipe, then the buffer of the entries, which then
+// blocks or drops the entries according to its overflow policy. A process which exits
+// is restarted with a backoff, as is a process which doesn't read an entry within the
+// timeout. It can be used as the InitOptions.Output.
+type ProcessWriter struct {
+	process   *processWriter
+	buffer    *AsyncWriter
+	closeOnce sync.Once
+}
+
+// NewProcessWriter starts the sink process and returns a ProcessWriter once the process
+// answered the handshake.
+func NewProcessWriter(options ProcessSinkOptions) (*ProcessWriter, error) {
+	if options.Path == "" {
+		return nil, errors.New("process sink: path is required")
+	}
+
+	process := newProcessWriter(options)
+
+	if err := process.start(); err != nil {
+		return nil, err
+	}
+
+	return &ProcessWriter{
+		process: process,
+		buffer:  NewAsyncWriter(process, options.Buffer),
+	}, nil
+}
+
+// Write buffers the entry, terminated by a newline.
+func (w *ProcessWriter) Write(p []byte) (int, error) {
+	entry := p
+
+	if !bytes.HasSuffix(p, []byte("\n")) {
+		entry = append(append(make([]byte, 0, len(p)+1), p...), '\n')
+	}
+
+	if _, err := w.buffer.Write(entry); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (w *ProcessWriter) Dropped() int64 {
+	return w.buffer.Dropped()
+}
+
+// Flush blocks until the buffered entries are written to the process.
+func (w *ProcessWriter) Flush() {
+	w.buffer.Flush()
+}
+
+// Close writes the buffered entries, without restarting the process, then closes its
+// standard input and waits for it to exit, killing it after the timeout. The following
+// writes and closes fail with ErrWriterClosed.
+func (w *ProcessWriter) Close() error {
+	err := ErrWriterClosed
+
+	w.closeOnce.Do(func() {
+		w.process.shutdown()
+		w.buffer.Close()
+
+		err = w.process.Close()
+	})
+
+	return err
+}
+
+// processWriter writes to the standard input of the sink process, restarted with an
+// exponential backoff when a write fails, until the write succeeds or the writer is
+// closing. Like the netWriter, it is written from the goroutine of an AsyncWriter only.
+type processWriter struct {
+	options ProcessSinkOptions
+	timeout time.Duration
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	// stdout is closed once the standard output of the process is read entirely.
+	stdout  chan struct{}
+	backoff time.Duration
+	closing chan struct{}
+}
+
+func newProcessWriter(options ProcessSinkOptions) *processWriter {
+	timeout := options.Timeout
+
+	if timeout <= 0 {
+		timeout = defaultNetTimeout
+	}
+
+	return &processWriter{options: options, timeout: timeout, closing: make(chan struct{})}
+}
+
+// start starts the process and waits for its handshake.
+func (w *processWriter) start() error {
+	cmd := exec.Command(w.options.Path, w.options.Args...)
+	cmd.Env = append(append(os.Environ(), processSinkEnv+"="+ProcessSinkProtocol), w.options.Env...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+
+	if err != nil {
+		return fmt.Errorf("process sink: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+
+	if err != nil {
+		return fmt.Errorf("process sink: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("process sink: %w", err)
+	}
+
+	w.cmd, w.stdin, w.stdout = cmd, stdin, make(chan struct{})
+	handshake := make(chan error, 1)
+
+	go func(done chan struct{}) {
+		defer close(done)
+
+		reader := bufio.NewReader(stdout)
+		line, err := reader.ReadString('\n')
+
+		if err == nil && strings.TrimSpace(line) != ProcessSinkProtocol {
+			err = fmt.Errorf("unexpected handshake %q", strings.TrimSpace(line))
+		}
+
+		handshake <- err
+
+		// The process may write to its standard output, which must not block it.
+		io.Copy(io.Discard, reader)
+	}(w.stdout)
+
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-handshake:
+	case <-timer.C:
+		err = errors.New("handshake timeout")
+	}
+
+	if err != nil {
+		w.kill()
+		return fmt.Errorf("process sink: %w", err)
+	}
+
+	return nil
+}
+
+func (w *processWriter) Write(p []byte) (int, error) {
+	var n int
+
+	for {
+		err := w.write(p, &n)
+
+		if err == nil {
+			w.backoff = 0
+			return n, nil
+		}
+
+		select {
+		case <-w.closing:
+			return n, err
+		default:
+		}
+
+		w.backoff = min(max(2*w.backoff, minNetBackoff), maxNetBackoff)
+
+		select {
+		case <-w.closing:
+			return n, err
+		case <-time.After(w.backoff):
+		}
+	}
+}
+
+func (w *processWriter) write(p []byte, n *int) error {
+	if w.cmd == nil {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	// A process which doesn't read the entry within the timeout is killed, which fails
+	// the write.
+	process := w.cmd.Process
+	timer := time.AfterFunc(w.timeout, func() { process.Kill() })
+
+	var err error
+	*n, err = w.stdin.Write(p)
+
+	if !timer.Stop() {
+		err = errors.New("process sink: write timeout")
+	}
+
+	if err != nil {
+		// The process is restarted by the retry, which writes the entire entry again.
+		w.kill()
+	}
+
+	return err
+}
+
+// kill kills the process and waits for it.
+func (w *processWriter) kill() {
+	w.cmd.Process.Kill()
+	<-w.stdout
+	w.cmd.Wait()
+	w.cmd = nil
+}
+
+// shutdown stops the retries of the writes.
+func (w *processWriter) shutdown() {
+	close(w.closing)
+}
+
+// Close closes the standard input of the process and waits for it to exit, killing it
+// after the timeout.
+func (w *processWriter) Close() error {
+	if w.cmd == nil {
+		return nil
+	}
+
+	w.stdin.Close()
+
+	select {
+	case <-w.stdout:
+	case <-time.After(w.timeout):
+		w.cmd.Process.Kill()
+		<-w.stdout
+	}
+
+	err := w.cmd.Wait()
+	w.cmd = nil
+
+	return err
+}
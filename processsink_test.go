@@ -0,0 +1,162 @@
+package glogger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+const (
+	testSinkModeEnv = "GLOGGER_TEST_SINK"
+	testSinkFileEnv = "GLOGGER_TEST_SINK_FILE"
+)
+
+// TestProcessSinkHelper is the sink process of the tests, run by the test binary itself.
+func TestProcessSinkHelper(t *testing.T) {
+	mode := os.Getenv(testSinkModeEnv)
+
+	if mode == "" {
+		return
+	}
+
+	if os.Getenv(processSinkEnv) != ProcessSinkProtocol {
+		os.Exit(2)
+	}
+
+	file, err := os.OpenFile(os.Getenv(testSinkFileEnv), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+
+	if err != nil {
+		os.Exit(2)
+	}
+
+	switch mode {
+	case "copy":
+		fmt.Println(ProcessSinkProtocol)
+		io.Copy(file, os.Stdin)
+	case "once":
+		// Reads one entry, then exits without reading the following ones.
+		fmt.Println(ProcessSinkProtocol)
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		os.Stdin.Close()
+		file.WriteString(line)
+	case "stuck":
+		// Never reads its standard input.
+		fmt.Println(ProcessSinkProtocol)
+		file.WriteString("started\n")
+		time.Sleep(time.Hour)
+	case "invalid":
+		fmt.Println("ready")
+		io.Copy(io.Discard, os.Stdin)
+	}
+
+	file.Close()
+	os.Exit(0)
+}
+
+func testSinkOptions(t *testing.T, mode string) (ProcessSinkOptions, string) {
+	path := filepath.Join(t.TempDir(), "sink.log")
+
+	return ProcessSinkOptions{
+		Path: os.Args[0],
+		Args: []string{"-test.run=^TestProcessSinkHelper$"},
+		Env:  []string{testSinkModeEnv + "=" + mode, testSinkFileEnv + "=" + path},
+	}, path
+}
+
+func TestProcessWriter(t *testing.T) {
+	t.Run("Entries are written to the process", func(t *testing.T) {
+		options, path := testSinkOptions(t, "copy")
+
+		writer, err := NewProcessWriter(options)
+		assert.NilError(t, err)
+
+		writer.Write([]byte(`{"message":"first"}` + "\n"))
+		writer.Write([]byte(`{"message":"second"}`))
+		assert.NilError(t, writer.Close())
+
+		written, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Equal(t, string(written), `{"message":"first"}`+"\n"+`{"message":"second"}`+"\n")
+	})
+
+	t.Run("Process is restarted when it exits", func(t *testing.T) {
+		options, path := testSinkOptions(t, "once")
+
+		writer, err := NewProcessWriter(options)
+		assert.NilError(t, err)
+
+		writer.Write([]byte(`{"message":"first"}` + "\n"))
+
+		// The entry is in the file once the process closed its standard input.
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if written, _ := os.ReadFile(path); len(written) > 0 {
+				break
+			}
+		}
+
+		writer.Write([]byte(`{"message":"second"}` + "\n"))
+		writer.Flush()
+		writer.Close()
+
+		written, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Equal(t, strings.Count(string(written), "\n"), 2, string(written))
+		assert.Assert(t, strings.Contains(string(written), "second"), string(written))
+	})
+
+	t.Run("Process is restarted when a write times out", func(t *testing.T) {
+		options, path := testSinkOptions(t, "stuck")
+		options.Timeout = 200 * time.Millisecond
+
+		writer, err := NewProcessWriter(options)
+		assert.NilError(t, err)
+
+		// The entry is larger than the pipe buffer.
+		writer.Write(append(bytes.Repeat([]byte("x"), 1<<20), '\n'))
+
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if written, _ := os.ReadFile(path); strings.Count(string(written), "started") >= 2 {
+				break
+			}
+		}
+
+		writer.Close()
+
+		written, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Count(string(written), "started") >= 2, string(written))
+	})
+
+	t.Run("Writer is closed once", func(t *testing.T) {
+		options, _ := testSinkOptions(t, "copy")
+
+		writer, err := NewProcessWriter(options)
+		assert.NilError(t, err)
+
+		assert.NilError(t, writer.Close())
+		assert.Equal(t, writer.Close(), ErrWriterClosed)
+
+		_, err = writer.Write([]byte(`{"message":"closed"}`))
+		assert.Equal(t, err, ErrWriterClosed)
+	})
+
+	t.Run("Invalid handshake is rejected", func(t *testing.T) {
+		options, _ := testSinkOptions(t, "invalid")
+
+		_, err := NewProcessWriter(options)
+		assert.ErrorContains(t, err, `process sink: unexpected handshake "ready"`)
+	})
+
+	t.Run("Path is required", func(t *testing.T) {
+		_, err := NewProcessWriter(ProcessSinkOptions{})
+		assert.ErrorContains(t, err, "process sink: path is required")
+	})
+}
@@ -0,0 +1,68 @@
+package glogger
+
+import (
+	"fmt"
+)
+
+// Profiles of the InitOptions, bundling the options suited to an environment.
+const (
+	// ProfileProduction logs the info entries and above in JSON, with the process fields.
+	// The request entries are sampled above 100 requests per second and per route.
+	ProfileProduction = "production"
+	// ProfileDevelopment logs the debug entries and above in the console format, with the
+	// caller. The request and response lines and headers are dumped at trace level.
+	ProfileDevelopment = "development"
+	// ProfileTest logs the debug entries and above in JSON, with the caller, so that the
+	// entries of a failing test show where they were logged.
+	ProfileTest = "test"
+)
+
+// productionSamplingLimit is the requests logged per second and per route in production.
+const productionSamplingLimit = 100
+
+// withProfile returns the options with the presets of the profile for the options which
+// are not set. The boolean options are enabled by the profile.
+func (option InitOptions) withProfile() (InitOptions, error) {
+	var preset InitOptions
+
+	switch option.Profile {
+	case "":
+		return option, nil
+	case ProfileProduction:
+		preset = InitOptions{Level: "info", Format: FormatJSON, ProcessFields: true}
+	case ProfileDevelopment:
+		preset = InitOptions{Level: "debug", Format: FormatConsole, Caller: true}
+	case ProfileTest:
+		preset = InitOptions{Level: "debug", Format: FormatJSON, Caller: true}
+	default:
+		return InitOptions{}, fmt.Errorf("invalid profile %q", option.Profile)
+	}
+
+	if option.Level == "" {
+		option.Level = preset.Level
+	}
+
+	if option.Format == "" {
+		option.Format = preset.Format
+	}
+
+	option.ProcessFields = option.ProcessFields || preset.ProcessFields
+	option.Caller = option.Caller || preset.Caller
+
+	return option, nil
+}
+
+// ProfileMiddlewareOptions returns the MiddlewareOptions of the profile, to be customized
+// before they are passed to LoggingMiddlewareWithOptions.
+func ProfileMiddlewareOptions(profile string) (MiddlewareOptions, error) {
+	switch profile {
+	case "", ProfileTest:
+		return MiddlewareOptions{}, nil
+	case ProfileProduction:
+		return MiddlewareOptions{Sampling: SamplingOptions{Limit: productionSamplingLimit}, RecoverPanics: true}, nil
+	case ProfileDevelopment:
+		return MiddlewareOptions{WireDump: true, RecoverPanics: true}, nil
+	default:
+		return MiddlewareOptions{}, fmt.Errorf("invalid profile %q", profile)
+	}
+}
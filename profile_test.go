@@ -0,0 +1,52 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Run("Production", func(t *testing.T) {
+		var output bytes.Buffer
+
+		logger, err := Init(InitOptions{Profile: ProfileProduction, Output: &output})
+		assert.NilError(t, err)
+		assert.Equal(t, logger.Level, logrus.InfoLevel)
+
+		logger.Debug("Hidden")
+		logger.Info("Started")
+
+		entry := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal(output.Bytes(), &entry))
+		assert.Equal(t, entry["message"], "Started")
+		assert.Assert(t, entry["instanceId"] != nil)
+
+		options, err := ProfileMiddlewareOptions(ProfileProduction)
+		assert.NilError(t, err)
+		assert.Equal(t, options.Sampling.Limit, float64(productionSamplingLimit))
+	})
+
+	t.Run("Test with a level override", func(t *testing.T) {
+		var output bytes.Buffer
+
+		logger, err := Init(InitOptions{Profile: ProfileTest, Level: "warning", Output: &output})
+		assert.NilError(t, err)
+		assert.Equal(t, logger.Level, logrus.WarnLevel)
+
+		logger.Warn("Flaky")
+
+		entry := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal(output.Bytes(), &entry))
+		assert.Assert(t, entry["caller"] != nil)
+	})
+
+	_, err := Init(InitOptions{Profile: "staging"})
+	assert.ErrorContains(t, err, `invalid profile "staging"`)
+
+	_, err = ProfileMiddlewareOptions("staging")
+	assert.ErrorContains(t, err, `invalid profile "staging"`)
+}
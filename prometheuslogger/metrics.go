@@ -0,0 +1,56 @@
+// Package prometheuslogger records the Prometheus metrics of the requests logged by the
+// glogger middleware.
+package prometheuslogger
+
+import (
+	"strconv"
+
+	"github.com/platform-horizon/glogger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics records Prometheus metrics of the requests logged by the middleware, from the
+// response time and status code it already measures. It is the Metrics of the
+// glogger.MiddlewareOptions.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+	requests *prometheus.CounterVec
+}
+
+// NewMetrics returns Metrics recording the http_request_duration_seconds histogram and
+// the http_requests_total counter, labeled by method, route and status, registered with
+// the registerer. The route is the route pattern of the request, empty if not matched.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	labels := []string{"method", "route", "status"}
+
+	metrics := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of the HTTP requests in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests.",
+		}, labels),
+	}
+
+	if err := registerer.Register(metrics.duration); err != nil {
+		return nil, err
+	}
+
+	if err := registerer.Register(metrics.requests); err != nil {
+		registerer.Unregister(metrics.duration)
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// ObserveRequest records the completed request.
+func (metrics *Metrics) ObserveRequest(request *glogger.Request, response *glogger.Response) {
+	status := strconv.Itoa(response.StatusCode)
+
+	metrics.duration.WithLabelValues(request.Method, request.Route, status).Observe(response.ResponseTime)
+	metrics.requests.WithLabelValues(request.Method, request.Route, status).Inc()
+}
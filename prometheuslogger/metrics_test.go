@@ -0,0 +1,49 @@
+package prometheuslogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/platform-horizon/glogger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("Requests are counted by method, route and status", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metrics, err := NewMetrics(registry)
+		assert.NilError(t, err)
+
+		logger, _ := test.NewNullLogger()
+		router := mux.NewRouter()
+		router.Use(glogger.LoggingMiddlewareWithOptions(logger, glogger.MiddlewareOptions{Metrics: metrics}))
+		router.HandleFunc("/users/{id}", func(rw http.ResponseWriter, r *http.Request) {
+			if mux.Vars(r)["id"] == "0" {
+				rw.WriteHeader(http.StatusNotFound)
+			}
+		})
+
+		for _, path := range []string{"/users/1", "/users/2", "/users/0"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		assert.Equal(t, testutil.ToFloat64(metrics.requests.WithLabelValues("GET", "/users/{id}", "200")), float64(2))
+		assert.Equal(t, testutil.ToFloat64(metrics.requests.WithLabelValues("GET", "/users/{id}", "404")), float64(1))
+		assert.Equal(t, testutil.CollectAndCount(metrics.duration), 2)
+	})
+
+	t.Run("Metrics cannot be registered twice", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+
+		_, err := NewMetrics(registry)
+		assert.NilError(t, err)
+
+		_, err = NewMetrics(registry)
+		assert.Assert(t, err != nil, "Expected error")
+	})
+}
@@ -0,0 +1,64 @@
+package glogger
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+)
+
+const defaultProxyHeaderTimeout = 10 * time.Second
+
+type proxyConnKey struct{}
+
+// NewProxyProtocolListener wraps the listener to accept connections carrying a
+// PROXY protocol (v1 or v2) header, as sent by L4 load balancers.
+// Use it together with ProxyProtocolConnContext to log the real client address.
+func NewProxyProtocolListener(listener net.Listener) net.Listener {
+	return &proxyproto.Listener{
+		Listener:          listener,
+		ReadHeaderTimeout: defaultProxyHeaderTimeout,
+	}
+}
+
+// ProxyProtocolConnContext is an http.Server ConnContext function storing the PROXY
+// protocol connection in the context. The logging middleware then uses the source
+// address of the PROXY header as Host.IP, ignoring any X-Forwarded-For header.
+func ProxyProtocolConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	if proxyConn, ok := conn.(*proxyproto.Conn); ok {
+		return context.WithValue(ctx, proxyConnKey{}, proxyConn)
+	}
+
+	return ctx
+}
+
+// getProxySourceIP returns the client IP carried in the PROXY header of the
+// request connection, if any.
+func getProxySourceIP(request *http.Request) string {
+	conn, ok := request.Context().Value(proxyConnKey{}).(*proxyproto.Conn)
+
+	if !ok {
+		return ""
+	}
+
+	header := conn.ProxyHeader()
+
+	if header == nil || header.Command.IsLocal() {
+		return ""
+	}
+
+	sourceIP, _, ok := header.IPs()
+
+	if !ok {
+		return ""
+	}
+
+	return sourceIP.String()
+}
@@ -0,0 +1,40 @@
+package glogger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestProxyProtocol(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Assert(t, err == nil, "Unexpected error opening listener")
+
+	logger, hook := test.NewNullLogger()
+	server := &http.Server{
+		Handler:     LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {})),
+		ConnContext: ProxyProtocolConnContext,
+	}
+	go server.Serve(NewProxyProtocolListener(listener))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.Assert(t, err == nil, "Unexpected error dialing server")
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "PROXY TCP4 192.0.2.10 127.0.0.1 56324 80\r\n")
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: localhost\r\nX-Forwarded-For: 203.0.113.1\r\nConnection: close\r\n\r\n")
+
+	response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	assert.Assert(t, err == nil, "Unexpected error reading response")
+	response.Body.Close()
+
+	host := hook.LastEntry().Data["host"].(Host)
+
+	assert.Equal(t, host.IP, "192.0.2.10")
+}
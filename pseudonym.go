@@ -0,0 +1,140 @@
+package glogger
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Pseudonymizer is a logrus hook replacing the user identifiers, such as the user IDs and
+// emails, with stable pseudonyms: their keyed hash, truncated to 128 bits. With a key per
+// environment, the activity of a user can be correlated across the entries and services
+// of an environment without logging the identifiers, and not across the environments.
+type Pseudonymizer struct {
+	keyed  KeyedHash
+	fields map[string]struct{}
+}
+
+// NewPseudonymizer returns a Pseudonymizer replacing the string values of the fields with
+// their pseudonyms, hashed with the keyed hash, such as HMAC(sha256.New, key). The fields
+// nested in objects, such as the identity field of the middleware, are set by their
+// dot-separated path:
+//
+//	logger.AddHook(glogger.NewPseudonymizer(glogger.HMAC(sha256.New, key), "userId", "email", "identity.userId"))
+func NewPseudonymizer(keyed KeyedHash, fields ...string) *Pseudonymizer {
+	pseudonymizer := &Pseudonymizer{keyed: keyed, fields: map[string]struct{}{}}
+
+	for _, field := range fields {
+		pseudonymizer.fields[field] = struct{}{}
+	}
+
+	return pseudonymizer
+}
+
+// Pseudonym returns the pseudonym of the identifier, such as to log it in another field
+// or to search the entries of a user. The emails are not case sensitive.
+func (pseudonymizer *Pseudonymizer) Pseudonym(id string) string {
+	id = strings.TrimSpace(id)
+
+	if strings.Contains(id, "@") {
+		id = strings.ToLower(id)
+	}
+
+	return pseudonymizer.keyed.hexSum(id)
+}
+
+// Levels returns the levels of the entries pseudonymized by the hook, which are all of them.
+func (pseudonymizer *Pseudonymizer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire replaces the string values of the fields of the entry with their pseudonyms.
+func (pseudonymizer *Pseudonymizer) Fire(entry *logrus.Entry) error {
+	var data logrus.Fields
+
+	for field := range pseudonymizer.fields {
+		key, path := field, []string(nil)
+
+		if _, ok := entry.Data[key]; !ok {
+			key, path = splitFieldPath(field)
+		}
+
+		// The value pseudonymized by a previous path of the same field is built upon.
+		fields := entry.Data
+
+		if data != nil {
+			fields = data
+		}
+
+		value, ok := fields[key]
+
+		if !ok {
+			continue
+		}
+
+		pseudonymized, ok := pseudonymizer.pseudonymize(value, path)
+
+		if !ok {
+			continue
+		}
+
+		if data == nil {
+			data = make(logrus.Fields, len(entry.Data))
+
+			for key, value := range entry.Data {
+				data[key] = value
+			}
+		}
+
+		data[key] = pseudonymized
+	}
+
+	if data != nil {
+		entry.Data = data
+	}
+
+	return nil
+}
+
+// pseudonymize returns the value whose string at the path, within the nested objects and
+// structs, is replaced with its pseudonym. The objects along the path are copied.
+func (pseudonymizer *Pseudonymizer) pseudonymize(value interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		id, ok := value.(string)
+
+		if !ok || id == "" {
+			return nil, false
+		}
+
+		return pseudonymizer.Pseudonym(id), true
+	}
+
+	object, err := jsonObjectOf(value)
+
+	if err != nil || object == nil {
+		return nil, false
+	}
+
+	nested, ok := pseudonymizer.pseudonymize(object[path[0]], path[1:])
+
+	if !ok {
+		return nil, false
+	}
+
+	copied := make(map[string]interface{}, len(object))
+
+	for key, value := range object {
+		copied[key] = value
+	}
+
+	copied[path[0]] = nested
+
+	return copied, true
+}
+
+// splitFieldPath splits the dot-separated path of a nested field into the key of the
+// entry field and the keys of the nested objects.
+func splitFieldPath(field string) (string, []string) {
+	keys := strings.Split(field, ".")
+	return keys[0], keys[1:]
+}
@@ -0,0 +1,69 @@
+package glogger
+
+import (
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestPseudonymizer(t *testing.T) {
+	production := NewPseudonymizer(HMAC(sha256.New, []byte("production")), "userId", "email")
+	staging := NewPseudonymizer(HMAC(sha256.New, []byte("staging")), "userId", "email")
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(production)
+	hook := test.NewLocal(logger)
+
+	fields := logrus.Fields{"userId": "42", "email": " Alice@Example.com", "action": "login"}
+	logger.WithFields(fields).Info("Logged in")
+
+	data := hook.LastEntry().Data
+	assert.Equal(t, data["userId"], production.Pseudonym("42"))
+	assert.Equal(t, data["email"], production.Pseudonym("alice@example.com"))
+	assert.Equal(t, data["action"], "login")
+	assert.Equal(t, len(data["userId"].(string)), 32)
+	assert.Equal(t, fields["userId"], "42")
+
+	assert.Assert(t, production.Pseudonym("42") != staging.Pseudonym("42"))
+	assert.Assert(t, production.Pseudonym("42") != production.Pseudonym("43"))
+
+	t.Run("Nested fields are pseudonymized by their path", func(t *testing.T) {
+		logger.ReplaceHooks(logrus.LevelHooks{})
+		logger.AddHook(NewPseudonymizer(HMAC(sha256.New, []byte("production")), "identity.userId", "actor.email"))
+		hook := test.NewLocal(logger)
+
+		identity := map[string]interface{}{"userId": "42", "tenant": "acme"}
+		actor := struct {
+			Email string `json:"email"`
+			Role  string `json:"role"`
+		}{Email: "alice@example.com", Role: "admin"}
+
+		logger.WithFields(logrus.Fields{"identity": identity, "actor": actor}).Info("Logged in")
+
+		data := hook.LastEntry().Data
+		assert.DeepEqual(t, data["identity"], map[string]interface{}{"userId": production.Pseudonym("42"), "tenant": "acme"})
+		assert.DeepEqual(t, data["actor"], map[string]interface{}{"email": production.Pseudonym("alice@example.com"), "role": "admin"})
+		assert.Equal(t, identity["userId"], "42")
+	})
+
+	t.Run("The paths of the same field are all pseudonymized", func(t *testing.T) {
+		logger.ReplaceHooks(logrus.LevelHooks{})
+		logger.AddHook(NewPseudonymizer(HMAC(sha256.New, []byte("production")), "identity.userId", "identity.email"))
+		hook := test.NewLocal(logger)
+
+		for i := 0; i < 10; i++ {
+			identity := map[string]interface{}{"userId": "42", "email": "alice@example.com"}
+			logger.WithField("identity", identity).Info("Logged in")
+
+			assert.DeepEqual(t, hook.LastEntry().Data["identity"], map[string]interface{}{
+				"userId": production.Pseudonym("42"),
+				"email":  production.Pseudonym("alice@example.com"),
+			})
+		}
+	})
+}
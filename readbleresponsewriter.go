@@ -1,28 +1,46 @@
 package glogger
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 )
 
 type readableResponseWriter struct {
-	writer     http.ResponseWriter
-	statusCode int
-	length     int
+	writer      http.ResponseWriter
+	statusCode  int
+	length      int
+	wroteHeader bool
+	stream      *eventStream
+	openStream  func(header http.Header) *eventStream
+	capture     *bodyCapture
+	openCapture func(header http.Header) *bodyCapture
+	hijacked    bool
+	// openConnection wraps the hijacked connection, once the status code is set.
+	openConnection func(conn net.Conn) net.Conn
+	// beforeHeader sets the headers of the middleware, before the header is written.
+	beforeHeader func(header http.Header)
 }
 
 func (writer *readableResponseWriter) WriteHeader(code int) {
-	writer.statusCode = code
+	writer.markHeader(code)
 	writer.writer.WriteHeader(code)
 }
 
 func (writer *readableResponseWriter) Write(b []byte) (int, error) {
+	writer.markHeader(http.StatusOK)
+
 	n, err := writer.writer.Write(b)
 
-	if err != nil {
-		return n, err
+	writer.length += n
+
+	if writer.stream != nil {
+		writer.stream.write(b[:n])
 	}
 
-	writer.length += n
+	if writer.capture != nil {
+		writer.capture.write(b[:n])
+	}
 
 	return n, err
 }
@@ -34,3 +52,137 @@ func (writer *readableResponseWriter) Header() http.Header {
 func (writer *readableResponseWriter) Length() int {
 	return writer.length
 }
+
+// Flush sends any buffered data to the client, if the underlying writer supports it.
+func (writer *readableResponseWriter) Flush() {
+	if flusher, ok := writer.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// hijack takes over the connection of the underlying writer, which must be an
+// http.Hijacker, such as for the websocket upgrades. The status code defaults to 101 if
+// no header was written.
+func (writer *readableResponseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := writer.writer.(http.Hijacker).Hijack()
+
+	if err != nil {
+		return conn, rw, err
+	}
+
+	writer.hijacked = true
+
+	if !writer.wroteHeader {
+		writer.wroteHeader = true
+		writer.statusCode = http.StatusSwitchingProtocols
+	}
+
+	if writer.openConnection == nil {
+		return conn, rw, nil
+	}
+
+	// The reads and writes of the ReadWriter go through the wrapped connection too, the
+	// bytes already read into its buffer being read first.
+	if err := rw.Flush(); err != nil {
+		return conn, rw, err
+	}
+
+	pending, _ := rw.Reader.Peek(rw.Reader.Buffered())
+	conn = writer.openConnection(&bufferedConn{Conn: conn, pending: append([]byte(nil), pending...)})
+
+	return conn, bufio.NewReadWriter(bufio.NewReaderSize(conn, rw.Reader.Size()), bufio.NewWriterSize(conn, rw.Writer.Size())), nil
+}
+
+// bufferedConn is a connection whose reads return the pending bytes first.
+type bufferedConn struct {
+	net.Conn
+	pending []byte
+}
+
+func (conn *bufferedConn) Read(b []byte) (int, error) {
+	if len(conn.pending) == 0 {
+		return conn.Conn.Read(b)
+	}
+
+	n := copy(b, conn.pending)
+	conn.pending = conn.pending[n:]
+
+	return n, nil
+}
+
+// hijackingResponseWriter is a readableResponseWriter of an http.Hijacker.
+type hijackingResponseWriter struct {
+	*readableResponseWriter
+}
+
+func (writer hijackingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return writer.hijack()
+}
+
+// pushingResponseWriter is a readableResponseWriter of an http.Pusher.
+type pushingResponseWriter struct {
+	*readableResponseWriter
+}
+
+func (writer pushingResponseWriter) Push(target string, opts *http.PushOptions) error {
+	return writer.writer.(http.Pusher).Push(target, opts)
+}
+
+// hijackingPushingResponseWriter is a readableResponseWriter of an http.Hijacker and
+// http.Pusher.
+type hijackingPushingResponseWriter struct {
+	*readableResponseWriter
+}
+
+func (writer hijackingPushingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return writer.hijack()
+}
+
+func (writer hijackingPushingResponseWriter) Push(target string, opts *http.PushOptions) error {
+	return writer.writer.(http.Pusher).Push(target, opts)
+}
+
+// responseWriter returns the writer passed to the handler, which implements http.Hijacker
+// and http.Pusher only if the underlying writer does, so that the handlers checking them
+// take the right code path.
+func (writer *readableResponseWriter) responseWriter() http.ResponseWriter {
+	_, hijacker := writer.writer.(http.Hijacker)
+	_, pusher := writer.writer.(http.Pusher)
+
+	switch {
+	case hijacker && pusher:
+		return hijackingPushingResponseWriter{writer}
+	case hijacker:
+		return hijackingResponseWriter{writer}
+	case pusher:
+		return pushingResponseWriter{writer}
+	default:
+		return writer
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (writer *readableResponseWriter) Unwrap() http.ResponseWriter {
+	return writer.writer
+}
+
+func (writer *readableResponseWriter) markHeader(code int) {
+	if writer.wroteHeader {
+		return
+	}
+
+	writer.wroteHeader = true
+	writer.statusCode = code
+
+	if writer.beforeHeader != nil {
+		writer.beforeHeader(writer.Header())
+	}
+
+	if writer.openStream != nil {
+		writer.stream = writer.openStream(writer.Header())
+	}
+
+	if writer.openCapture != nil {
+		writer.capture = writer.openCapture(writer.Header())
+	}
+}
@@ -0,0 +1,75 @@
+package glogger
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+const maxStackDepth = 64
+
+// StackFrame struct contains items of a stack trace frame info log.
+type StackFrame struct {
+	Function string `json:"function,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// Panic struct contains items of a recovered panic info log.
+type Panic struct {
+	Value string       `json:"value"`
+	Stack []StackFrame `json:"stack,omitempty"`
+}
+
+// panicStack returns the stack trace of the panicking goroutine, starting from the
+// function which panicked. It must be called by the deferred function recovering.
+func panicStack() []StackFrame {
+	pcs := make([]uintptr, maxStackDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+
+	var stack []StackFrame
+	panicking := false
+
+	for {
+		frame, more := frames.Next()
+
+		if panicking {
+			stack = append(stack, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// serveRecovering invokes the handler, recovering from its panics. The
+// http.ErrAbortHandler panic is not recovered, since it is used to abort the response.
+func serveRecovering(next http.Handler, rw http.ResponseWriter, r *http.Request) (recovered *Panic) {
+	defer func() {
+		if value := recover(); value != nil {
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			recovered = &Panic{
+				Value: fmt.Sprint(value),
+				Stack: panicStack(),
+			}
+		}
+	}()
+
+	next.ServeHTTP(rw, r)
+
+	return nil
+}
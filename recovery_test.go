@@ -0,0 +1,55 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func panickingHandler(rw http.ResponseWriter, r *http.Request) {
+	panic("something went wrong")
+}
+
+func TestRecoverPanics(t *testing.T) {
+	t.Run("Panic is logged and the request completed", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		recorder := httptest.NewRecorder()
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{RecoverPanics: true})(http.HandlerFunc(panickingHandler))
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		entries := hook.AllEntries()
+		assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+		assert.Equal(t, recorder.Code, http.StatusInternalServerError)
+
+		assert.Equal(t, entries[0].Level, logrus.ErrorLevel)
+		assert.Equal(t, entries[0].Message, "Panic Recovered")
+
+		recovered := entries[0].Data["panic"].(*Panic)
+		assert.Equal(t, recovered.Value, "something went wrong")
+		assert.Assert(t, strings.HasSuffix(recovered.Stack[0].Function, "glogger.panickingHandler"), recovered.Stack[0].Function)
+		assert.Assert(t, recovered.Stack[0].Line > 0, "Unexpected missing line")
+
+		assert.Equal(t, entries[1].Message, "Completed Request")
+		assert.Equal(t, entries[1].Data["http"].(HTTP).Response.StatusCode, http.StatusInternalServerError)
+		assert.Assert(t, entries[1].Data["http"].(HTTP).Response.ResponseTime != 0, "Unexpected response time equal to 0")
+	})
+
+	t.Run("Abort handler panic is not recovered", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{RecoverPanics: true})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		defer func() {
+			assert.Equal(t, recover(), http.ErrAbortHandler)
+		}()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+	})
+}
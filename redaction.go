@@ -0,0 +1,125 @@
+package glogger
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+// RedactionOptions is the struct of options to mask sensitive data before it is logged
+type RedactionOptions struct {
+	// QueryParams are the case-insensitive name patterns (as in path.Match, e.g. "*token*")
+	// of the query parameters whose value is masked.
+	QueryParams []string
+	// Headers are the case-insensitive name patterns of the headers whose value is masked.
+	Headers []string
+	// Values are the patterns masked in every query parameter and header value.
+	Values []*regexp.Regexp
+	// Mask replaces the redacted values. Defaults to "[REDACTED]".
+	Mask string
+}
+
+func (options RedactionOptions) enabled() bool {
+	return len(options.QueryParams) > 0 || len(options.Headers) > 0 || len(options.Values) > 0
+}
+
+func (options RedactionOptions) mask() string {
+	if options.Mask == "" {
+		return defaultRedactionMask
+	}
+
+	return options.Mask
+}
+
+func matchName(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (options RedactionOptions) redactValue(value string) string {
+	for _, pattern := range options.Values {
+		value = pattern.ReplaceAllLiteralString(value, options.mask())
+	}
+
+	return value
+}
+
+// redactQuery masks the query parameters preserving their order and encoding.
+func (options RedactionOptions) redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	params := strings.Split(rawQuery, "&")
+
+	for i, param := range params {
+		parts := strings.SplitN(param, "=", 2)
+
+		if len(parts) != 2 {
+			continue
+		}
+
+		name, err := url.QueryUnescape(parts[0])
+
+		if err != nil {
+			name = parts[0]
+		}
+
+		if matchName(options.QueryParams, name) {
+			params[i] = parts[0] + "=" + options.mask()
+			continue
+		}
+
+		params[i] = parts[0] + "=" + options.redactValue(parts[1])
+	}
+
+	return strings.Join(params, "&")
+}
+
+// redactRequest returns a copy of the request with the sensitive data masked, to be
+// used to populate the log entries. The request is returned as is if no redaction is configured.
+func (options RedactionOptions) redactRequest(r *http.Request) *http.Request {
+	if !options.enabled() {
+		return r
+	}
+
+	redacted := r.Clone(r.Context())
+	redacted.URL.RawQuery = options.redactQuery(r.URL.RawQuery)
+	redacted.Header = options.redactHeader(r.Header)
+
+	return redacted
+}
+
+// redactHeader returns a copy of the header with the sensitive values masked. The header
+// is returned as is if no redaction is configured.
+func (options RedactionOptions) redactHeader(header http.Header) http.Header {
+	if !options.enabled() {
+		return header
+	}
+
+	redacted := header.Clone()
+
+	for name, values := range redacted {
+		for i, value := range values {
+			if matchName(options.Headers, name) {
+				values[i] = options.mask()
+				continue
+			}
+
+			values[i] = options.redactValue(value)
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,44 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestRedaction(t *testing.T) {
+	options := RedactionOptions{
+		QueryParams: []string{"access_token", "*SECRET*"},
+		Headers:     []string{"User-Agent"},
+		Values:      []*regexp.Regexp{regexp.MustCompile(`\d{16}`)},
+	}
+
+	t.Run("Query parameters are masked preserving order", func(t *testing.T) {
+		actual := options.redactQuery("name=Test&access_token=abc&card=4111111111111111&client_secret=x&flag")
+
+		assert.Equal(t, actual, "name=Test&access_token=[REDACTED]&card=[REDACTED]&client_secret=[REDACTED]&flag")
+	})
+
+	t.Run("Secrets never reach the log entries", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api?access_token=abc&name=Test", nil)
+		request.Header.Set("User-Agent", userAgent)
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{Redaction: options})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.URL.Query().Get("access_token"), "abc")
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		for _, entry := range hook.AllEntries() {
+			request := entry.Data["http"].(HTTP).Request
+
+			assert.Equal(t, request.Path, "/api?access_token=[REDACTED]&name=Test")
+			assert.Equal(t, request.Query, "access_token=[REDACTED]&name=Test")
+			assert.Equal(t, request.UserAgent, "[REDACTED]")
+		}
+	})
+}
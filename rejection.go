@@ -0,0 +1,49 @@
+package glogger
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	rejectionKey           = "rejection"
+	rejectedRequestMessage = "Request Rejected"
+)
+
+// Rejection struct contains items of request rejection info log.
+type Rejection struct {
+	Reason string `json:"reason"`
+}
+
+// LogRejected logs a request rejected before its handler runs, such as by a rate limiter
+// or a load shedder, in a "Request Rejected" warning entry with the http and host fields
+// of the completed request entries and the reason of the rejection, so that the
+// rejections are counted along the other requests. It is cheaper than the middleware:
+// the response is neither wrapped nor measured. Outside of the middleware, the
+// correlationId is read from the X-Request-Id header, if any.
+//
+//	if !limiter.Allow() {
+//		glogger.LogRejected(r.Context(), r, "rate_limited", http.StatusTooManyRequests)
+//		http.Error(rw, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+//		return
+//	}
+func LogRejected(ctx context.Context, r *http.Request, reason string, status int) {
+	fields := logrus.Fields{
+		"http": HTTP{
+			Request:  newRequest(r),
+			Response: &Response{StatusCode: status},
+		},
+		"host":       newHost(r),
+		rejectionKey: Rejection{Reason: reason},
+	}
+
+	if RequestIDFromContext(ctx) == "" {
+		if requestID := r.Header.Get(correlationIDKey); requestID != "" {
+			fields["correlationId"] = requestID
+		}
+	}
+
+	Get(ctx).WithFields(fields).Warn(rejectedRequestMessage)
+}
@@ -0,0 +1,60 @@
+package glogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestLogRejected(t *testing.T) {
+	t.Run("Rejection is logged with the request schema", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		ctx := WithLogger(context.Background(), logrus.NewEntry(logger))
+		request := httptest.NewRequest(http.MethodPost, "/orders?page=2", nil)
+
+		LogRejected(ctx, request, "rate_limited", http.StatusTooManyRequests)
+
+		entry := hook.LastEntry()
+		assert.Equal(t, entry.Level, logrus.WarnLevel)
+		assert.Equal(t, entry.Message, "Request Rejected")
+		assert.DeepEqual(t, entry.Data["rejection"], Rejection{Reason: "rate_limited"})
+
+		logged := entry.Data["http"].(HTTP)
+		assert.Equal(t, logged.Request.Method, "POST")
+		assert.Equal(t, logged.Request.Path, "/orders?page=2")
+		assert.Equal(t, logged.Response.StatusCode, http.StatusTooManyRequests)
+		assert.Equal(t, entry.Data["host"].(Host).IP, "192.0.2.1")
+		assert.Equal(t, entry.Data["correlationId"], nil)
+	})
+
+	t.Run("Request ID header is logged outside of the middleware", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		ctx := WithLogger(context.Background(), logrus.NewEntry(logger))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Request-Id", "request-id")
+
+		LogRejected(ctx, request, "shed", http.StatusServiceUnavailable)
+
+		assert.Equal(t, hook.LastEntry().Data["correlationId"], "request-id")
+	})
+
+	t.Run("Request ID of the middleware is kept", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			LogRejected(r.Context(), r, "shed", http.StatusServiceUnavailable)
+		}))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-Request-Id", "request-id")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		entry := hook.AllEntries()[0]
+		assert.Equal(t, entry.Message, "Request Rejected")
+		assert.Equal(t, entry.Data["correlationId"], "request-id")
+	})
+}
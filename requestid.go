@@ -0,0 +1,47 @@
+package glogger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the ID of the request handled by the middleware, read
+// from the request headers or generated, which is logged as correlationId. It returns
+// an empty string for contexts not created by the middleware.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func withRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// requestID returns the value of the first request ID header present in the request, or
+// a new ID of the RequestIDGenerator if none is present. The trace ID is used for the traceparent header.
+func (options MiddlewareOptions) requestID(r *http.Request) string {
+	headers := options.RequestIDHeaders
+
+	if len(headers) == 0 {
+		headers = []string{correlationIDKey}
+	}
+
+	for _, name := range headers {
+		if strings.EqualFold(name, traceparentKey) {
+			if traceID, _, ok := parseTraceparent(r.Header.Get(traceparentKey)); ok {
+				return traceID
+			}
+
+			continue
+		}
+
+		if requestID := r.Header.Get(name); requestID != "" {
+			return requestID
+		}
+	}
+
+	return options.RequestIDGenerator.newID()
+}
@@ -0,0 +1,149 @@
+package glogger
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReverseDNSTTL         = 10 * time.Minute
+	defaultReverseDNSNegativeTTL = time.Minute
+	defaultReverseDNSTimeout     = 2 * time.Second
+	defaultReverseDNSMaxEntries  = 10000
+)
+
+// Client struct contains items of client info log.
+type Client struct {
+	Domain string `json:"domain,omitempty"`
+}
+
+// ReverseDNSOptions is the struct of options to configure reverse DNS enrichment
+type ReverseDNSOptions struct {
+	// Resolver used for the lookups. Defaults to net.DefaultResolver.
+	Resolver *net.Resolver
+	// TTL of resolved names. Defaults to 10 minutes.
+	TTL time.Duration
+	// NegativeTTL of unresolvable addresses. Defaults to 1 minute.
+	NegativeTTL time.Duration
+	// Timeout of a single lookup. Defaults to 2 seconds.
+	Timeout time.Duration
+	// MaxEntries is the maximum number of cached addresses. Defaults to 10000.
+	MaxEntries int
+}
+
+type reverseDNSEntry struct {
+	domain  string
+	expires time.Time
+}
+
+// ReverseDNS resolves client IPs to domain names in background, caching both
+// the resolved names and the failed lookups.
+type ReverseDNS struct {
+	options    ReverseDNSOptions
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+
+	mutex    sync.Mutex
+	cache    map[string]reverseDNSEntry
+	inflight map[string]struct{}
+}
+
+// NewReverseDNS creates a reverse DNS resolver to be used in MiddlewareOptions
+func NewReverseDNS(options ReverseDNSOptions) *ReverseDNS {
+	if options.Resolver == nil {
+		options.Resolver = net.DefaultResolver
+	}
+
+	if options.TTL <= 0 {
+		options.TTL = defaultReverseDNSTTL
+	}
+
+	if options.NegativeTTL <= 0 {
+		options.NegativeTTL = defaultReverseDNSNegativeTTL
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = defaultReverseDNSTimeout
+	}
+
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = defaultReverseDNSMaxEntries
+	}
+
+	return &ReverseDNS{
+		options:    options,
+		lookupAddr: options.Resolver.LookupAddr,
+		cache:      make(map[string]reverseDNSEntry),
+		inflight:   make(map[string]struct{}),
+	}
+}
+
+// Lookup returns the cached domain of the IP. If the IP is not cached or its entry
+// is expired, a lookup is started in background and an empty string is returned.
+func (resolver *ReverseDNS) Lookup(ip string) string {
+	if net.ParseIP(ip) == nil {
+		return ""
+	}
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+
+	entry, ok := resolver.cache[ip]
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.domain
+	}
+
+	if _, ok := resolver.inflight[ip]; !ok {
+		resolver.inflight[ip] = struct{}{}
+		go resolver.resolve(ip)
+	}
+
+	return entry.domain
+}
+
+func (resolver *ReverseDNS) resolve(ip string) {
+	ctx, cancel := context.WithTimeout(context.Background(), resolver.options.Timeout)
+	defer cancel()
+
+	entry := reverseDNSEntry{expires: time.Now().Add(resolver.options.NegativeTTL)}
+
+	if names, err := resolver.lookupAddr(ctx, ip); err == nil && len(names) > 0 {
+		entry = reverseDNSEntry{
+			domain:  strings.TrimSuffix(names[0], "."),
+			expires: time.Now().Add(resolver.options.TTL),
+		}
+	}
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+
+	delete(resolver.inflight, ip)
+
+	if len(resolver.cache) >= resolver.options.MaxEntries {
+		resolver.evict()
+	}
+
+	resolver.cache[ip] = entry
+}
+
+// evict removes the expired entries, or an arbitrary one when none is expired.
+func (resolver *ReverseDNS) evict() {
+	now := time.Now()
+
+	for ip, entry := range resolver.cache {
+		if now.After(entry.expires) {
+			delete(resolver.cache, ip)
+		}
+	}
+
+	for ip := range resolver.cache {
+		if len(resolver.cache) < resolver.options.MaxEntries {
+			return
+		}
+
+		delete(resolver.cache, ip)
+	}
+}
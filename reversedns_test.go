@@ -0,0 +1,106 @@
+package glogger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func waitLookups(resolver *ReverseDNS) {
+	for {
+		resolver.mutex.Lock()
+		pending := len(resolver.inflight)
+		resolver.mutex.Unlock()
+
+		if pending == 0 {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReverseDNS(t *testing.T) {
+	t.Run("Resolved names are cached", func(t *testing.T) {
+		var lookups int32
+		resolver := NewReverseDNS(ReverseDNSOptions{})
+		resolver.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+			atomic.AddInt32(&lookups, 1)
+			return []string{"client.internal."}, nil
+		}
+
+		assert.Equal(t, resolver.Lookup("192.0.2.1"), "")
+		waitLookups(resolver)
+		assert.Equal(t, resolver.Lookup("192.0.2.1"), "client.internal")
+		assert.Equal(t, atomic.LoadInt32(&lookups), int32(1))
+	})
+
+	t.Run("Failed lookups are cached", func(t *testing.T) {
+		var lookups int32
+		resolver := NewReverseDNS(ReverseDNSOptions{})
+		resolver.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+			atomic.AddInt32(&lookups, 1)
+			return nil, errors.New("no such host")
+		}
+
+		resolver.Lookup("192.0.2.1")
+		waitLookups(resolver)
+		assert.Equal(t, resolver.Lookup("192.0.2.1"), "")
+		waitLookups(resolver)
+		assert.Equal(t, atomic.LoadInt32(&lookups), int32(1))
+	})
+
+	t.Run("Non IP values are not resolved", func(t *testing.T) {
+		resolver := NewReverseDNS(ReverseDNSOptions{})
+
+		assert.Equal(t, resolver.Lookup("192.0.2.1, 192.0.2.2"), "")
+		assert.Equal(t, len(resolver.inflight), 0)
+	})
+
+	t.Run("Client domain is logged", func(t *testing.T) {
+		resolver := NewReverseDNS(ReverseDNSOptions{})
+		resolver.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+			return []string{"client.internal."}, nil
+		}
+		resolver.Lookup("192.0.2.1")
+		waitLookups(resolver)
+
+		logger, hook := test.NewNullLogger()
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{ReverseDNS: resolver})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+
+		assert.Equal(t, hook.LastEntry().Data["client"].(Client).Domain, "client.internal")
+	})
+
+	t.Run("Forwarded IPs are only resolved through trusted proxies", func(t *testing.T) {
+		var lookups []string
+		resolver := NewReverseDNS(ReverseDNSOptions{})
+		resolver.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+			lookups = append(lookups, addr)
+			return nil, errors.New("no such host")
+		}
+
+		logger, _ := test.NewNullLogger()
+		request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+		request.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{ReverseDNS: resolver})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		waitLookups(resolver)
+		assert.DeepEqual(t, lookups, []string{"192.0.2.1"})
+
+		options := MiddlewareOptions{ReverseDNS: resolver, TrustedProxies: TrustedProxies{netip.MustParsePrefix("192.0.2.0/24")}}
+		handler = LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		waitLookups(resolver)
+		assert.DeepEqual(t, lookups, []string{"192.0.2.1", "203.0.113.7"})
+	})
+}
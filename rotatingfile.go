@@ -0,0 +1,558 @@
+package glogger
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFileOptions is the struct of options to configure a rotating file
+type RotatingFileOptions struct {
+	// Filename is the path of the file. Its directory is created if missing.
+	Filename string
+	// MaxSize is the maximum size of the file, in bytes, before it is rotated. Zero
+	// disables the rotation by size.
+	MaxSize int64
+	// Interval is the maximum duration between two rotations, such as 24 hours. Zero
+	// disables the rotation by age.
+	Interval time.Duration
+	// MaxBackups is the maximum number of rotated files kept. Zero keeps them all.
+	MaxBackups int
+	// MaxAge is the maximum age of the rotated files kept. Zero keeps them all.
+	MaxAge time.Duration
+	// Compress compresses the rotated files with gzip, in background, like Compression
+	// with CodecGzip.
+	Compress bool
+	// Compression compresses the rotated files with the codec and level, in background.
+	// It takes precedence over Compress.
+	Compression Compression
+	// Lock shares the file between processes, such as pre-forked workers: each entry is
+	// written, and the file rotated, entirely under an exclusive advisory lock, and the processes
+	// reopen the file rotated by another one. It relies on flock, on the platforms
+	// supporting it.
+	Lock bool
+	// Verify reads back the end of the file periodically to report the corrupted
+	// entries, such as the partial lines written when the disk is full.
+	Verify VerifyOptions
+}
+
+func (options RotatingFileOptions) compression() Compression {
+	if !options.Compression.Enabled() && options.Compress {
+		return Compression{Codec: CodecGzip}
+	}
+
+	return options.Compression
+}
+
+// RotatingFile is an io.WriteCloser writing to a file which is rotated by size or age.
+// The rotated files are renamed with their rotation time, such as
+// app-2024-01-02T15-04-05.000.log, followed by a counter such as "-1" for the files
+// rotated in the same millisecond, compressed and removed in background.
+// It can be used as the InitOptions.Output.
+type RotatingFile struct {
+	options   RotatingFileOptions
+	now       func() time.Time
+	rename    func(oldpath, newpath string) error
+	mutex     sync.Mutex
+	file      *os.File
+	size      int64
+	opened    time.Time
+	mill      sync.WaitGroup
+	millMutex sync.Mutex
+	// verification is guarded by the mutex.
+	verification verification
+}
+
+var (
+	activeRotatingFilesMutex sync.Mutex
+	// activeRotatingFiles counts the open RotatingFiles by absolute file name.
+	activeRotatingFiles = map[string]int{}
+)
+
+// NewRotatingFile opens the file in append mode, creating it if missing.
+func NewRotatingFile(options RotatingFileOptions) (*RotatingFile, error) {
+	file := &RotatingFile{options: options, now: time.Now, rename: os.Rename}
+
+	if err := file.open(); err != nil {
+		return nil, err
+	}
+
+	setActiveRotatingFile(options.Filename, 1)
+
+	if options.Verify.Interval > 0 {
+		file.startVerification()
+	}
+
+	return file, nil
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.options.Filename), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.options.Filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.now()
+
+	return nil
+}
+
+// Write writes the entry to the file, rotating it first if the entry would exceed
+// MaxSize or the Interval has elapsed.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return 0, ErrWriterClosed
+	}
+
+	if f.options.Lock {
+		if err := f.lock(); err != nil {
+			return 0, err
+		}
+
+		defer f.unlock()
+	}
+
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// lock locks the file against the other processes. The file is reopened first when
+// another process rotated it, and its size is updated with their writes.
+func (f *RotatingFile) lock() error {
+	for {
+		if err := lockFile(f.file); err != nil {
+			return err
+		}
+
+		info, err := f.file.Stat()
+
+		if err != nil {
+			unlockFile(f.file)
+			return err
+		}
+
+		if current, err := os.Stat(f.options.Filename); err == nil && os.SameFile(info, current) {
+			f.size = info.Size()
+			return nil
+		}
+
+		f.file.Close()
+		f.file = nil
+
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *RotatingFile) unlock() {
+	if f.file != nil {
+		unlockFile(f.file)
+	}
+}
+
+func (f *RotatingFile) shouldRotate(size int) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.options.MaxSize > 0 && f.size+int64(size) > f.options.MaxSize {
+		return true
+	}
+
+	return f.options.Interval > 0 && f.now().Sub(f.opened) >= f.options.Interval
+}
+
+// Rotate rotates the file immediately.
+func (f *RotatingFile) Rotate() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return ErrWriterClosed
+	}
+
+	if f.options.Lock {
+		if err := f.lock(); err != nil {
+			return err
+		}
+
+		defer f.unlock()
+	}
+
+	return f.rotate()
+}
+
+// rotate renames the file and opens a new one. With Lock, the file locked by the caller
+// is renamed while open, and closed once the new file is open and locked, so that the
+// other processes neither write to it nor rotate it meanwhile.
+func (f *RotatingFile) rotate() error {
+	if f.options.Lock && fileLocking {
+		return f.rotateLocked()
+	}
+
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	f.file = nil
+
+	if err := f.rename(f.options.Filename, f.backupName(f.now().UTC())); err != nil && !os.IsNotExist(err) {
+		// The writes go on to the file which could not be rotated.
+		if err := f.open(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.millInBackground()
+
+	return nil
+}
+
+func (f *RotatingFile) rotateLocked() error {
+	rotated := f.file
+
+	if err := f.rename(f.options.Filename, f.backupName(f.now().UTC())); err != nil && !os.IsNotExist(err) {
+		// The writes go on to the file which could not be rotated, still locked.
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		rotated.Close()
+		f.file = nil
+
+		return err
+	}
+
+	if err := lockFile(f.file); err != nil {
+		f.file.Close()
+		rotated.Close()
+		f.file = nil
+
+		return err
+	}
+
+	// Closing the rotated file releases its lock.
+	rotated.Close()
+	f.millInBackground()
+
+	return nil
+}
+
+// millInBackground removes and compresses the backups in background.
+func (f *RotatingFile) millInBackground() {
+	f.mill.Add(1)
+
+	go func() {
+		defer f.mill.Done()
+		f.millBackups()
+	}()
+}
+
+// Reopen closes and reopens the file, after it was moved by an external tool such as logrotate.
+func (f *RotatingFile) Reopen() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return ErrWriterClosed
+	}
+
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	f.file = nil
+
+	return f.open()
+}
+
+// Close closes the file and waits for the background compression and removal of the
+// rotated files.
+func (f *RotatingFile) Close() error {
+	f.mutex.Lock()
+
+	if f.file == nil {
+		f.mutex.Unlock()
+		return ErrWriterClosed
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	f.mutex.Unlock()
+
+	setActiveRotatingFile(f.options.Filename, -1)
+	f.stopVerification()
+	f.mill.Wait()
+
+	return err
+}
+
+// ReopenOnSignal reopens the file every time the process receives SIGUSR1, as sent by
+// logrotate, on the platforms supporting it. The returned function stops the reopening.
+func (f *RotatingFile) ReopenOnSignal() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	if len(reopenSignals) > 0 {
+		signal.Notify(signals, reopenSignals...)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				f.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+func setActiveRotatingFile(filename string, delta int) {
+	path, err := filepath.Abs(filename)
+
+	if err != nil {
+		return
+	}
+
+	activeRotatingFilesMutex.Lock()
+	defer activeRotatingFilesMutex.Unlock()
+
+	if activeRotatingFiles[path] += delta; activeRotatingFiles[path] <= 0 {
+		delete(activeRotatingFiles, path)
+	}
+}
+
+// isActiveRotatingFile returns whether the file is written by an open RotatingFile.
+func isActiveRotatingFile(filename string) bool {
+	path, err := filepath.Abs(filename)
+
+	if err != nil {
+		return false
+	}
+
+	activeRotatingFilesMutex.Lock()
+	defer activeRotatingFilesMutex.Unlock()
+
+	return activeRotatingFiles[path] > 0
+}
+
+// backupName returns the name of the file rotated at the time, which is not the name of
+// an existing rotated file, compressed or not.
+func (f *RotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.options.Filename)
+	prefix := strings.TrimSuffix(f.options.Filename, ext) + "-" + t.Format(backupTimeFormat)
+	name := prefix + ext
+
+	for counter := 1; backupExists(name); counter++ {
+		name = prefix + "-" + strconv.Itoa(counter) + ext
+	}
+
+	return name
+}
+
+func backupExists(name string) bool {
+	for _, extension := range append([]string{""}, compressedExtensions...) {
+		if _, err := os.Lstat(name + extension); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+type backup struct {
+	path    string
+	time    time.Time
+	counter int
+}
+
+// parseBackupSuffix parses the suffix of a rotated file name, its rotation time followed
+// by an optional counter.
+func parseBackupSuffix(suffix string) (time.Time, int, bool) {
+	if len(suffix) < len(backupTimeFormat) {
+		return time.Time{}, 0, false
+	}
+
+	rotated, err := time.Parse(backupTimeFormat, suffix[:len(backupTimeFormat)])
+
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	counter := suffix[len(backupTimeFormat):]
+
+	if counter == "" {
+		return rotated, 0, true
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(counter, "-"))
+
+	if err != nil || n <= 0 || !strings.HasPrefix(counter, "-") {
+		return time.Time{}, 0, false
+	}
+
+	return rotated, n, true
+}
+
+// backups returns the rotated files, the most recent first.
+func (f *RotatingFile) backups() []backup {
+	ext := filepath.Ext(f.options.Filename)
+	prefix := strings.TrimSuffix(filepath.Base(f.options.Filename), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.options.Filename))
+
+	if err != nil {
+		return nil
+	}
+
+	var backups []backup
+
+	for _, entry := range entries {
+		name := trimCompressedExtension(entry.Name())
+
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		rotated, counter, ok := parseBackupSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+
+		if !ok {
+			continue
+		}
+
+		backups = append(backups, backup{path: filepath.Join(filepath.Dir(f.options.Filename), entry.Name()), time: rotated, counter: counter})
+	}
+
+	slices.SortFunc(backups, func(a, b backup) int {
+		if order := b.time.Compare(a.time); order != 0 {
+			return order
+		}
+
+		return b.counter - a.counter
+	})
+
+	return backups
+}
+
+// millBackups compresses and removes the rotated files.
+func (f *RotatingFile) millBackups() {
+	f.millMutex.Lock()
+	defer f.millMutex.Unlock()
+
+	now := f.now()
+
+	for i, backup := range f.backups() {
+		expired := f.options.MaxAge > 0 && now.Sub(backup.time) > f.options.MaxAge
+
+		if (f.options.MaxBackups > 0 && i >= f.options.MaxBackups) || expired {
+			os.Remove(backup.path)
+			continue
+		}
+
+		if compression := f.options.compression(); compression.Enabled() && trimCompressedExtension(backup.path) == backup.path {
+			compressFile(backup.path, compression)
+		}
+	}
+}
+
+func trimCompressedExtension(name string) string {
+	for _, extension := range compressedExtensions {
+		if strings.HasSuffix(name, extension) {
+			return strings.TrimSuffix(name, extension)
+		}
+	}
+
+	return name
+}
+
+// compressFile compresses the file with the compression, replacing it.
+func compressFile(path string, compression Compression) error {
+	source, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer source.Close()
+
+	compressed := path + compression.Extension()
+	destination, err := os.OpenFile(compressed, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	writer, err := compression.NewWriter(destination)
+
+	if err != nil {
+		destination.Close()
+		os.Remove(compressed)
+		return err
+	}
+
+	if _, err := io.Copy(writer, source); err != nil {
+		writer.Close()
+		destination.Close()
+		os.Remove(compressed)
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		destination.Close()
+		os.Remove(compressed)
+		return err
+	}
+
+	if err := destination.Close(); err != nil {
+		os.Remove(compressed)
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,27 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package glogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileLocking is whether lockFile locks the files.
+const fileLocking = true
+
+// lockFile waits for an exclusive advisory lock of the file, shared by the processes
+// opening it. The lock is released by unlockFile or by closing the file.
+func lockFile(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package glogger
+
+import "os"
+
+// fileLocking is whether lockFile locks the files.
+const fileLocking = false
+
+// lockFile doesn't lock: the platform has no flock, and the writes rely on O_APPEND only.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package glogger
+
+import "os"
+
+// reopenSignals is empty: SIGUSR1 is only supported on Unix.
+var reopenSignals []os.Signal
@@ -0,0 +1,270 @@
+package glogger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"gotest.tools/assert"
+)
+
+func newTestRotatingFile(t *testing.T, options RotatingFileOptions, now *time.Time) *RotatingFile {
+	t.Helper()
+
+	file, err := NewRotatingFile(options)
+	assert.NilError(t, err)
+
+	file.now = func() time.Time { return *now }
+	file.opened = *now
+
+	return file
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("File is rotated by size", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		file := newTestRotatingFile(t, RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), MaxSize: 10}, &now)
+
+		file.Write([]byte("12345678\n"))
+		now = now.Add(time.Second)
+		file.Write([]byte("abcdefgh\n"))
+		assert.NilError(t, file.Close())
+
+		current, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+		assert.Equal(t, string(current), "abcdefgh\n")
+
+		rotated, err := os.ReadFile(filepath.Join(dir, "app-2024-01-02T15-04-06.000.log"))
+		assert.NilError(t, err)
+		assert.Equal(t, string(rotated), "12345678\n")
+	})
+
+	t.Run("File is rotated by age", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		file := newTestRotatingFile(t, RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), Interval: time.Hour}, &now)
+
+		file.Write([]byte("first\n"))
+		now = now.Add(30 * time.Minute)
+		file.Write([]byte("second\n"))
+		now = now.Add(30 * time.Minute)
+		file.Write([]byte("third\n"))
+		assert.NilError(t, file.Close())
+
+		assert.Equal(t, len(file.backups()), 1)
+	})
+
+	t.Run("Old backups are compressed and removed", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		options := RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), MaxBackups: 2, MaxAge: 48 * time.Hour, Compress: true}
+		file := newTestRotatingFile(t, options, &now)
+
+		for i := 0; i < 4; i++ {
+			file.Write([]byte("entry\n"))
+			now = now.Add(time.Hour)
+			assert.NilError(t, file.Rotate())
+			file.mill.Wait()
+		}
+
+		assert.NilError(t, file.Close())
+
+		backups := file.backups()
+		assert.Equal(t, len(backups), 2)
+
+		for _, backup := range backups {
+			assert.Assert(t, strings.HasSuffix(backup.path, ".gz"), backup.path)
+		}
+
+		compressed, err := os.Open(backups[0].path)
+		assert.NilError(t, err)
+		defer compressed.Close()
+
+		reader, err := gzip.NewReader(compressed)
+		assert.NilError(t, err)
+
+		content, _ := io.ReadAll(reader)
+		assert.Equal(t, string(content), "entry\n")
+
+		now = now.Add(72 * time.Hour)
+		file.millBackups()
+		assert.Equal(t, len(file.backups()), 0)
+	})
+
+	t.Run("Backups are compressed with the codec", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		options := RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), Compression: Compression{Codec: CodecZstd, Level: 3}}
+		file := newTestRotatingFile(t, options, &now)
+
+		file.Write([]byte("entry\n"))
+		assert.NilError(t, file.Rotate())
+		assert.NilError(t, file.Close())
+
+		backups := file.backups()
+		assert.Equal(t, len(backups), 1)
+		assert.Assert(t, strings.HasSuffix(backups[0].path, ".log.zst"), backups[0].path)
+
+		compressed, err := os.Open(backups[0].path)
+		assert.NilError(t, err)
+		defer compressed.Close()
+
+		reader, err := zstd.NewReader(compressed)
+		assert.NilError(t, err)
+		defer reader.Close()
+
+		content, _ := io.ReadAll(reader)
+		assert.Equal(t, string(content), "entry\n")
+	})
+
+	t.Run("Processes sharing the file follow the rotations", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		options := RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), MaxSize: 20, Lock: true}
+
+		// Each RotatingFile has its own file descriptor, as in different processes.
+		first := newTestRotatingFile(t, options, &now)
+		second := newTestRotatingFile(t, options, &now)
+
+		first.Write([]byte("first 1\n"))
+		second.Write([]byte("second 1\n"))
+
+		// The first file sees the writes of the second one, and rotates.
+		now = now.Add(time.Second)
+		first.Write([]byte("first 2\n"))
+		second.Write([]byte("second 2\n"))
+
+		assert.NilError(t, first.Close())
+		assert.NilError(t, second.Close())
+
+		content, err := os.ReadFile(options.Filename)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), "first 2\nsecond 2\n")
+
+		backups := first.backups()
+		assert.Equal(t, len(backups), 1)
+
+		content, err = os.ReadFile(backups[0].path)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), "first 1\nsecond 1\n")
+	})
+
+	t.Run("Processes sharing the file wait for the rotations", func(t *testing.T) {
+		if !fileLocking {
+			t.Skip("The files are not locked on this platform")
+		}
+
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		options := RotatingFileOptions{Filename: filepath.Join(dir, "app.log"), Lock: true}
+
+		first := newTestRotatingFile(t, options, &now)
+		second := newTestRotatingFile(t, options, &now)
+
+		first.Write([]byte("first 1\n"))
+
+		// The second file writes while the first one rotates, and must wait until the new file is open.
+		written := make(chan struct{})
+
+		first.rename = func(oldpath, newpath string) error {
+			go func() {
+				second.Write([]byte("second 1\n"))
+				close(written)
+			}()
+
+			select {
+			case <-written:
+				t.Error("The second file wrote during the rotation")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			return os.Rename(oldpath, newpath)
+		}
+
+		assert.NilError(t, first.Rotate())
+		<-written
+
+		assert.NilError(t, first.Close())
+		assert.NilError(t, second.Close())
+
+		content, err := os.ReadFile(options.Filename)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), "second 1\n")
+
+		backups := first.backups()
+		assert.Equal(t, len(backups), 1)
+
+		content, err = os.ReadFile(backups[0].path)
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), "first 1\n")
+	})
+
+	t.Run("Rotations in the same millisecond keep every backup", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		file := newTestRotatingFile(t, RotatingFileOptions{Filename: filepath.Join(dir, "app.log")}, &now)
+
+		for _, entry := range []string{"first\n", "second\n", "third\n"} {
+			file.Write([]byte(entry))
+			assert.NilError(t, file.Rotate())
+		}
+
+		assert.NilError(t, file.Close())
+
+		var contents []string
+
+		for _, backup := range file.backups() {
+			content, err := os.ReadFile(backup.path)
+			assert.NilError(t, err)
+			contents = append(contents, string(content))
+		}
+
+		assert.DeepEqual(t, contents, []string{"third\n", "second\n", "first\n"})
+		assert.Equal(t, filepath.Base(file.backups()[0].path), "app-2024-01-02T15-04-05.000-2.log")
+	})
+
+	t.Run("Writes go on when the rotation fails", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		file := newTestRotatingFile(t, RotatingFileOptions{Filename: filepath.Join(dir, "app.log")}, &now)
+
+		file.rename = func(oldpath, newpath string) error {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errors.New("invalid cross-device link")}
+		}
+
+		file.Write([]byte("before\n"))
+		assert.ErrorContains(t, file.Rotate(), "cross-device link")
+
+		_, err := file.Write([]byte("after\n"))
+		assert.NilError(t, err)
+		assert.NilError(t, file.Close())
+
+		content, _ := os.ReadFile(file.options.Filename)
+		assert.Equal(t, string(content), "before\nafter\n")
+	})
+
+	t.Run("File is reopened after an external rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "app.log")
+		file, err := NewRotatingFile(RotatingFileOptions{Filename: filename})
+		assert.NilError(t, err)
+
+		file.Write([]byte("before\n"))
+		assert.NilError(t, os.Rename(filename, filename+".1"))
+		assert.NilError(t, file.Reopen())
+		file.Write([]byte("after\n"))
+		assert.NilError(t, file.Close())
+
+		content, _ := os.ReadFile(filename)
+		assert.Equal(t, string(content), "after\n")
+
+		_, err = file.Write([]byte("closed\n"))
+		assert.Equal(t, err, ErrWriterClosed)
+	})
+}
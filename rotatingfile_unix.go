@@ -0,0 +1,10 @@
+//go:build unix
+
+package glogger
+
+import (
+	"os"
+	"syscall"
+)
+
+var reopenSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,84 @@
+package glogger
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type loggingRoundTripper struct {
+	base http.RoundTripper
+}
+
+// NewRoundTripper returns an http.RoundTripper logging the outgoing requests and their
+// responses with the logger of the request context. The correlation ID of the context
+// logger is propagated to the downstream service in the X-Request-Id header. The calls
+// are recorded with RecordDependency, the 5xx responses being failures.
+// If base is nil, http.DefaultTransport is used.
+func NewRoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &loggingRoundTripper{base: base}
+}
+
+func getContextCorrelationID(logger *logrus.Entry) string {
+	correlationID, _ := logger.Data["correlationId"].(string)
+	return correlationID
+}
+
+func (transport *loggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	logger := Get(r.Context())
+
+	if correlationID := getContextCorrelationID(logger); correlationID != "" && r.Header.Get(correlationIDKey) == "" {
+		r = r.Clone(r.Context())
+		r.Header.Set(correlationIDKey, correlationID)
+	}
+
+	host := Host{Hostname: removePort(r.URL.Host)}
+
+	logger.WithFields(logrus.Fields{
+		"http": HTTP{Request: newRequest(r)},
+		"host": host,
+	}).Trace("Outgoing Request")
+
+	response, err := transport.base.RoundTrip(r)
+	call := DependencyCall{Name: host.Hostname, ResponseTime: time.Since(start).Seconds()}
+
+	if err != nil {
+		call.Error = err.Error()
+		RecordDependency(r.Context(), call)
+
+		logger.WithFields(logrus.Fields{
+			"http": HTTP{
+				Request:  newRequest(r),
+				Response: &Response{ResponseTime: call.ResponseTime},
+			},
+			"host": host,
+		}).WithError(err).Error("Failed Outgoing Request")
+
+		return response, err
+	}
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		call.Error = response.Status
+	}
+
+	RecordDependency(r.Context(), call)
+
+	logger.WithFields(logrus.Fields{
+		"http": HTTP{
+			Request: newRequest(r),
+			Response: &Response{
+				StatusCode:   response.StatusCode,
+				ResponseTime: call.ResponseTime,
+			},
+		},
+		"host": host,
+	}).Info("Completed Outgoing Request")
+
+	return response, err
+}
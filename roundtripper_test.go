@@ -0,0 +1,70 @@
+package glogger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestRoundTripper(t *testing.T) {
+	t.Run("Correlation ID is propagated and response is logged", func(t *testing.T) {
+		var receivedID string
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			receivedID = r.Header.Get("X-Request-Id")
+			rw.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.TraceLevel)
+		ctx := WithLogger(context.Background(), logrus.NewEntry(logger).WithField("correlationId", "request-id"))
+
+		request, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/v1/users?name=Test", nil)
+		client := http.Client{Transport: NewRoundTripper(nil)}
+		response, err := client.Do(request)
+		assert.Assert(t, err == nil, "Unexpected error")
+		response.Body.Close()
+
+		entries := hook.AllEntries()
+
+		assert.Equal(t, receivedID, "request-id")
+		assert.Equal(t, request.Header.Get("X-Request-Id"), "", "Unexpected mutation of the request")
+		assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+		assert.Equal(t, entries[0].Message, "Outgoing Request")
+		assert.Equal(t, entries[1].Message, "Completed Outgoing Request")
+		assert.Equal(t, entries[1].Data["correlationId"], "request-id")
+
+		httpField := entries[1].Data["http"].(HTTP)
+		assert.Equal(t, httpField.Request.Method, "POST")
+		assert.Equal(t, httpField.Request.Path, "/api/v1/users?name=Test")
+		assert.Equal(t, httpField.Response.StatusCode, 201)
+		assert.Equal(t, entries[1].Data["host"].(Host).Hostname, "127.0.0.1")
+	})
+
+	t.Run("Transport errors are logged", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		ctx := WithLogger(context.Background(), logrus.NewEntry(logger))
+		transport := NewRoundTripper(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}))
+
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://downstream/", nil)
+		_, err := transport.RoundTrip(request)
+
+		assert.Assert(t, err != nil, "Expected error")
+		assert.Equal(t, hook.LastEntry().Level, logrus.ErrorLevel)
+		assert.Equal(t, hook.LastEntry().Message, "Failed Outgoing Request")
+	})
+}
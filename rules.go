@@ -0,0 +1,410 @@
+package glogger
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Rule is a field derivation rule: when all its conditions match an entry, its fields
+// are set on the entry.
+type Rule struct {
+	conditions []ruleCondition
+	fields     logrus.Fields
+}
+
+type ruleCondition struct {
+	path     []string
+	operator string
+	value    string
+	pattern  *regexp.Regexp
+}
+
+// RulesHook is a logrus hook deriving fields from the other fields of the entries with
+// declarative rules, one per line, such as:
+//
+//	when http.request.path startsWith "/admin" then set area="admin"
+//	when level == "error" and owner.team == "payments" then set page=true, severity="high"
+//
+// The conditions compare the string value of a field, with a dot-separated path into the
+// objects, or of the level or message, with the ==, !=, startsWith, endsWith, contains and
+// matches (a regular expression) operators. The set values are quoted strings, integers or
+// booleans, set on the top-level fields. Empty lines and lines starting with # are ignored.
+// The rules can be replaced at runtime, without a redeploy. Add the hook after the hooks
+// adding the fields it matches.
+type RulesHook struct {
+	rules atomic.Pointer[[]Rule]
+}
+
+// NewRulesHook returns a RulesHook with the rules.
+func NewRulesHook(rules []Rule) *RulesHook {
+	hook := &RulesHook{}
+	hook.SetRules(rules)
+
+	return hook
+}
+
+// SetRules replaces the rules of the hook.
+func (hook *RulesHook) SetRules(rules []Rule) {
+	hook.rules.Store(&rules)
+}
+
+// Load replaces the rules of the hook with the rules of the file. The rules are kept if
+// the file is invalid.
+func (hook *RulesHook) Load(filename string) error {
+	b, err := os.ReadFile(filename)
+
+	if err != nil {
+		return err
+	}
+
+	rules, err := ParseRules(string(b))
+
+	if err != nil {
+		return err
+	}
+
+	hook.SetRules(rules)
+
+	return nil
+}
+
+// Levels returns the levels of the entries enriched by the hook, which are all of them.
+func (hook *RulesHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sets the fields of the rules matching the entry.
+func (hook *RulesHook) Fire(entry *logrus.Entry) error {
+	var data logrus.Fields
+
+	for _, rule := range *hook.rules.Load() {
+		if !rule.matches(entry) {
+			continue
+		}
+
+		if data == nil {
+			data = make(logrus.Fields, len(entry.Data)+len(rule.fields))
+
+			for key, value := range entry.Data {
+				data[key] = value
+			}
+		}
+
+		for key, value := range rule.fields {
+			data[key] = value
+		}
+	}
+
+	if data != nil {
+		entry.Data = data
+	}
+
+	return nil
+}
+
+func (rule Rule) matches(entry *logrus.Entry) bool {
+	builtins := map[string]interface{}{"message": entry.Message, "level": entry.Level.String()}
+
+	for _, condition := range rule.conditions {
+		value, ok, err := lookupPath(entry.Data, builtins, condition.path)
+
+		if err != nil || !ok {
+			return false
+		}
+
+		if !condition.matches(fmt.Sprint(value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (condition ruleCondition) matches(value string) bool {
+	switch condition.operator {
+	case "==":
+		return value == condition.value
+	case "!=":
+		return value != condition.value
+	case "startsWith":
+		return strings.HasPrefix(value, condition.value)
+	case "endsWith":
+		return strings.HasSuffix(value, condition.value)
+	case "contains":
+		return strings.Contains(value, condition.value)
+	default:
+		return condition.pattern.MatchString(value)
+	}
+}
+
+// ParseRules parses the rules, one per line, as documented by RulesHook.
+func ParseRules(text string) ([]Rule, error) {
+	var rules []Rule
+
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule, err := parseRule(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("rule line %d: %w", i+1, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func parseRule(line string) (Rule, error) {
+	tokens, err := tokenizeRule(line)
+
+	if err != nil {
+		return Rule{}, err
+	}
+
+	parser := &ruleParser{tokens: tokens}
+	rule := Rule{fields: logrus.Fields{}}
+
+	if err := parser.expect("when"); err != nil {
+		return Rule{}, err
+	}
+
+	for {
+		condition, err := parser.condition()
+
+		if err != nil {
+			return Rule{}, err
+		}
+
+		rule.conditions = append(rule.conditions, condition)
+
+		if !parser.accept("and") {
+			break
+		}
+	}
+
+	if err := parser.expect("then"); err != nil {
+		return Rule{}, err
+	}
+
+	if err := parser.expect("set"); err != nil {
+		return Rule{}, err
+	}
+
+	for {
+		key, value, err := parser.assignment()
+
+		if err != nil {
+			return Rule{}, err
+		}
+
+		rule.fields[key] = value
+
+		if !parser.accept(",") {
+			break
+		}
+	}
+
+	if token, ok := parser.next(); ok {
+		return Rule{}, fmt.Errorf("unexpected %q", token.text)
+	}
+
+	return rule, nil
+}
+
+type ruleToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeRule splits a rule into words, quoted strings and the =, ==, != and ,
+// operators.
+func tokenizeRule(line string) ([]ruleToken, error) {
+	var tokens []ruleToken
+
+	for i := 0; i < len(line); {
+		c := line[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"':
+			end := i + 1
+
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated string %s", line[i:])
+			}
+
+			value, err := strconv.Unquote(line[i : end+1])
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", line[i:end+1])
+			}
+
+			tokens = append(tokens, ruleToken{text: value, quoted: true})
+			i = end + 1
+		case c == ',':
+			tokens = append(tokens, ruleToken{text: ","})
+			i++
+		case c == '=' || c == '!':
+			if strings.HasPrefix(line[i:], "==") || strings.HasPrefix(line[i:], "!=") {
+				tokens = append(tokens, ruleToken{text: line[i : i+2]})
+				i += 2
+			} else if c == '=' {
+				tokens = append(tokens, ruleToken{text: "="})
+				i++
+			} else {
+				return nil, fmt.Errorf("unexpected %q", c)
+			}
+		default:
+			end := i
+
+			for end < len(line) && isRuleWordChar(rune(line[end])) {
+				end++
+			}
+
+			if end == i {
+				return nil, fmt.Errorf("unexpected %q", c)
+			}
+
+			tokens = append(tokens, ruleToken{text: line[i:end]})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
+
+func isRuleWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-@", r)
+}
+
+type ruleParser struct {
+	tokens []ruleToken
+}
+
+func (parser *ruleParser) next() (ruleToken, bool) {
+	if len(parser.tokens) == 0 {
+		return ruleToken{}, false
+	}
+
+	token := parser.tokens[0]
+	parser.tokens = parser.tokens[1:]
+
+	return token, true
+}
+
+func (parser *ruleParser) accept(keyword string) bool {
+	if len(parser.tokens) > 0 && !parser.tokens[0].quoted && parser.tokens[0].text == keyword {
+		parser.tokens = parser.tokens[1:]
+		return true
+	}
+
+	return false
+}
+
+func (parser *ruleParser) expect(keyword string) error {
+	if !parser.accept(keyword) {
+		return fmt.Errorf("expected %q", keyword)
+	}
+
+	return nil
+}
+
+func (parser *ruleParser) word() (string, error) {
+	token, ok := parser.next()
+
+	if !ok || token.quoted || !isRuleWordChar(rune(token.text[0])) {
+		return "", fmt.Errorf("expected a field name")
+	}
+
+	return token.text, nil
+}
+
+func (parser *ruleParser) condition() (ruleCondition, error) {
+	path, err := parser.word()
+
+	if err != nil {
+		return ruleCondition{}, err
+	}
+
+	operator, ok := parser.next()
+
+	if !ok || operator.quoted {
+		return ruleCondition{}, fmt.Errorf("expected an operator after %s", path)
+	}
+
+	value, ok := parser.next()
+
+	if !ok {
+		return ruleCondition{}, fmt.Errorf("expected a value after %s", operator.text)
+	}
+
+	condition := ruleCondition{path: strings.Split(path, "."), operator: operator.text, value: value.text}
+
+	switch operator.text {
+	case "==", "!=", "startsWith", "endsWith", "contains":
+	case "matches":
+		if condition.pattern, err = regexp.Compile(value.text); err != nil {
+			return ruleCondition{}, err
+		}
+	default:
+		return ruleCondition{}, fmt.Errorf("unknown operator %q", operator.text)
+	}
+
+	return condition, nil
+}
+
+func (parser *ruleParser) assignment() (string, interface{}, error) {
+	key, err := parser.word()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if strings.Contains(key, ".") {
+		return "", nil, fmt.Errorf("cannot set the nested field %s", key)
+	}
+
+	if err := parser.expect("="); err != nil {
+		return "", nil, err
+	}
+
+	token, ok := parser.next()
+
+	if !ok {
+		return "", nil, fmt.Errorf("expected a value for %s", key)
+	}
+
+	if token.quoted {
+		return key, token.text, nil
+	}
+
+	if token.text == "true" || token.text == "false" {
+		return key, token.text == "true", nil
+	}
+
+	if value, err := strconv.ParseInt(token.text, 10, 64); err == nil {
+		return key, value, nil
+	}
+
+	return "", nil, fmt.Errorf("invalid value %s for %s, strings must be quoted", token.text, key)
+}
@@ -0,0 +1,83 @@
+package glogger
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(`
+# Areas
+when http.request.path startsWith "/admin" then set area="admin"
+when level == "error" and owner.team == "payments" then set page=true, priority=1
+`)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 2)
+	assert.DeepEqual(t, rules[1].fields, logrus.Fields{"page": true, "priority": int64(1)})
+
+	invalid := []string{
+		`when path == "/" set area="root"`,
+		`when path is "/" then set area="root"`,
+		`when path matches "(" then set area="root"`,
+		`when path == "/" then set area=root`,
+		`when path == "/" then set http.area="root"`,
+		`when path == "/ then set area="root"`,
+		`when path == "/" then set area="root" extra`,
+	}
+
+	for _, text := range invalid {
+		_, err := ParseRules(text)
+		assert.Assert(t, err != nil, "Expected error for %s", text)
+	}
+}
+
+func TestRulesHook(t *testing.T) {
+	rules, err := ParseRules(`
+when http.request.path startsWith "/admin" then set area="admin"
+when http.response.statusCode == "503" and message != "Incoming Request" then set dependency="down"
+when user matches "^bot-" then set bot=true
+`)
+	assert.NilError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	rulesHook := NewRulesHook(rules)
+	logger.AddHook(rulesHook)
+	hook := test.NewLocal(logger)
+
+	logger.WithField("http", HTTP{
+		Request:  &Request{Path: "/admin/users"},
+		Response: &Response{StatusCode: http.StatusServiceUnavailable},
+	}).Info("Completed Request")
+
+	data := hook.LastEntry().Data
+	assert.Equal(t, data["area"], "admin")
+	assert.Equal(t, data["dependency"], "down")
+	assert.Equal(t, data["bot"], nil)
+
+	logger.WithField("user", "bot-42").Info("Entry")
+	assert.Equal(t, hook.LastEntry().Data["bot"], true)
+
+	filename := filepath.Join(t.TempDir(), "rules")
+	assert.NilError(t, os.WriteFile(filename, []byte(`when user == "alice" then set vip=true`), 0o644))
+	assert.NilError(t, rulesHook.Load(filename))
+
+	logger.WithField("user", "bot-42").Info("Entry")
+	assert.Equal(t, hook.LastEntry().Data["bot"], nil)
+
+	logger.WithField("user", "alice").Info("Entry")
+	assert.Equal(t, hook.LastEntry().Data["vip"], true)
+
+	assert.NilError(t, os.WriteFile(filename, []byte(`when user`), 0o644))
+	assert.Assert(t, rulesHook.Load(filename) != nil, "Expected error")
+
+	logger.WithField("user", "alice").Info("Entry")
+	assert.Equal(t, hook.LastEntry().Data["vip"], true)
+}
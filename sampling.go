@@ -0,0 +1,171 @@
+package glogger
+
+import (
+	"container/list"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const maxSamplingBuckets = 10000
+
+// SamplingOptions is the struct of options to configure the sampling of the request entries.
+// Requests are sampled when they start: the entries of a request are either all logged or
+// all dropped, except the completed request entries at warning level or above, which are
+// always logged.
+type SamplingOptions struct {
+	// Rate is the fraction of requests logged, between 0 and 1. Zero logs every request.
+	Rate float64
+	// Limit is the maximum number of requests logged per second and per route pattern, or
+	// per path if the router didn't match the route before the middleware, with bursts of
+	// up to Burst requests. Zero disables the limit.
+	Limit float64
+	// Burst is the number of requests logged in a burst above Limit. Defaults to Limit, at least 1.
+	Burst int
+}
+
+// Sampling struct contains items of request sampling info log. Each sampled request entry
+// stands for (1 + Dropped) / Rate requests.
+type Sampling struct {
+	Rate    float64 `json:"rate,omitempty"`
+	Dropped int64   `json:"dropped,omitempty"`
+}
+
+type tokenBucket struct {
+	key     string
+	tokens  float64
+	last    time.Time
+	dropped int64
+}
+
+type sampler struct {
+	rate    float64
+	limit   float64
+	burst   float64
+	random  func() float64
+	now     func() time.Time
+	mutex   sync.Mutex
+	buckets map[string]*list.Element
+	// used are the buckets ordered by their last use, the least recently used first.
+	used *list.List
+}
+
+func (options SamplingOptions) newSampler() *sampler {
+	rate := options.Rate
+
+	if rate <= 0 || rate >= 1 {
+		rate = 1
+	}
+
+	if rate == 1 && options.Limit <= 0 {
+		return nil
+	}
+
+	burst := float64(options.Burst)
+
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(options.Limit))
+	}
+
+	return &sampler{
+		rate:    rate,
+		limit:   options.Limit,
+		burst:   burst,
+		random:  rand.Float64,
+		now:     time.Now,
+		buckets: map[string]*list.Element{},
+		used:    list.New(),
+	}
+}
+
+// take takes a token from the bucket of the key, returning whether the request is
+// allowed and the number of requests dropped since the last allowed one. The least
+// recently used bucket is evicted past maxSamplingBuckets.
+func (s *sampler) take(key string) (bool, int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	element, ok := s.buckets[key]
+
+	if ok {
+		s.used.MoveToBack(element)
+	} else {
+		if len(s.buckets) >= maxSamplingBuckets {
+			delete(s.buckets, s.used.Remove(s.used.Front()).(*tokenBucket).key)
+		}
+
+		element = s.used.PushBack(&tokenBucket{key: key, tokens: s.burst, last: now})
+		s.buckets[key] = element
+	}
+
+	bucket := element.Value.(*tokenBucket)
+
+	bucket.tokens = math.Min(s.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*s.limit)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		bucket.dropped++
+		return false, 0
+	}
+
+	bucket.tokens--
+	dropped := bucket.dropped
+	bucket.dropped = 0
+
+	return true, dropped
+}
+
+// sample returns whether the entries of the request are logged and, if the request is
+// sampled, the sampling info to log. The requests are limited per route pattern, or per
+// path if the route is not matched yet.
+func (s *sampler) sample(r *http.Request, routePattern func(r *http.Request) string) (bool, *Sampling) {
+	if s == nil {
+		return true, nil
+	}
+
+	if s.rate < 1 && s.random() >= s.rate {
+		return false, nil
+	}
+
+	info := &Sampling{}
+
+	if s.rate < 1 {
+		info.Rate = s.rate
+	}
+
+	if s.limit > 0 {
+		key := routePattern(r)
+
+		if key == "" {
+			key = r.URL.Path
+		}
+
+		allowed, dropped := s.take(key)
+
+		if !allowed {
+			return false, nil
+		}
+
+		info.Dropped = dropped
+	}
+
+	if *info == (Sampling{}) {
+		return true, nil
+	}
+
+	return true, info
+}
+
+// withSampling adds the sampling info to the fields of a sampled request entry.
+func withSampling(fields logrus.Fields, sampling *Sampling) logrus.Fields {
+	if sampling != nil {
+		fields["sampling"] = sampling
+	}
+
+	return fields
+}
@@ -0,0 +1,145 @@
+package glogger
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestSampler(t *testing.T) {
+	t.Run("Sampling is disabled by default", func(t *testing.T) {
+		assert.Assert(t, SamplingOptions{}.newSampler() == nil, "Unexpected sampler")
+		assert.Assert(t, SamplingOptions{Rate: 1}.newSampler() == nil, "Unexpected sampler")
+	})
+
+	t.Run("Requests are sampled by rate", func(t *testing.T) {
+		s := SamplingOptions{Rate: 0.25}.newSampler()
+		random := 0.0
+		s.random = func() float64 { return random }
+
+		sampled, info := s.sample(httptest.NewRequest(http.MethodGet, "/", nil), noRoutePattern)
+		assert.Assert(t, sampled, "Expected sampled request")
+		assert.Equal(t, *info, Sampling{Rate: 0.25})
+
+		random = 0.5
+		sampled, _ = s.sample(httptest.NewRequest(http.MethodGet, "/", nil), noRoutePattern)
+		assert.Assert(t, !sampled, "Expected dropped request")
+	})
+
+	t.Run("Requests are rate limited per path", func(t *testing.T) {
+		s := SamplingOptions{Limit: 1}.newSampler()
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		sample := func(path string) (bool, *Sampling) {
+			return s.sample(httptest.NewRequest(http.MethodGet, path, nil), noRoutePattern)
+		}
+
+		sampled, info := sample("/users")
+		assert.Assert(t, sampled && info == nil, "Expected sampled request")
+
+		sampled, _ = sample("/users")
+		assert.Assert(t, !sampled, "Expected dropped request")
+		sampled, _ = sample("/users")
+		assert.Assert(t, !sampled, "Expected dropped request")
+
+		sampled, _ = sample("/orders")
+		assert.Assert(t, sampled, "Expected sampled request on other path")
+
+		now = now.Add(time.Second)
+		sampled, info = sample("/users")
+		assert.Assert(t, sampled, "Expected sampled request")
+		assert.Equal(t, *info, Sampling{Dropped: 2})
+	})
+
+	t.Run("Requests are rate limited per route pattern", func(t *testing.T) {
+		s := SamplingOptions{Limit: 1}.newSampler()
+		s.now = time.Now
+
+		routePattern := func(r *http.Request) string {
+			if strings.HasPrefix(r.URL.Path, "/users/") {
+				return "/users/{id}"
+			}
+
+			return ""
+		}
+
+		sampled, _ := s.sample(httptest.NewRequest(http.MethodGet, "/users/1", nil), routePattern)
+		assert.Assert(t, sampled, "Expected sampled request")
+		sampled, _ = s.sample(httptest.NewRequest(http.MethodGet, "/users/2", nil), routePattern)
+		assert.Assert(t, !sampled, "Expected dropped request on same route")
+		sampled, _ = s.sample(httptest.NewRequest(http.MethodGet, "/orders", nil), routePattern)
+		assert.Assert(t, sampled, "Expected sampled request on other path")
+		assert.Equal(t, len(s.buckets), 2)
+	})
+
+	t.Run("The least recently used buckets are evicted", func(t *testing.T) {
+		s := SamplingOptions{Limit: 1}.newSampler()
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		allowed, _ := s.take("/users")
+		assert.Assert(t, allowed, "Expected allowed request")
+
+		for i := 0; i < maxSamplingBuckets-1; i++ {
+			s.take(fmt.Sprint(i))
+		}
+
+		allowed, _ = s.take("/users")
+		assert.Assert(t, !allowed, "Expected dropped request")
+		allowed, _ = s.take("/orders")
+		assert.Assert(t, allowed, "Expected allowed request")
+		assert.Equal(t, len(s.buckets), maxSamplingBuckets)
+
+		allowed, _ = s.take("/users")
+		assert.Assert(t, !allowed, "Expected the bucket of the recent path to be kept")
+		allowed, _ = s.take("0")
+		assert.Assert(t, allowed, "Expected the bucket of the oldest path to be evicted")
+	})
+}
+
+func noRoutePattern(r *http.Request) string {
+	return ""
+}
+
+func TestSamplingMiddleware(t *testing.T) {
+	invoke := func(status int) []*logrus.Entry {
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.TraceLevel)
+
+		handler := LoggingMiddlewareWithOptions(logger, MiddlewareOptions{Sampling: SamplingOptions{Limit: 1}})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(status)
+		}))
+
+		for i := 0; i < 3; i++ {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, defaultRequestPath, nil))
+		}
+
+		return hook.AllEntries()
+	}
+
+	t.Run("Dropped requests are not logged", func(t *testing.T) {
+		entries := invoke(http.StatusOK)
+
+		assert.Equal(t, len(entries), 2, "Unexpected entries length.")
+		assert.Equal(t, entries[0].Message, "Incoming Request")
+		assert.Equal(t, entries[1].Message, "Completed Request")
+	})
+
+	t.Run("Warnings and errors are always logged", func(t *testing.T) {
+		entries := invoke(http.StatusInternalServerError)
+
+		assert.Equal(t, len(entries), 4, "Unexpected entries length.")
+		assert.Equal(t, entries[2].Message, "Completed Request")
+		assert.Equal(t, entries[3].Message, "Completed Request")
+		_, ok := entries[3].Data["sampling"]
+		assert.Assert(t, !ok, "Unexpected sampling field on a request not sampled")
+	})
+}
@@ -0,0 +1,128 @@
+package glogger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretSource returns a secret, such as the token of a sink. Read the secrets from
+// Vault or a KMS with a function calling their client, and cache them with CachedSecret.
+type SecretSource func(ctx context.Context) (string, error)
+
+// StaticSecret returns the SecretSource of a fixed secret.
+func StaticSecret(secret string) SecretSource {
+	return func(ctx context.Context) (string, error) {
+		return secret, nil
+	}
+}
+
+// FileSecret returns the SecretSource reading the secret from a file, such as a secret
+// mounted by Kubernetes or rendered by the Vault agent, trimmed of its surrounding spaces.
+// The file is read on every call, wrap it with CachedSecret to read it periodically.
+func FileSecret(filename string) SecretSource {
+	return func(ctx context.Context) (string, error) {
+		b, err := os.ReadFile(filename)
+
+		if err != nil {
+			return "", err
+		}
+
+		return string(bytes.TrimSpace(b)), nil
+	}
+}
+
+// EnvFileSecret returns the SecretSource reading the variable from an env file of
+// KEY=VALUE lines, with optional quotes around the values. Blank lines and lines
+// starting with # are ignored.
+func EnvFileSecret(filename string, name string) SecretSource {
+	return func(ctx context.Context) (string, error) {
+		b, err := os.ReadFile(filename)
+
+		if err != nil {
+			return "", err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+
+			if !ok || strings.TrimSpace(key) != name {
+				continue
+			}
+
+			value = strings.TrimSpace(value)
+
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				value = value[1 : len(value)-1]
+			}
+
+			return value, nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+
+		return "", fmt.Errorf("%s: %s is not set", filename, name)
+	}
+}
+
+// CachedSecret returns the SecretSource caching the secret of the source for the refresh
+// interval, so that the secret is rotated without a request to the secret store for
+// each use. When the refresh fails, the previous secret is kept for another interval.
+func CachedSecret(source SecretSource, refresh time.Duration) SecretSource {
+	cache := &secretCache{source: source, refresh: refresh, now: time.Now}
+
+	return cache.secret
+}
+
+type secretCache struct {
+	source  SecretSource
+	refresh time.Duration
+	now     func() time.Time
+	mutex   sync.Mutex
+	value   string
+	fetched time.Time
+	valid   bool
+}
+
+func (cache *secretCache) secret(ctx context.Context) (string, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	current := cache.now()
+
+	if cache.valid && current.Sub(cache.fetched) < cache.refresh {
+		return cache.value, nil
+	}
+
+	value, err := cache.source(ctx)
+
+	if err != nil {
+		if cache.valid {
+			fmt.Fprintf(os.Stderr, "Failed to refresh a secret, %v\n", err)
+			cache.fetched = current
+
+			return cache.value, nil
+		}
+
+		return "", err
+	}
+
+	cache.value, cache.fetched, cache.valid = value, current, true
+
+	return value, nil
+}
@@ -0,0 +1,85 @@
+package glogger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestSecretSources(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	t.Run("File", func(t *testing.T) {
+		filename := filepath.Join(dir, "token")
+		assert.NilError(t, os.WriteFile(filename, []byte("secret\n"), 0o600))
+
+		secret, err := FileSecret(filename)(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, secret, "secret")
+
+		_, err = FileSecret(filepath.Join(dir, "missing"))(ctx)
+		assert.Assert(t, errors.Is(err, os.ErrNotExist), err)
+	})
+
+	t.Run("Env file", func(t *testing.T) {
+		filename := filepath.Join(dir, "sinks.env")
+		assert.NilError(t, os.WriteFile(filename, []byte("# Sinks\nOTLP_TOKEN=plain\nexport SPLUNK_TOKEN=\"quoted value\"\n\n"), 0o600))
+
+		secret, err := EnvFileSecret(filename, "OTLP_TOKEN")(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, secret, "plain")
+
+		secret, err = EnvFileSecret(filename, "SPLUNK_TOKEN")(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, secret, "quoted value")
+
+		_, err = EnvFileSecret(filename, "KAFKA_PASSWORD")(ctx)
+		assert.ErrorContains(t, err, "KAFKA_PASSWORD is not set")
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		values := []string{"first", "second"}
+		var failure error
+
+		cache := &secretCache{source: func(ctx context.Context) (string, error) {
+			if failure != nil {
+				return "", failure
+			}
+
+			value := values[0]
+			values = values[1:]
+
+			return value, nil
+		}, refresh: time.Minute}
+
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		secret, _ := cache.secret(ctx)
+		assert.Equal(t, secret, "first")
+
+		now = now.Add(30 * time.Second)
+		secret, _ = cache.secret(ctx)
+		assert.Equal(t, secret, "first")
+
+		now = now.Add(30 * time.Second)
+		secret, _ = cache.secret(ctx)
+		assert.Equal(t, secret, "second")
+
+		// The previous secret is kept while the source fails.
+		failure = errors.New("vault sealed")
+		now = now.Add(time.Minute)
+		secret, err := cache.secret(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, secret, "second")
+
+		_, err = CachedSecret(cache.source, time.Minute)(ctx)
+		assert.ErrorContains(t, err, "vault sealed")
+	})
+}
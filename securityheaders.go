@@ -0,0 +1,28 @@
+package glogger
+
+import "net/http"
+
+// DefaultSecurityHeaders are the security response headers commonly audited.
+var DefaultSecurityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"X-Content-Type-Options",
+	"X-Frame-Options",
+	"Referrer-Policy",
+}
+
+// presentSecurityHeaders returns the canonical names of the audited headers which are
+// present in the response header.
+func presentSecurityHeaders(audited []string, header http.Header) []string {
+	var present []string
+
+	for _, name := range audited {
+		name = http.CanonicalHeaderKey(name)
+
+		if len(header.Values(name)) > 0 {
+			present = append(present, name)
+		}
+	}
+
+	return present
+}
@@ -0,0 +1,81 @@
+package glogger
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+const requestSeqKey = "requestSeq"
+
+type requestSequenceKey struct{}
+
+// withRequestSequence returns a new context counting the entries logged for the request.
+func withRequestSequence(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestSequenceKey{}, new(atomic.Int64))
+}
+
+// RequestSequenceHook is a logrus hook adding the requestSeq field to the entries logged
+// with the request scoped logger of the middleware: the entries of a request are numbered
+// from 1 in logging order, so that their order can be restored by correlationId and
+// requestSeq when they are written by concurrent outputs or share the same timestamp.
+type RequestSequenceHook struct{}
+
+// Levels returns the levels of the entries numbered by the hook, which are all of them.
+func (hook RequestSequenceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the requestSeq field to the entry, if it is logged for a request.
+func (hook RequestSequenceHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	sequence, ok := entry.Context.Value(requestSequenceKey{}).(*atomic.Int64)
+
+	if !ok {
+		return nil
+	}
+
+	entry.Data = withField(entry.Data, requestSeqKey, sequence.Add(1))
+
+	return nil
+}
+
+const seqKey = "seq"
+
+// seq is the sequence number of the last entry of the process.
+var seq atomic.Int64
+
+// SequenceHook is a logrus hook adding the seq field to every entry: a sequence number
+// increasing across the loggers of the process, so that consumers can detect dropped
+// entries and restore their order when timestamps collide.
+type SequenceHook struct{}
+
+// Levels returns the levels of the entries numbered by the hook, which are all of them.
+func (hook SequenceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the seq field to the entry.
+func (hook SequenceHook) Fire(entry *logrus.Entry) error {
+	entry.Data = withField(entry.Data, seqKey, seq.Add(1))
+
+	return nil
+}
+
+// withField returns a copy of the fields with the field added. The fields map of an entry
+// being logged is shared with the entry it derives from, and must not be modified by hooks.
+func withField(fields logrus.Fields, key string, value interface{}) logrus.Fields {
+	data := make(logrus.Fields, len(fields)+1)
+
+	for k, v := range fields {
+		data[k] = v
+	}
+
+	data[key] = value
+
+	return data
+}
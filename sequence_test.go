@@ -0,0 +1,68 @@
+package glogger
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestRequestSequenceHook(t *testing.T) {
+	t.Run("Entries of a request are numbered in order", func(t *testing.T) {
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		logger.SetLevel(logrus.TraceLevel)
+		logger.AddHook(RequestSequenceHook{})
+		hook := test.NewLocal(logger)
+
+		next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			log := Get(r.Context())
+			log.Info("First")
+			log.Info("Second")
+		})
+
+		testMiddlewareInvocation(next, "", logger, defaultRequestPath)
+		testMiddlewareInvocation(next, "", logger, defaultRequestPath)
+
+		entries := hook.AllEntries()
+		assert.Equal(t, len(entries), 8, "Unexpected entries length.")
+
+		for i, entry := range entries {
+			assert.Equal(t, entry.Data[requestSeqKey], int64(i%4+1))
+		}
+	})
+
+	t.Run("Entries outside of requests are not numbered", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		logger.AddHook(RequestSequenceHook{})
+
+		logger.Info("Server started")
+
+		_, ok := hook.LastEntry().Data[requestSeqKey]
+		assert.Assert(t, !ok, "Unexpected requestSeq field")
+	})
+}
+
+func TestSequenceHook(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(SequenceHook{})
+	hook := test.NewLocal(logger)
+
+	entry := logger.WithField("key", "value")
+	entry.Info("First")
+	entry.Info("Second")
+	logger.Info("Third")
+
+	entries := hook.AllEntries()
+	first := entries[0].Data[seqKey].(int64)
+
+	assert.Equal(t, entries[1].Data[seqKey], first+1)
+	assert.Equal(t, entries[2].Data[seqKey], first+2)
+
+	_, ok := entry.Data[seqKey]
+	assert.Assert(t, !ok, "Unexpected seq field in the shared fields")
+}
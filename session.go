@@ -0,0 +1,45 @@
+package glogger
+
+import "net/http"
+
+const sessionKey = "session"
+
+// Session struct contains items of session info log.
+type Session struct {
+	ID string `json:"id,omitempty"`
+}
+
+// SessionIDFunc returns the session ID of the request, or an empty string.
+type SessionIDFunc func(r *http.Request) string
+
+// SessionCookie returns a SessionIDFunc reading the session ID from the named cookie.
+func SessionCookie(name string) SessionIDFunc {
+	return func(r *http.Request) string {
+		cookie, err := r.Cookie(name)
+
+		if err != nil {
+			return ""
+		}
+
+		return cookie.Value
+	}
+}
+
+// SessionHeader returns a SessionIDFunc reading the session ID from the named header.
+func SessionHeader(name string) SessionIDFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// HashSessionID returns a SessionIDFunc logging an HMAC-SHA256 of the session ID keyed
+// by the salt, so that the sessions can be reconstructed without logging credentials.
+func HashSessionID(salt string, sessionID SessionIDFunc) SessionIDFunc {
+	return func(r *http.Request) string {
+		if id := sessionID(r); id != "" {
+			return keyedHash(salt, id)
+		}
+
+		return ""
+	}
+}
@@ -0,0 +1,53 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestSessionID(t *testing.T) {
+	t.Run("Session ID is added to every entry of the request", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{SessionID: SessionCookie("session")}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			Get(r.Context()).Info("Handling Request")
+		}))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		for _, entry := range hook.AllEntries() {
+			assert.Equal(t, entry.Data["session"], Session{ID: "abc"}, "Unexpected session for %s", entry.Message)
+		}
+	})
+
+	t.Run("Session field is omitted without session", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		options := MiddlewareOptions{SessionID: SessionHeader("X-Session-Id")}
+
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		_, ok := hook.LastEntry().Data["session"]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("Session ID is hashed", func(t *testing.T) {
+		sessionID := HashSessionID("salt", SessionHeader("X-Session-Id"))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.Equal(t, sessionID(request), "")
+
+		request.Header.Set("X-Session-Id", "abc")
+		hashed := sessionID(request)
+
+		assert.Equal(t, len(hashed), 32)
+		assert.Equal(t, hashed, sessionID(request))
+		assert.Assert(t, hashed != "abc")
+	})
+}
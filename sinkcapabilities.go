@@ -0,0 +1,335 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const truncatedKey = "truncated"
+
+// SinkCapabilities declares the constraints of the sink of an Output, such as a syslog
+// server limiting the message size or an index without nested objects, from which the
+// formatter variant of the output is derived, so that every output needs no formatter
+// configured of its own.
+type SinkCapabilities struct {
+	// FlatKeys flattens the nested objects into dot-separated keys, such as
+	// "http.request.path", for the sinks which don't index the nested JSON objects.
+	FlatKeys bool
+	// MaxLineSize is the maximum size of an entry, in bytes, such as 1024 for the legacy
+	// syslog servers or 16 KiB for the container runtimes splitting the longer lines.
+	// The longest string values of the longer JSON entries are truncated, with an
+	// ellipsis, until the entry fits, then the largest fields are dropped if it still
+	// doesn't, and the truncated field set to true. Zero disables the limit.
+	MaxLineSize int
+}
+
+// formatter returns the formatter of the sink: the formatter itself, or a variant
+// complying with the capabilities.
+func (capabilities SinkCapabilities) formatter(formatter logrus.Formatter) logrus.Formatter {
+	if !capabilities.FlatKeys && capabilities.MaxLineSize <= 0 {
+		return formatter
+	}
+
+	return &sinkFormatter{formatter: formatter, capabilities: capabilities}
+}
+
+type sinkFormatter struct {
+	formatter    logrus.Formatter
+	capabilities SinkCapabilities
+}
+
+func (formatter *sinkFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if formatter.capabilities.FlatKeys {
+		flat, err := flattenEntry(entry)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entry = flat
+	}
+
+	serialized, err := formatter.formatter.Format(entry)
+
+	if err != nil || formatter.capabilities.MaxLineSize <= 0 || len(serialized) <= formatter.capabilities.MaxLineSize {
+		return serialized, err
+	}
+
+	return truncateEntry(serialized, formatter.capabilities.MaxLineSize), nil
+}
+
+// flattenEntry returns a copy of the entry whose fields encoded as JSON objects are
+// flattened into dot-separated keys.
+func flattenEntry(entry *logrus.Entry) (*logrus.Entry, error) {
+	data := make(logrus.Fields, len(entry.Data))
+
+	var flatten func(prefix string, key string, value interface{}) error
+
+	flatten = func(prefix string, key string, value interface{}) error {
+		object, err := jsonObjectOf(value)
+
+		if err != nil {
+			return err
+		}
+
+		if object == nil {
+			data[prefix+key] = value
+			return nil
+		}
+
+		for nestedKey, nestedValue := range object {
+			if err := flatten(prefix+key+".", nestedKey, nestedValue); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for key, value := range entry.Data {
+		if err := flatten("", key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	flat := *entry
+	flat.Data = data
+
+	return &flat, nil
+}
+
+// truncateEntry halves the longest string value of the JSON entry, in the objects and
+// arrays at any depth, until it fits within size bytes, newline included, then drops the
+// largest fields if the entry still doesn't fit. The other values are kept as formatted,
+// in the order of the formatter. The entries which are not JSON objects are returned as is.
+func truncateEntry(serialized []byte, size int) []byte {
+	node, err := decodeJSONNode(bytes.TrimSuffix(serialized, []byte("\n")))
+
+	if err != nil {
+		return serialized
+	}
+
+	object, ok := node.(*jsonNodeObject)
+
+	if !ok {
+		return serialized
+	}
+
+	// The formatter escaped the HTML characters unless one of them is in the entry.
+	escapeHTML := !bytes.ContainsAny(serialized, "<>&")
+	object.set(truncatedKey, json.RawMessage("true"))
+	length := len(appendJSONNode(nil, object, escapeHTML)) + 1
+
+	for length > size {
+		longest := longestString(object)
+
+		if longest == nil || utf8.RuneCountInString(longest.value) <= minTruncatedValue {
+			break
+		}
+
+		runes := []rune(longest.value)
+		longest.value = string(runes[:len(runes)/2]) + "…"
+
+		raw := appendJSONString(nil, longest.value, escapeHTML)
+		length += len(raw) - len(longest.raw)
+		longest.raw = raw
+	}
+
+	for length > size {
+		largest := -1
+
+		for i, field := range object.fields {
+			if field.key != truncatedKey && (largest < 0 || field.size(escapeHTML) > object.fields[largest].size(escapeHTML)) {
+				largest = i
+			}
+		}
+
+		if largest < 0 {
+			break
+		}
+
+		length -= object.fields[largest].size(escapeHTML) + 1
+		object.fields = append(object.fields[:largest], object.fields[largest+1:]...)
+	}
+
+	return append(appendJSONNode(nil, object, escapeHTML), '\n')
+}
+
+// The JSON nodes are the decoded entry, preserving the order of the keys and the
+// formatting of the values which are not truncated.
+type (
+	jsonNodeObject struct {
+		fields []jsonNodeField
+	}
+
+	jsonNodeField struct {
+		key   string
+		value interface{}
+	}
+
+	jsonNodeArray []interface{}
+
+	jsonNodeString struct {
+		raw   []byte
+		value string
+	}
+)
+
+// decodeJSONNode decodes the JSON value into nodes, the values other than the objects,
+// arrays and strings being kept as json.RawMessage.
+func decodeJSONNode(raw []byte) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+
+	if len(raw) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	switch raw[0] {
+	case '{', '[':
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		object := &jsonNodeObject{}
+		var array jsonNodeArray
+
+		for decoder.More() {
+			var key string
+
+			if raw[0] == '{' {
+				token, err := decoder.Token()
+
+				if err != nil {
+					return nil, err
+				}
+
+				key, _ = token.(string)
+			}
+
+			var value json.RawMessage
+
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+
+			node, err := decodeJSONNode(value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if raw[0] == '{' {
+				object.fields = append(object.fields, jsonNodeField{key: key, value: node})
+			} else {
+				array = append(array, node)
+			}
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		if raw[0] == '{' {
+			return object, nil
+		}
+
+		return array, nil
+	case '"':
+		var value string
+
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+
+		return &jsonNodeString{raw: raw, value: value}, nil
+	default:
+		if !json.Valid(raw) {
+			return nil, errors.New("invalid JSON value")
+		}
+
+		return json.RawMessage(raw), nil
+	}
+}
+
+// appendJSONNode appends the JSON encoding of the node.
+func appendJSONNode(b []byte, node interface{}, escapeHTML bool) []byte {
+	switch node := node.(type) {
+	case *jsonNodeObject:
+		object := newJSONObject(b, escapeHTML)
+
+		for _, field := range node.fields {
+			object.key(field.key)
+			object.b = appendJSONNode(object.b, field.value, escapeHTML)
+		}
+
+		return object.close()
+	case jsonNodeArray:
+		b = append(b, '[')
+
+		for i, value := range node {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			b = appendJSONNode(b, value, escapeHTML)
+		}
+
+		return append(b, ']')
+	case *jsonNodeString:
+		return append(b, node.raw...)
+	case json.RawMessage:
+		return append(b, node...)
+	}
+
+	return b
+}
+
+// set sets the value of the key, appended if the object doesn't have it.
+func (object *jsonNodeObject) set(key string, value interface{}) {
+	for i := range object.fields {
+		if object.fields[i].key == key {
+			object.fields[i].value = value
+			return
+		}
+	}
+
+	object.fields = append(object.fields, jsonNodeField{key: key, value: value})
+}
+
+// size returns the size of the field encoded as a key and value.
+func (field jsonNodeField) size(escapeHTML bool) int {
+	return len(appendJSONString(nil, field.key, escapeHTML)) + 1 + len(appendJSONNode(nil, field.value, escapeHTML))
+}
+
+// longestString returns the longest string value of the node, at any depth.
+func longestString(node interface{}) *jsonNodeString {
+	var longest *jsonNodeString
+
+	longer := func(candidate *jsonNodeString) {
+		if candidate != nil && (longest == nil || len(candidate.value) > len(longest.value)) {
+			longest = candidate
+		}
+	}
+
+	switch node := node.(type) {
+	case *jsonNodeString:
+		return node
+	case *jsonNodeObject:
+		for _, field := range node.fields {
+			longer(longestString(field.value))
+		}
+	case jsonNodeArray:
+		for _, value := range node {
+			longer(longestString(value))
+		}
+	}
+
+	return longest
+}
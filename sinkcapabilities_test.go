@@ -0,0 +1,102 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestSinkCapabilities(t *testing.T) {
+	var nested, flat, limited bytes.Buffer
+
+	logger, err := Init(InitOptions{
+		Outputs: []Output{
+			{Writer: &nested},
+			{Writer: &flat, Capabilities: SinkCapabilities{FlatKeys: true}},
+			{Writer: &limited, Capabilities: SinkCapabilities{MaxLineSize: 256}},
+		},
+	})
+	assert.NilError(t, err)
+
+	logger.WithFields(logrus.Fields{
+		"http":  HTTP{Request: &Request{Method: "GET", Path: "/users/42"}},
+		"query": strings.Repeat("x", 1000),
+	}).Info("Completed Request")
+
+	t.Run("Entries are nested by default", func(t *testing.T) {
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal(nested.Bytes(), &entry))
+
+		assert.Equal(t, entry["http"].(map[string]interface{})["request"].(map[string]interface{})["path"], "/users/42")
+		assert.Equal(t, entry["truncated"], nil)
+	})
+
+	t.Run("Keys are flattened", func(t *testing.T) {
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal(flat.Bytes(), &entry))
+
+		assert.Equal(t, entry["http.request.path"], "/users/42")
+		assert.Equal(t, entry["http.request.method"], "GET")
+		assert.Equal(t, entry["http"], nil)
+		assert.Equal(t, entry["message"], "Completed Request")
+	})
+
+	t.Run("Lines are truncated to the maximum size", func(t *testing.T) {
+		assert.Assert(t, limited.Len() <= 256, "Unexpected size %d", limited.Len())
+		assert.Assert(t, strings.HasSuffix(limited.String(), "\n"))
+
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal(limited.Bytes(), &entry))
+
+		assert.Equal(t, entry["truncated"], true)
+		assert.Assert(t, strings.HasSuffix(entry["query"].(string), "…"))
+		assert.Equal(t, entry["message"], "Completed Request")
+	})
+
+	t.Run("Short entries are not truncated", func(t *testing.T) {
+		limited.Reset()
+		logger.Info("Started")
+
+		assert.Assert(t, !strings.Contains(limited.String(), "truncated"), limited.String())
+	})
+
+	t.Run("The key order and HTML characters of the formatter are kept", func(t *testing.T) {
+		serialized := []byte(`{"b":"<` + strings.Repeat("x", 300) + `>","a":1.50,"message":"Started"}` + "\n")
+		truncated := string(truncateEntry(serialized, 128))
+
+		assert.Assert(t, len(truncated) <= 128, truncated)
+		assert.Assert(t, strings.HasPrefix(truncated, `{"b":"<xxx`), truncated)
+		assert.Assert(t, strings.HasSuffix(truncated, `…","a":1.50,"message":"Started","truncated":true}`+"\n"), truncated)
+	})
+
+	t.Run("The fields are dropped when halving the strings is not enough", func(t *testing.T) {
+		limited.Reset()
+
+		tags := make([]string, 200)
+
+		for i := range tags {
+			tags[i] = "tag"
+		}
+
+		logger.WithFields(logrus.Fields{
+			"tags":  tags,
+			"ids":   []string{strings.Repeat("1", 200), strings.Repeat("2", 200)},
+			"count": 42,
+		}).Info("Completed Request")
+
+		assert.Assert(t, limited.Len() <= 256, "Unexpected size %d", limited.Len())
+
+		var entry map[string]interface{}
+		assert.NilError(t, json.Unmarshal(limited.Bytes(), &entry))
+
+		assert.Equal(t, entry["truncated"], true)
+		assert.Equal(t, entry["tags"], nil)
+		assert.Assert(t, strings.HasSuffix(entry["ids"].([]interface{})[0].(string), "…"))
+		assert.Equal(t, entry["count"], float64(42))
+		assert.Assert(t, strings.HasPrefix(entry["message"].(string), "Complete"))
+	})
+}
@@ -0,0 +1,171 @@
+package glogger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// levelTrace and levelFatal are the slog levels used for the logrus levels
+// which have no slog equivalent.
+const (
+	levelTrace = slog.LevelDebug - 4
+	levelFatal = slog.LevelError + 4
+)
+
+type discardFormatter struct{}
+
+func (formatter *discardFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
+
+// slogHook forwards every logrus entry to a slog handler.
+type slogHook struct {
+	handler slog.Handler
+}
+
+func (hook *slogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *slogHook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := toSlogLevel(entry.Level)
+
+	if !hook.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, 0)
+
+	for key, value := range entry.Data {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		record.AddAttrs(slog.Any(key, value))
+	}
+
+	return hook.handler.Handle(ctx, record)
+}
+
+// NewSlogLogger returns a logger writing its entries to the slog handler, so that
+// Get and LoggingMiddleware can be used by services logging with log/slog.
+// The level filtering is left to the handler.
+func NewSlogLogger(handler slog.Handler) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetFormatter(&discardFormatter{})
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(&slogHook{handler: handler})
+
+	return logger
+}
+
+// GetSlog returns a slog logger writing to the logger of the context, including
+// the request scoped fields such as the correlation ID.
+func GetSlog(ctx context.Context) *slog.Logger {
+	return slog.New(&entryHandler{entry: Get(ctx)})
+}
+
+// entryHandler is a slog handler writing to a logrus entry.
+type entryHandler struct {
+	entry *logrus.Entry
+	group string
+}
+
+func (handler *entryHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.entry.Logger.IsLevelEnabled(toLogrusLevel(level))
+}
+
+func (handler *entryHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(logrus.Fields, record.NumAttrs())
+
+	record.Attrs(func(attr slog.Attr) bool {
+		handler.addField(fields, attr)
+		return true
+	})
+
+	entry := handler.entry.WithContext(ctx).WithFields(fields)
+	entry.Time = record.Time
+	entry.Log(toLogrusLevel(record.Level), record.Message)
+
+	return nil
+}
+
+func (handler *entryHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(logrus.Fields, len(attrs))
+
+	for _, attr := range attrs {
+		handler.addField(fields, attr)
+	}
+
+	return &entryHandler{entry: handler.entry.WithFields(fields), group: handler.group}
+}
+
+func (handler *entryHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+
+	return &entryHandler{entry: handler.entry, group: handler.group + name + "."}
+}
+
+func (handler *entryHandler) addField(fields logrus.Fields, attr slog.Attr) {
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		group := &entryHandler{entry: handler.entry, group: handler.group + attr.Key + "."}
+
+		for _, groupAttr := range value.Group() {
+			group.addField(fields, groupAttr)
+		}
+
+		return
+	}
+
+	fields[handler.group+attr.Key] = value.Any()
+}
+
+func toSlogLevel(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.TraceLevel:
+		return levelTrace
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	default:
+		return levelFatal
+	}
+}
+
+func toLogrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return logrus.TraceLevel
+	case level < slog.LevelInfo:
+		return logrus.DebugLevel
+	case level < slog.LevelWarn:
+		return logrus.InfoLevel
+	case level < slog.LevelError:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
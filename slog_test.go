@@ -0,0 +1,48 @@
+package glogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewSlogLogger(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		GetSlog(r.Context()).Info("From slog", slog.Group("user", slog.String("id", "42")))
+		GetSlog(r.Context()).Debug("Not enabled")
+	}))
+	request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+	request.Header.Set("X-Request-Id", "request-id")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, len(lines), 2, "Unexpected number of logs")
+
+	var handlerLog map[string]interface{}
+	err := json.Unmarshal([]byte(lines[0]), &handlerLog)
+	assert.Assert(t, err == nil, "log is not a JSON")
+	assert.Equal(t, handlerLog["msg"], "From slog")
+	assert.Equal(t, handlerLog["correlationId"], "request-id")
+	assert.Equal(t, handlerLog["user.id"], "42")
+
+	var completedLog struct {
+		Msg  string
+		HTTP HTTP
+		Host Host
+	}
+	err = json.Unmarshal([]byte(lines[1]), &completedLog)
+	assert.Assert(t, err == nil, "log is not a JSON")
+	assert.Equal(t, completedLog.Msg, "Completed Request")
+	assert.Equal(t, completedLog.HTTP.Request.Path, "/my-req")
+	assert.Equal(t, completedLog.HTTP.Response.StatusCode, http.StatusOK)
+	assert.Equal(t, completedLog.Host.Hostname, hostname)
+}
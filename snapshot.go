@@ -0,0 +1,71 @@
+package glogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Snapshot is an immutable copy of an entry taken at log time, for the sinks processing
+// entries asynchronously. The fields are serialized early, so that the snapshot never
+// observes the changes of the caller to the fields maps and values it reuses.
+type Snapshot struct {
+	Time    time.Time
+	Level   logrus.Level
+	Message string
+	fields  map[string]json.RawMessage
+}
+
+// NewSnapshot takes a snapshot of the entry. The errors are serialized as their message,
+// as the formatters do.
+func NewSnapshot(entry *logrus.Entry) (*Snapshot, error) {
+	fields := make(map[string]json.RawMessage, len(entry.Data))
+
+	for key, value := range entry.Data {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		raw, err := json.Marshal(value)
+
+		if err != nil {
+			return nil, formatError(entry, false, fmt.Errorf("failed to marshal field %s to JSON: %v", key, err))
+		}
+
+		fields[key] = raw
+	}
+
+	return &Snapshot{
+		Time:    entry.Time,
+		Level:   entry.Level,
+		Message: entry.Message,
+		fields:  fields,
+	}, nil
+}
+
+// Field returns the serialized value of the field, and whether the field is set.
+func (snapshot *Snapshot) Field(key string) (json.RawMessage, bool) {
+	raw, ok := snapshot.fields[key]
+	return append(json.RawMessage(nil), raw...), ok
+}
+
+// Entry returns a new entry of the logger with the content of the snapshot, to be
+// formatted by the sink. Its fields are json.RawMessage values: the JSONFormatter
+// output is unchanged, while the GCPFormatter no longer maps the http field to httpRequest.
+func (snapshot *Snapshot) Entry(logger *logrus.Logger) *logrus.Entry {
+	data := make(logrus.Fields, len(snapshot.fields))
+
+	for key, raw := range snapshot.fields {
+		data[key] = raw
+	}
+
+	return &logrus.Entry{
+		Logger:  logger,
+		Data:    data,
+		Time:    snapshot.Time,
+		Level:   snapshot.Level,
+		Message: snapshot.Message,
+	}
+}
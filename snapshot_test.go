@@ -0,0 +1,51 @@
+package glogger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("Snapshot is not affected by changes to the fields", func(t *testing.T) {
+		request := &Request{Path: "/users"}
+		entry := &logrus.Entry{
+			Time:    time.Now(),
+			Level:   logrus.InfoLevel,
+			Message: "Completed Request",
+			Data: logrus.Fields{
+				"http":  HTTP{Request: request},
+				"error": errors.New("failure"),
+			},
+		}
+
+		formatter := JSONFormatter{}
+		expected, err := formatter.Format(entry)
+		assert.NilError(t, err)
+
+		snapshot, err := NewSnapshot(entry)
+		assert.NilError(t, err)
+
+		request.Path = "/changed"
+		entry.Data["userId"] = "42"
+
+		actual, err := formatter.Format(snapshot.Entry(logrus.New()))
+		assert.NilError(t, err)
+		assert.Equal(t, string(actual), string(expected))
+
+		raw, ok := snapshot.Field("error")
+		assert.Assert(t, ok, "Expected error field")
+		assert.Equal(t, string(raw), `"failure"`)
+	})
+
+	t.Run("Unserializable fields are reported", func(t *testing.T) {
+		SetStrictMode(false)
+		defer SetStrictMode(true)
+
+		_, err := NewSnapshot(&logrus.Entry{Data: logrus.Fields{"channel": make(chan int)}})
+		assert.Assert(t, err != nil, "Expected error")
+	})
+}
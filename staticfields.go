@@ -0,0 +1,136 @@
+package glogger
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StaticFieldsHook is a logrus hook adding static fields to every entry, such as the
+// service name, version and environment. The fields of the entries take precedence.
+type StaticFieldsHook struct {
+	fields logrus.Fields
+}
+
+// NewStaticFieldsHook returns a StaticFieldsHook adding a copy of the fields.
+func NewStaticFieldsHook(fields logrus.Fields) *StaticFieldsHook {
+	copied := make(logrus.Fields, len(fields))
+
+	for key, value := range fields {
+		copied[key] = value
+	}
+
+	return &StaticFieldsHook{fields: copied}
+}
+
+// Levels returns the levels of the entries enriched by the hook, which are all of them.
+func (hook *StaticFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the static fields missing from the entry.
+func (hook *StaticFieldsHook) Fire(entry *logrus.Entry) error {
+	data := make(logrus.Fields, len(entry.Data)+len(hook.fields))
+
+	for key, value := range hook.fields {
+		data[key] = value
+	}
+
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+
+	entry.Data = data
+
+	return nil
+}
+
+// StaticPrefix is the start of the JSON entries holding static fields, serialized once,
+// such as the service name and version. Set it as the StaticPrefix of the JSONFormatter,
+// which starts the entries with it, and as the Prefix of an AsyncWriter, which writes it
+// to the connections in a buffer shared by the entries instead of copying it in each one.
+type StaticPrefix struct {
+	fields logrus.Fields
+	prefix []byte
+}
+
+// NewStaticPrefix returns the StaticPrefix of a copy of the fields. It fails when a
+// field cannot be serialized in JSON.
+func NewStaticPrefix(fields logrus.Fields) (*StaticPrefix, error) {
+	copied := make(logrus.Fields, len(fields))
+	keys := make([]string, 0, len(fields))
+
+	for key, value := range fields {
+		if err, isError := value.(error); isError {
+			value = err.Error()
+		}
+
+		copied[key] = value
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	object := newJSONObject(nil, true)
+
+	for _, key := range keys {
+		object.key(key)
+
+		if err := object.value(copied[key]); err != nil {
+			return nil, fmt.Errorf("failed to marshal static field %s to JSON: %v", key, err)
+		}
+	}
+
+	// The fields of the entries follow.
+	if len(keys) > 0 {
+		object.b = append(object.b, ',')
+	}
+
+	return &StaticPrefix{fields: copied, prefix: object.b}, nil
+}
+
+// Bytes returns the prefix, which must not be modified.
+func (p *StaticPrefix) Bytes() []byte {
+	return p.prefix
+}
+
+// collides reports whether the entry has a field of the prefix, including the time,
+// message and level, which then takes precedence over the prefix.
+func (p *StaticPrefix) collides(entry *logrus.Entry, timestamp timestampFormat) bool {
+	for key := range p.fields {
+		if _, ok := entry.Data[key]; ok {
+			return true
+		}
+
+		switch key {
+		case timestamp.key, "message", "level", entryBytesKey:
+			return true
+		case timeZoneKey:
+			if timestamp.zone {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// withFields returns a copy of the entry with the static fields, the fields of the entry
+// taking precedence.
+func (p *StaticPrefix) withFields(entry *logrus.Entry) *logrus.Entry {
+	data := make(logrus.Fields, len(entry.Data)+len(p.fields))
+
+	for key, value := range p.fields {
+		data[key] = value
+	}
+
+	for key, value := range entry.Data {
+		data[key] = value
+	}
+
+	copied := *entry
+	copied.Data = data
+
+	return &copied
+}
@@ -0,0 +1,64 @@
+package glogger
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestStaticFieldsHook(t *testing.T) {
+	fields := logrus.Fields{"service": "api", "version": "1.2.3"}
+
+	logger, err := Init(InitOptions{Output: io.Discard, StaticFields: fields})
+	assert.NilError(t, err)
+	hook := test.NewLocal(logger)
+
+	fields["service"] = "changed"
+	logger.WithField("version", "override").Info("Entry")
+
+	data := hook.LastEntry().Data
+	assert.Equal(t, data["service"], "api")
+	assert.Equal(t, data["version"], "override")
+}
+
+func TestStaticPrefix(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	prefix, err := NewStaticPrefix(logrus.Fields{"version": "1.2.3", "service": "api"})
+	assert.NilError(t, err)
+	assert.Equal(t, string(prefix.Bytes()), `{"service":"api","version":"1.2.3",`)
+
+	formatter := &JSONFormatter{StaticPrefix: prefix}
+
+	t.Run("Entries start with the prefix", func(t *testing.T) {
+		entry := &logrus.Entry{Time: now, Level: logrus.InfoLevel, Message: "Entry", Data: logrus.Fields{"id": 1}}
+
+		data, err := formatter.Format(entry)
+		assert.NilError(t, err)
+		assert.Equal(t, string(data), `{"service":"api","version":"1.2.3","id":1,"level":"info","message":"Entry","time":1700000000}`+"\n")
+	})
+
+	t.Run("Fields of the entry take precedence", func(t *testing.T) {
+		entry := &logrus.Entry{Time: now, Level: logrus.InfoLevel, Message: "Entry", Data: logrus.Fields{"version": "override"}}
+
+		data, err := formatter.Format(entry)
+		assert.NilError(t, err)
+		assert.Equal(t, string(data), `{"level":"info","message":"Entry","service":"api","time":1700000000,"version":"override"}`+"\n")
+	})
+
+	t.Run("Canonical entries have the static fields", func(t *testing.T) {
+		entry := &logrus.Entry{Time: now, Level: logrus.InfoLevel, Message: "Entry"}
+
+		data, err := (&JSONFormatter{StaticPrefix: prefix, Canonical: true}).Format(entry)
+		assert.NilError(t, err)
+		assert.Equal(t, string(data), `{"level":"info","message":"Entry","service":"api","time":1700000000,"version":"1.2.3"}`+"\n")
+	})
+
+	t.Run("Fields which cannot be serialized are rejected", func(t *testing.T) {
+		_, err := NewStaticPrefix(logrus.Fields{"channel": make(chan int)})
+		assert.ErrorContains(t, err, "channel")
+	})
+}
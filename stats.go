@@ -0,0 +1,73 @@
+package glogger
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// entrySizeBounds are the upper bounds, in bytes, of the entry size histogram buckets.
+var entrySizeBounds = []int64{256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, math.MaxInt64}
+
+type entryStats struct {
+	entries  uint64
+	bytes    uint64
+	maxBytes uint64
+	buckets  [8]uint64
+}
+
+var stats entryStats
+
+// SizeBucket struct contains the number of entries whose size is greater than the
+// previous bucket upper bound and lower than or equal to UpperBound.
+type SizeBucket struct {
+	UpperBound int64  `json:"upperBound"`
+	Count      uint64 `json:"count"`
+}
+
+// Statistics struct contains the statistics of the entries serialized by the formatters.
+type Statistics struct {
+	Entries       uint64       `json:"entries"`
+	Bytes         uint64       `json:"bytes"`
+	MaxEntryBytes uint64       `json:"maxEntryBytes"`
+	EntrySizes    []SizeBucket `json:"entrySizes"`
+}
+
+func recordEntrySize(size int) {
+	atomic.AddUint64(&stats.entries, 1)
+	atomic.AddUint64(&stats.bytes, uint64(size))
+
+	for {
+		current := atomic.LoadUint64(&stats.maxBytes)
+
+		if uint64(size) <= current || atomic.CompareAndSwapUint64(&stats.maxBytes, current, uint64(size)) {
+			break
+		}
+	}
+
+	for i, bound := range entrySizeBounds {
+		if int64(size) <= bound {
+			atomic.AddUint64(&stats.buckets[i], 1)
+			return
+		}
+	}
+}
+
+// Stats returns the statistics of the entries serialized by the formatters since
+// the start of the process, including the histogram of the entry sizes.
+func Stats() Statistics {
+	result := Statistics{
+		Entries:       atomic.LoadUint64(&stats.entries),
+		Bytes:         atomic.LoadUint64(&stats.bytes),
+		MaxEntryBytes: atomic.LoadUint64(&stats.maxBytes),
+		EntrySizes:    make([]SizeBucket, len(entrySizeBounds)),
+	}
+
+	for i, bound := range entrySizeBounds {
+		result.EntrySizes[i] = SizeBucket{
+			UpperBound: bound,
+			Count:      atomic.LoadUint64(&stats.buckets[i]),
+		}
+	}
+
+	return result
+}
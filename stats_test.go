@@ -0,0 +1,44 @@
+package glogger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestEntrySizeStats(t *testing.T) {
+	t.Run("Entry sizes are recorded in the histogram", func(t *testing.T) {
+		before := Stats()
+		entry := logrus.Entry{
+			Level:   logrus.InfoLevel,
+			Message: strings.Repeat("a", 2000),
+		}
+
+		data, err := (&JSONFormatter{}).Format(&entry)
+		assert.Assert(t, err == nil, "Error is nil")
+
+		after := Stats()
+
+		assert.Equal(t, after.Entries-before.Entries, uint64(1))
+		assert.Equal(t, after.Bytes-before.Bytes, uint64(len(data)))
+		assert.Equal(t, after.EntrySizes[2].UpperBound, int64(4<<10))
+		assert.Equal(t, after.EntrySizes[2].Count-before.EntrySizes[2].Count, uint64(1))
+		assert.Assert(t, after.MaxEntryBytes >= uint64(len(data)), "Unexpected max entry bytes")
+	})
+
+	t.Run("Entry size field is added over the threshold", func(t *testing.T) {
+		formatter := JSONFormatter{EntrySizeThreshold: 100}
+
+		small, _ := formatter.Format(&logrus.Entry{Level: logrus.InfoLevel, Message: "small"})
+		large, _ := formatter.Format(&logrus.Entry{Level: logrus.InfoLevel, Message: strings.Repeat("a", 200)})
+
+		var fields map[string]interface{}
+		json.Unmarshal(large, &fields)
+
+		assert.Assert(t, !strings.Contains(string(small), "entryBytes"), "Unexpected entryBytes field")
+		assert.Assert(t, fields["entryBytes"].(float64) > 200, "Unexpected entryBytes field value")
+	})
+}
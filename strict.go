@@ -0,0 +1,56 @@
+package glogger
+
+import (
+	"flag"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The strict mode is enabled by default in test binaries, so that serialization bugs
+// surface in CI, unless it was set with SetStrictMode.
+var strict, strictSet atomic.Bool
+
+// SetStrictMode enables or disables the strict mode of every formatter. In strict mode
+// formatting errors panic with a *FormatError instead of being returned to logrus,
+// which only prints them to stderr. Strict mode is enabled by default under go test.
+func SetStrictMode(enabled bool) {
+	strict.Store(enabled)
+	strictSet.Store(true)
+}
+
+// strictMode returns true if the strict mode is enabled. The test binaries are detected
+// by the flags registered by the testing package, which glogger doesn't import.
+func strictMode() bool {
+	if strictSet.Load() {
+		return strict.Load()
+	}
+
+	return flag.Lookup("test.v") != nil
+}
+
+// FormatError is the panic value of the formatters in strict mode.
+type FormatError struct {
+	Message string
+	Fields  logrus.Fields
+	Err     error
+}
+
+func (err *FormatError) Error() string {
+	return fmt.Sprintf("failed to format entry %q with fields %v: %v", err.Message, err.Fields, err.Err)
+}
+
+func (err *FormatError) Unwrap() error {
+	return err.Err
+}
+
+// formatError returns the error of formatting the entry, or panics if the strict mode
+// is enabled globally or by the formatter.
+func formatError(entry *logrus.Entry, formatterStrict bool, err error) error {
+	if formatterStrict || strictMode() {
+		panic(&FormatError{Message: entry.Message, Fields: entry.Data, Err: err})
+	}
+
+	return err
+}
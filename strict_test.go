@@ -0,0 +1,54 @@
+package glogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func formatPanic(formatter logrus.Formatter, entry *logrus.Entry) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+
+	formatter.Format(entry)
+
+	return nil
+}
+
+func TestStrictMode(t *testing.T) {
+	entry := &logrus.Entry{
+		Message: "Unserializable",
+		Data:    logrus.Fields{"channel": make(chan int)},
+	}
+
+	t.Run("Strict mode is enabled under go test", func(t *testing.T) {
+		for _, formatter := range []logrus.Formatter{&JSONFormatter{}, &GCPFormatter{}} {
+			formatErr, ok := formatPanic(formatter, entry).(*FormatError)
+
+			assert.Assert(t, ok, "Expected a *FormatError panic")
+			assert.Equal(t, formatErr.Message, "Unserializable")
+			assert.Assert(t, formatErr.Fields["channel"] != nil, "Expected the offending fields")
+		}
+	})
+
+	t.Run("Errors are returned when strict mode is disabled", func(t *testing.T) {
+		SetStrictMode(false)
+		defer SetStrictMode(true)
+
+		_, err := (&JSONFormatter{}).Format(entry)
+		assert.Assert(t, err != nil, "Expected error")
+	})
+
+	t.Run("Formatter option enables strict mode", func(t *testing.T) {
+		SetStrictMode(false)
+		defer SetStrictMode(true)
+
+		formatErr, ok := formatPanic(&JSONFormatter{Strict: true}, entry).(*FormatError)
+
+		assert.Assert(t, ok, "Expected a *FormatError panic")
+		assert.Assert(t, errors.Unwrap(formatErr) != nil, "Expected the formatting error")
+	})
+}
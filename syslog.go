@@ -0,0 +1,198 @@
+package glogger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSyslogFacility = 1
+	syslogTimeFormat      = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// SyslogOptions is the struct of options to configure a SyslogWriter.
+type SyslogOptions struct {
+	// Network is "udp", the default, "tcp" or "unixgram".
+	Network string
+	// Address is the address of the syslog server, such as "localhost:514".
+	Address string
+	// TLSConfig enables TLS over TCP, as in RFC 5425.
+	TLSConfig *tls.Config
+	// Facility is the syslog facility, such as 16 for local0. Defaults to 1, the
+	// user-level messages.
+	Facility int
+	// AppName is the APP-NAME of the messages. Defaults to the name of the executable.
+	AppName string
+	// Hostname is the HOSTNAME of the messages. Defaults to the host name.
+	Hostname string
+	// LevelKey is the key of the level in the entries, mapped to the severity of the
+	// messages. Defaults to "level".
+	LevelKey string
+	// Timeout is the timeout of the connections and writes. Defaults to 5 seconds.
+	Timeout time.Duration
+	// Buffer configures the buffer of the entries written while the server is slow or
+	// unreachable.
+	Buffer AsyncWriterOptions
+}
+
+// SyslogWriter is an io.WriteCloser shipping the entries to a syslog server, in RFC 5424
+// messages whose MSG is the entry. The messages are framed by octet counting over TCP,
+// and sent one per datagram otherwise. Like the ForwardWriter, the entries are buffered
+// and sent from a background goroutine, reconnecting with a backoff when the connection
+// fails. It can be used as the InitOptions.Output.
+type SyslogWriter struct {
+	framed   bool
+	facility int
+	header   string
+	levelKey string
+	conn     *netWriter
+	buffer   *AsyncWriter
+}
+
+// NewSyslogWriter returns a SyslogWriter. The server is connected on the first entry
+// written, so that it may be unreachable at start.
+func NewSyslogWriter(options SyslogOptions) (*SyslogWriter, error) {
+	if options.Address == "" {
+		return nil, errors.New("syslog: address is required")
+	}
+
+	network := options.Network
+
+	if network == "" {
+		network = "udp"
+	}
+
+	facility := options.Facility
+
+	if facility == 0 {
+		facility = defaultSyslogFacility
+	}
+
+	if facility < 0 || facility > 23 {
+		return nil, errors.New("syslog: invalid facility " + strconv.Itoa(facility))
+	}
+
+	appName := options.AppName
+
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+
+	hostname := options.Hostname
+
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	levelKey := options.LevelKey
+
+	if levelKey == "" {
+		levelKey = logrus.FieldKeyLevel
+	}
+
+	conn := newNetWriter(network, options.Address, options.TLSConfig, options.Timeout)
+
+	return &SyslogWriter{
+		framed:   network == "tcp" || network == "tcp4" || network == "tcp6",
+		facility: facility,
+		header:   " " + syslogName(hostname) + " " + syslogName(appName) + " " + strconv.Itoa(os.Getpid()) + " - - ",
+		levelKey: levelKey,
+		conn:     conn,
+		buffer:   NewAsyncWriter(conn, options.Buffer),
+	}, nil
+}
+
+// Write formats the entry in a syslog message and buffers it.
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	entry := bytes.TrimRight(p, "\n")
+
+	message := make([]byte, 0, len(entry)+128)
+	message = append(message, '<')
+	message = strconv.AppendInt(message, int64(w.facility*8+syslogSeverity(entry, w.levelKey)), 10)
+	message = append(message, ">1 "...)
+	message = time.Now().UTC().AppendFormat(message, syslogTimeFormat)
+	message = append(message, w.header...)
+	message = append(message, entry...)
+
+	if w.framed {
+		framed := strconv.AppendInt(make([]byte, 0, len(message)+8), int64(len(message)), 10)
+		framed = append(framed, ' ')
+		message = append(framed, message...)
+	}
+
+	if _, err := w.buffer.Write(message); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (w *SyslogWriter) Dropped() int64 {
+	return w.buffer.Dropped()
+}
+
+// Flush blocks until the buffered entries are sent.
+func (w *SyslogWriter) Flush() {
+	w.buffer.Flush()
+}
+
+// Close sends the buffered entries, without retrying when the server is unreachable,
+// and closes the connection.
+func (w *SyslogWriter) Close() error {
+	w.conn.shutdown()
+	w.buffer.Close()
+
+	return w.conn.Close()
+}
+
+// syslogSeverity returns the severity of the level of a JSON entry, informational when
+// the entry has no level.
+func syslogSeverity(entry []byte, levelKey string) int {
+	var fields map[string]json.RawMessage
+	var level string
+
+	if json.Unmarshal(entry, &fields) != nil || json.Unmarshal(fields[levelKey], &level) != nil {
+		return 6
+	}
+
+	switch level {
+	case "panic":
+		return 0
+	case "fatal":
+		return 2
+	case "error":
+		return 3
+	case "warning", "warn":
+		return 4
+	case "debug", "trace":
+		return 7
+	default:
+		return 6
+	}
+}
+
+// syslogName returns the printable ASCII of a header field, or the nil value "-".
+func syslogName(name string) string {
+	printable := make([]byte, 0, len(name))
+
+	for i := 0; i < len(name); i++ {
+		if name[i] > ' ' && name[i] < 127 {
+			printable = append(printable, name[i])
+		}
+	}
+
+	if len(printable) == 0 {
+		return "-"
+	}
+
+	return string(printable)
+}
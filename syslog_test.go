@@ -0,0 +1,84 @@
+package glogger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestSyslogWriter(t *testing.T) {
+	t.Run("Messages are sent in datagrams over UDP", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		writer, err := NewSyslogWriter(SyslogOptions{Address: conn.LocalAddr().String(), Facility: 16, AppName: "my app", Hostname: "host"})
+		assert.NilError(t, err)
+		defer writer.Close()
+
+		_, err = writer.Write([]byte(`{"level":"error","msg":"failed"}` + "\n"))
+		assert.NilError(t, err)
+
+		b := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(b)
+		assert.NilError(t, err)
+
+		pattern := `^<131>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}Z host myapp ` + strconv.Itoa(os.Getpid()) + ` - - \{"level":"error","msg":"failed"\}$`
+		assert.Assert(t, regexp.MustCompile(pattern).Match(b[:n]), string(b[:n]))
+	})
+
+	t.Run("Messages are framed and resent after reconnection over TCP", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+
+		writer, err := NewSyslogWriter(SyslogOptions{Network: "tcp", Address: address})
+		assert.NilError(t, err)
+		defer writer.Close()
+
+		_, err = writer.Write([]byte(`{"level":"warning","msg":"retried"}` + "\n"))
+		assert.NilError(t, err)
+
+		// The writer is backing off until the server is up.
+		time.Sleep(50 * time.Millisecond)
+
+		listener, err = net.Listen("tcp", address)
+		assert.NilError(t, err)
+		defer listener.Close()
+
+		conn, err := listener.Accept()
+		assert.NilError(t, err)
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		length, err := reader.ReadString(' ')
+		assert.NilError(t, err)
+
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		message := make([]byte, n)
+		_, err = io.ReadFull(reader, message)
+		assert.NilError(t, err)
+
+		assert.Assert(t, strings.HasPrefix(string(message), "<12>1 "), string(message))
+		assert.Assert(t, strings.HasSuffix(string(message), ` - - {"level":"warning","msg":"retried"}`), string(message))
+	})
+
+	t.Run("Severities", func(t *testing.T) {
+		assert.Equal(t, syslogSeverity([]byte(`{"level":"debug"}`), "level"), 7)
+		assert.Equal(t, syslogSeverity([]byte(`{"severity":"fatal"}`), "severity"), 2)
+		assert.Equal(t, syslogSeverity([]byte(`level=error`), "level"), 6)
+	})
+
+	_, err := NewSyslogWriter(SyslogOptions{Address: "localhost:514", Facility: 24})
+	assert.Assert(t, err != nil, "Expected error")
+}
@@ -0,0 +1,86 @@
+package glogger
+
+import (
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	timeKey     = "time"
+	timeZoneKey = "tz"
+)
+
+const (
+	// TimestampUnixMillis is the TimestampFormat of the time in unix milliseconds.
+	TimestampUnixMillis = "unix-millis"
+	// TimestampUnixNanos is the TimestampFormat of the time in unix nanoseconds.
+	TimestampUnixNanos = "unix-nanos"
+)
+
+// timestampFormat formats the time of the entries of the JSONFormatter.
+type timestampFormat struct {
+	// key is the key of the time field.
+	key string
+	// layout is the time layout, TimestampUnixMillis, TimestampUnixNanos, or empty for
+	// unix seconds.
+	layout   string
+	location *time.Location
+	zone     bool
+}
+
+func (formatter *JSONFormatter) timestampFormat() timestampFormat {
+	location := formatter.Location
+
+	if location == nil {
+		location = time.UTC
+	}
+
+	key := formatter.TimeField
+
+	if key == "" {
+		key = timeKey
+	}
+
+	return timestampFormat{key: key, layout: formatter.TimestampFormat, location: location, zone: formatter.TimeZoneField}
+}
+
+// value returns the time value of the map of fields.
+func (format timestampFormat) value(t time.Time) interface{} {
+	switch format.layout {
+	case "":
+		return t.Unix()
+	case TimestampUnixMillis:
+		return t.UnixMilli()
+	case TimestampUnixNanos:
+		return t.UnixNano()
+	}
+
+	return t.In(format.location).Format(format.layout)
+}
+
+// append appends the JSON encoding of the time.
+func (format timestampFormat) append(b []byte, t time.Time, escapeHTML bool) []byte {
+	switch format.layout {
+	case "":
+		return strconv.AppendInt(b, t.Unix(), 10)
+	case TimestampUnixMillis:
+		return strconv.AppendInt(b, t.UnixMilli(), 10)
+	case TimestampUnixNanos:
+		return strconv.AppendInt(b, t.UnixNano(), 10)
+	}
+
+	var scratch [64]byte
+	formatted := t.In(format.location).AppendFormat(scratch[:0], format.layout)
+
+	for _, c := range formatted {
+		if c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			return appendJSONString(b, string(formatted), escapeHTML)
+		}
+	}
+
+	b = append(b, '"')
+	b = append(b, formatted...)
+
+	return append(b, '"')
+}
@@ -0,0 +1,116 @@
+package glogger
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	timingKey       = "timing"
+	serverTimingKey = "Server-Timing"
+	// appTimingName is the name of the handler duration in the Server-Timing header.
+	appTimingName = "app"
+)
+
+// Timing struct contains items of request timing info log. The start is the wall clock
+// time at which the request was received, and the duration is measured with the
+// monotonic clock, so that a step of the wall clock during the request, such as an NTP
+// correction or a leap second, cannot yield a negative or absurd duration.
+type Timing struct {
+	Start    time.Time       `json:"start"`
+	Duration time.Duration   `json:"durationNs"`
+	Segments []TimingSegment `json:"segments,omitempty"`
+}
+
+// TimingSegment struct contains items of request timing segment info log.
+type TimingSegment struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"durationNs"`
+}
+
+// newTiming returns the timing of a request started at start, as returned by time.Now.
+func newTiming(start time.Time, duration time.Duration, segments []TimingSegment) Timing {
+	// Round(0) strips the monotonic clock reading, which must not be compared with the
+	// wall clock of other processes.
+	return Timing{Start: start.Round(0).UTC(), Duration: duration, Segments: segments}
+}
+
+type timingSegmentsKey struct{}
+
+type timingSegments struct {
+	mutex    sync.Mutex
+	segments []TimingSegment
+}
+
+// withTimingSegments returns a new context able to record the segments of RecordTiming.
+func withTimingSegments(ctx context.Context) context.Context {
+	return context.WithValue(ctx, timingSegmentsKey{}, &timingSegments{})
+}
+
+// RecordTiming records a named segment of the request duration, such as the time spent
+// querying the database, added to the timing field of the completed request entry and
+// to the Server-Timing header. The name should be a token, such as "db". It has no
+// effect on contexts not created by the middleware, or without its Timing and
+// ServerTiming options.
+func RecordTiming(ctx context.Context, name string, duration time.Duration) {
+	timings, ok := ctx.Value(timingSegmentsKey{}).(*timingSegments)
+
+	if !ok {
+		return
+	}
+
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+
+	timings.segments = append(timings.segments, TimingSegment{Name: name, Duration: duration})
+}
+
+func timingSegmentsFromContext(ctx context.Context) []TimingSegment {
+	timings, ok := ctx.Value(timingSegmentsKey{}).(*timingSegments)
+
+	if !ok {
+		return nil
+	}
+
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+
+	return append([]TimingSegment(nil), timings.segments...)
+}
+
+// serverTiming returns the value of the Server-Timing header with the handler duration,
+// as app, followed by the segments, in milliseconds.
+func serverTiming(duration time.Duration, segments []TimingSegment) string {
+	metrics := []string{serverTimingMetric(appTimingName, duration)}
+
+	for _, segment := range segments {
+		metrics = append(metrics, serverTimingMetric(segment.Name, segment.Duration))
+	}
+
+	return strings.Join(metrics, ", ")
+}
+
+func serverTimingMetric(name string, duration time.Duration) string {
+	ms := float64(duration) / float64(time.Millisecond)
+
+	return serverTimingName(name) + ";dur=" + strconv.FormatFloat(ms, 'f', -1, 64)
+}
+
+// serverTimingName replaces the characters of the name which are not allowed in a
+// token, such as spaces, with underscores.
+func serverTimingName(name string) string {
+	if name == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return '_'
+		}
+
+		return r
+	}, name)
+}
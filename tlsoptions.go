@@ -0,0 +1,95 @@
+package glogger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// TLSOptions is the struct of options to configure the TLS connections of the network
+// sinks, such as the ForwardWriter, the SyslogWriter and the OTLP exporter, to the
+// backends with a private CA or requiring client certificates.
+type TLSOptions struct {
+	// CAFile is the PEM bundle of the certificate authorities trusted in addition to the
+	// system ones, such as a private CA.
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key, for mutual TLS. They
+	// are read again at each handshake, so that the renewed certificates are used without
+	// restart.
+	CertFile string
+	KeyFile  string
+	// MinVersion is the minimum TLS version, such as tls.VersionTLS13. Defaults to TLS 1.2.
+	MinVersion uint16
+	// ServerName is the expected name of the server certificate, when it differs from the
+	// host of the address.
+	ServerName string
+}
+
+// Config returns the tls.Config of the options, to be set as the TLSConfig of the sinks.
+func (options TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: options.MinVersion, ServerName: options.ServerName}
+
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if options.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(options.CAFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls: no certificate in %s", options.CAFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return nil, errors.New("tls: the client certificate requires both CertFile and KeyFile")
+	}
+
+	if options.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		loader := &certificateLoader{certFile: options.CertFile, keyFile: options.KeyFile, certificate: &certificate}
+		config.GetClientCertificate = loader.clientCertificate
+	}
+
+	return config, nil
+}
+
+// certificateLoader reads the client certificate at each handshake, keeping the last
+// valid one when the files are being replaced.
+type certificateLoader struct {
+	certFile    string
+	keyFile     string
+	mutex       sync.Mutex
+	certificate *tls.Certificate
+}
+
+func (loader *certificateLoader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+
+	if certificate, err := tls.LoadX509KeyPair(loader.certFile, loader.keyFile); err == nil {
+		loader.certificate = &certificate
+	}
+
+	return loader.certificate, nil
+}
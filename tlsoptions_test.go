@@ -0,0 +1,123 @@
+package glogger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// newTestCertificate returns a certificate signed by the parent, or self-signed without
+// parent, and its key.
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NilError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+
+	return certificate, key
+}
+
+func writeTestPEM(t *testing.T, filename string, certificate *x509.Certificate, key *ecdsa.PrivateKey) {
+	t.Helper()
+
+	assert.NilError(t, os.WriteFile(filename+".crt", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}), 0o600))
+
+	if key != nil {
+		der, err := x509.MarshalECPrivateKey(key)
+		assert.NilError(t, err)
+		assert.NilError(t, os.WriteFile(filename+".key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	dir := t.TempDir()
+	expiry := time.Now().Add(time.Hour)
+
+	ca, caKey := newTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Private CA"},
+		NotAfter:              expiry,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	server, serverKey := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "collector"},
+		NotAfter:     expiry,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	client, clientKey := newTestCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "api"},
+		NotAfter:     expiry,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	writeTestPEM(t, filepath.Join(dir, "ca"), ca, nil)
+	writeTestPEM(t, filepath.Join(dir, "client"), client, clientKey)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	collector := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	collector.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{server.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	collector.StartTLS()
+	defer collector.Close()
+
+	config, err := TLSOptions{
+		CAFile:     filepath.Join(dir, "ca.crt"),
+		CertFile:   filepath.Join(dir, "client.crt"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		MinVersion: tls.VersionTLS13,
+	}.Config()
+	assert.NilError(t, err)
+
+	response, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(collector.URL)
+	assert.NilError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, response.TLS.Version, uint16(tls.VersionTLS13))
+	assert.Equal(t, response.StatusCode, http.StatusOK)
+
+	_, err = TLSOptions{CertFile: filepath.Join(dir, "client.crt")}.Config()
+	assert.ErrorContains(t, err, "requires both CertFile and KeyFile")
+
+	_, err = TLSOptions{CAFile: filepath.Join(dir, "client.key")}.Config()
+	assert.ErrorContains(t, err, "no certificate")
+
+	config, err = TLSOptions{}.Config()
+	assert.NilError(t, err)
+	assert.Equal(t, config.MinVersion, uint16(tls.VersionTLS12))
+}
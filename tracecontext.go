@@ -0,0 +1,72 @@
+package glogger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	traceparentKey = "traceparent"
+	traceIDKey     = "traceId"
+	spanIDKey      = "spanId"
+)
+
+// SpanContextFunc returns the trace and span IDs of the span active in the context,
+// e.g. with OpenTelemetry:
+//
+//	func(ctx context.Context) (string, string, bool) {
+//		spanContext := trace.SpanContextFromContext(ctx)
+//		return spanContext.TraceID().String(), spanContext.SpanID().String(), spanContext.IsValid()
+//	}
+type SpanContextFunc func(ctx context.Context) (traceID, spanID string, ok bool)
+
+func isHex(value string, length int) bool {
+	if len(value) != length || strings.Trim(value, "0") == "" {
+		return false
+	}
+
+	for _, c := range value {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseTraceparent parses a W3C traceparent header: "version-traceid-parentid-flags".
+func parseTraceparent(header string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+
+	if !isHex(parts[1], 32) || !isHex(parts[2], 16) {
+		return "", "", false
+	}
+
+	return parts[1], parts[2], true
+}
+
+func addTraceFields(fields logrus.Fields, r *http.Request, spanContext SpanContextFunc) {
+	if spanContext != nil {
+		if traceID, spanID, ok := spanContext(r.Context()); ok {
+			fields[traceIDKey] = traceID
+			fields[spanIDKey] = spanID
+			return
+		}
+	}
+
+	if traceID, spanID, ok := parseTraceparent(r.Header.Get(traceparentKey)); ok {
+		fields[traceIDKey] = traceID
+		fields[spanIDKey] = spanID
+	}
+}
@@ -0,0 +1,71 @@
+package glogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, spanID, ok := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	assert.Assert(t, ok, "Unexpected invalid traceparent")
+	assert.Equal(t, traceID, "4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, spanID, "00f067aa0ba902b7")
+
+	for _, header := range []string{
+		"",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		_, _, ok := parseTraceparent(header)
+		assert.Assert(t, !ok, "Unexpected valid traceparent %s", header)
+	}
+}
+
+func TestTraceContextFields(t *testing.T) {
+	invoke := func(options MiddlewareOptions) map[string]interface{} {
+		logger, _ := test.NewNullLogger()
+		request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		var fields map[string]interface{}
+		handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			fields = Get(r.Context()).Data
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+
+		return fields
+	}
+
+	t.Run("Trace fields are not added by default", func(t *testing.T) {
+		fields := invoke(MiddlewareOptions{})
+
+		assert.Assert(t, fields["traceId"] == nil, "Unexpected traceId field")
+	})
+
+	t.Run("Trace fields are taken from traceparent", func(t *testing.T) {
+		fields := invoke(MiddlewareOptions{TraceContext: true})
+
+		assert.Equal(t, fields["traceId"], "4bf92f3577b34da6a3ce929d0e0e4736")
+		assert.Equal(t, fields["spanId"], "00f067aa0ba902b7")
+	})
+
+	t.Run("Active span takes precedence", func(t *testing.T) {
+		fields := invoke(MiddlewareOptions{
+			TraceContext: true,
+			SpanContext: func(ctx context.Context) (string, string, bool) {
+				return "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331", true
+			},
+		})
+
+		assert.Equal(t, fields["traceId"], "0af7651916cd43dd8448eb211c80319c")
+		assert.Equal(t, fields["spanId"], "b7ad6b7169203331")
+	})
+}
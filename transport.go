@@ -0,0 +1,48 @@
+package glogger
+
+import (
+	"net"
+	"net/http"
+)
+
+const (
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+	schemeH2C   = "h2c"
+	schemeUnix  = "unix"
+)
+
+// getUnixSocket returns the path of the unix domain socket the request was
+// received on, if any.
+func getUnixSocket(request *http.Request) (string, bool) {
+	addr, ok := request.Context().Value(http.LocalAddrContextKey).(*net.UnixAddr)
+
+	if !ok {
+		return "", false
+	}
+
+	return addr.Name, true
+}
+
+// getScheme returns the scheme of the request URL or, for server requests where it
+// is not set, the scheme of the transport: unix for unix domain sockets, h2c for
+// HTTP/2 over cleartext TCP, https for TLS and http otherwise.
+func getScheme(request *http.Request) string {
+	if request.URL.Scheme != "" {
+		return request.URL.Scheme
+	}
+
+	if _, ok := getUnixSocket(request); ok {
+		return schemeUnix
+	}
+
+	if request.TLS != nil {
+		return schemeHTTPS
+	}
+
+	if request.ProtoMajor == 2 {
+		return schemeH2C
+	}
+
+	return schemeHTTP
+}
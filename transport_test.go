@@ -0,0 +1,51 @@
+package glogger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestUnixSocketTransport(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "glogger.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.Assert(t, err == nil, "Unexpected error opening listener")
+
+	logger, hook := test.NewNullLogger()
+	server := &http.Server{Handler: LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return net.Dial("unix", socket)
+		},
+	}}
+	response, err := client.Get("http://unix/api/v1/users")
+	assert.Assert(t, err == nil, "Unexpected error calling server")
+	response.Body.Close()
+
+	entry := hook.LastEntry()
+	host := entry.Data["host"].(Host)
+
+	assert.Equal(t, entry.Data["http"].(HTTP).Request.Scheme, "unix")
+	assert.Equal(t, host.Hostname, socket)
+	assert.Equal(t, host.IP, "")
+}
+
+func TestH2CTransport(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	request.Proto, request.ProtoMajor, request.ProtoMinor = "HTTP/2.0", 2, 0
+
+	handler := LoggingMiddleware(logger)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, hook.LastEntry().Data["http"].(HTTP).Request.Scheme, "h2c")
+}
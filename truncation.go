@@ -0,0 +1,84 @@
+package glogger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultLineWidth  = 120
+	minTruncatedValue = 10
+)
+
+// terminalWidth returns the terminal width from the COLUMNS environment variable,
+// or a default width of 120 columns.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	return defaultLineWidth
+}
+
+// truncateLines truncates the string values of the pretty printed JSON output whose
+// line is longer than width, appending an ellipsis and the number of omitted characters.
+func truncateLines(output []byte, width int) []byte {
+	lines := strings.Split(string(output), "\n")
+
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) <= width {
+			continue
+		}
+
+		match := prettyLineRegexp.FindStringSubmatch(line)
+
+		if match == nil || !strings.HasPrefix(match[3], `"`) {
+			continue
+		}
+
+		value := []rune(strings.TrimSuffix(strings.TrimPrefix(match[3], `"`), `"`))
+		prefix := match[1] + `"` + match[2] + `": "`
+		keep := width - utf8.RuneCountInString(prefix) - len(`… (+999999 chars)",`)
+
+		if keep < minTruncatedValue {
+			keep = minTruncatedValue
+		}
+
+		if keep >= len(value) {
+			continue
+		}
+
+		keep = escapeBoundary(value, keep)
+
+		lines[i] = fmt.Sprintf(`%s%s… (+%d chars)"%s`, prefix, string(value[:keep]), len(value)-keep, match[4])
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// escapeBoundary returns the greatest length of the escaped JSON string value not greater
+// than keep, which doesn't cut an escape sequence, such as \\ or \u00e9, in half.
+func escapeBoundary(value []rune, keep int) int {
+	for i := 0; i < len(value); {
+		next := i + 1
+
+		if value[i] == '\\' {
+			next = i + 2
+
+			if i+1 < len(value) && value[i+1] == 'u' {
+				next = i + 6
+			}
+		}
+
+		if next > keep {
+			return i
+		}
+
+		i = next
+	}
+
+	return keep
+}
@@ -0,0 +1,55 @@
+package glogger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/assert"
+)
+
+func TestTruncateValues(t *testing.T) {
+	formatter := JSONFormatter{PrettyPrint: true, TruncateValues: true, LineWidth: 60}
+	entry := logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Message: strings.Repeat("a", 100),
+		Data:    logrus.Fields{"short": "value"},
+	}
+
+	data, err := formatter.Format(&entry)
+	assert.Assert(t, err == nil, "Error is nil")
+
+	lines := strings.Split(string(data), "\n")
+
+	for _, line := range lines {
+		assert.Assert(t, len([]rune(line)) <= 60, "Line too long: %s", line)
+	}
+
+	assert.Assert(t, strings.Contains(string(data), `"message": "aaaaaaaaaaaaaaaaaaaaaaaaa… (+75 chars)",`), string(data))
+	assert.Assert(t, strings.Contains(string(data), `"short": "value"`), string(data))
+}
+
+func TestTruncateEscapeSequences(t *testing.T) {
+	for _, escaped := range []string{`\\`, `\"`, `\u00e9`} {
+		for offset := 0; offset < 8; offset++ {
+			value := `\"` + strings.Repeat("a", 18+offset) + escaped + strings.Repeat("b", 100)
+			line := string(truncateLines([]byte(`  "message": "`+value+`",`), 60))
+
+			match := prettyLineRegexp.FindStringSubmatch(line)
+			assert.Assert(t, match != nil, line)
+
+			var truncated string
+			assert.NilError(t, json.Unmarshal([]byte(match[3]), &truncated), line)
+			assert.Assert(t, strings.Contains(truncated, "… (+"), line)
+		}
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "80")
+	assert.Equal(t, terminalWidth(), 80)
+
+	t.Setenv("COLUMNS", "")
+	assert.Equal(t, terminalWidth(), 120)
+}
@@ -0,0 +1,153 @@
+package glogger
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+const (
+	forwardedKey = "Forwarded"
+	realIPKey    = "X-Real-Ip"
+)
+
+// TrustedProxies is the list of the networks of the trusted proxies.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses the CIDRs, such as "10.0.0.0/8", or the IP addresses of the
+// trusted proxies.
+func ParseTrustedProxies(proxies ...string) (TrustedProxies, error) {
+	result := make(TrustedProxies, 0, len(proxies))
+
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+
+			if err != nil {
+				return nil, err
+			}
+
+			addr = addr.Unmap()
+			result = append(result, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, prefix.Masked())
+	}
+
+	return result, nil
+}
+
+func (proxies TrustedProxies) trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+
+	if err != nil {
+		return false
+	}
+
+	addr = addr.WithZone("").Unmap()
+
+	for _, prefix := range proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forwardedChain returns the client addresses forwarded by the proxies, from the
+// Forwarded header, the X-Forwarded-For header or the X-Real-IP header, in this order
+// of preference. The first address is the original client.
+func forwardedChain(header http.Header) []string {
+	var chain []string
+
+	for _, value := range header.Values(forwardedKey) {
+		for _, element := range strings.Split(value, ",") {
+			if forwarded := forwardedParameter(element, "for"); forwarded != "" {
+				host, _ := SplitHostPort(forwarded)
+				chain = append(chain, host)
+			}
+		}
+	}
+
+	if len(chain) > 0 {
+		return chain
+	}
+
+	for _, value := range header.Values(forwardedForKey) {
+		for _, ip := range strings.Split(value, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				chain = append(chain, ip)
+			}
+		}
+	}
+
+	if len(chain) > 0 {
+		return chain
+	}
+
+	if ip := strings.TrimSpace(header.Get(realIPKey)); ip != "" {
+		return []string{ip}
+	}
+
+	return nil
+}
+
+// forwardedParameter returns the value of the parameter of a Forwarded header element,
+// such as for="[2001:db8::1]:4711".
+func forwardedParameter(element string, name string) string {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+
+		if ok && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`)
+		}
+	}
+
+	return ""
+}
+
+// client returns the IP of the client and the host forwarded by the proxies. The proxies
+// are trusted when the request comes from a trusted address, from the PROXY protocol
+// or the connection, or from a unix domain socket. The forwarded addresses are then
+// walked from the closest proxy, and the first address which is not trusted is the client.
+func (proxies TrustedProxies) client(r *http.Request) (ip string, forwardedHost string) {
+	_, unix := getUnixSocket(r)
+	remote := getProxySourceIP(r)
+
+	if remote == "" && !unix {
+		remote = removePort(r.RemoteAddr)
+	}
+
+	if !unix && !proxies.trusts(remote) {
+		return NormalizeIP(remote), ""
+	}
+
+	forwardedHost = r.Header.Get(forwardedHostKey)
+
+	if forwardedHost == "" {
+		element, _, _ := strings.Cut(r.Header.Get(forwardedKey), ",")
+		forwardedHost = forwardedParameter(element, "host")
+	}
+
+	chain := forwardedChain(r.Header)
+
+	if len(chain) == 0 {
+		return NormalizeIP(remote), forwardedHost
+	}
+
+	for i := len(chain) - 1; i > 0; i-- {
+		if !proxies.trusts(chain[i]) {
+			return NormalizeIP(chain[i]), forwardedHost
+		}
+	}
+
+	return NormalizeIP(chain[0]), forwardedHost
+}
@@ -0,0 +1,110 @@
+package glogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8", "192.0.2.1", "2001:db8::/32")
+	assert.NilError(t, err)
+
+	assert.Assert(t, proxies.trusts("10.1.2.3"))
+	assert.Assert(t, proxies.trusts("::ffff:192.0.2.1"))
+	assert.Assert(t, proxies.trusts("2001:db8::1"))
+	assert.Assert(t, !proxies.trusts("192.0.2.2"))
+	assert.Assert(t, !proxies.trusts("unknown"))
+
+	_, err = ParseTrustedProxies("10.0.0.0/33")
+	assert.Assert(t, err != nil, "Expected error")
+}
+
+func TestTrustedProxiesClient(t *testing.T) {
+	proxies, _ := ParseTrustedProxies("10.0.0.0/8")
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		header       http.Header
+		expectedIP   string
+		expectedHost string
+	}{
+		{
+			name:       "Headers of untrusted peers are ignored",
+			remoteAddr: "203.0.113.7:1234",
+			header:     http.Header{"X-Forwarded-For": {"1.2.3.4"}, "X-Forwarded-Host": {"spoofed.example.com"}},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:         "Client is the first untrusted address from the right",
+			remoteAddr:   "10.0.0.1:1234",
+			header:       http.Header{"X-Forwarded-For": {"1.2.3.4, 198.51.100.1", "10.0.0.2"}, "X-Forwarded-Host": {"example.com"}},
+			expectedIP:   "198.51.100.1",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "Forwarded header is preferred",
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{
+				"Forwarded":       {`for="[2001:db8:cafe::17]:4711";host=example.com, for=10.0.0.2`},
+				"X-Forwarded-For": {"1.2.3.4"},
+			},
+			expectedIP:   "2001:db8:cafe::17",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "X-Real-IP is used without forwarding headers",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Real-Ip": {"198.51.100.1"}},
+			expectedIP: "198.51.100.1",
+		},
+		{
+			name:       "Leftmost address is the client when all proxies are trusted",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			expectedIP: "10.0.0.3",
+		},
+		{
+			name:       "Remote address is the client without headers",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{},
+			expectedIP: "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = tt.remoteAddr
+			request.Header = tt.header
+
+			ip, host := proxies.client(request)
+
+			assert.Equal(t, ip, tt.expectedIP)
+			assert.Equal(t, host, tt.expectedHost)
+		})
+	}
+}
+
+func TestTrustedProxiesMiddleware(t *testing.T) {
+	proxies, _ := ParseTrustedProxies("192.0.2.0/24")
+	options := MiddlewareOptions{TrustedProxies: proxies}
+
+	logger, hook := test.NewNullLogger()
+
+	request := httptest.NewRequest(http.MethodGet, defaultRequestPath, nil)
+	request.RemoteAddr = "203.0.113.7:1234"
+	request.Header.Set("X-Forwarded-For", "1.2.3.4")
+	request.Header.Set("X-Forwarded-Host", clientHost)
+
+	handler := LoggingMiddlewareWithOptions(logger, options)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	host := hook.LastEntry().Data["host"].(Host)
+	assert.Equal(t, host.IP, "203.0.113.7")
+	assert.Equal(t, host.ForwardedHostname, "")
+}
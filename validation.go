@@ -0,0 +1,110 @@
+package glogger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+const validationErrorsKey = "validationErrors"
+
+// ValidationFailure struct contains items of a field validation failure info log. The
+// value is never logged, only masked to tell the missing values from the invalid ones.
+type ValidationFailure struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Error returns the description of the failure.
+func (failure ValidationFailure) Error() string {
+	if failure.Message != "" {
+		return failure.Message
+	}
+
+	return fmt.Sprintf("%s failed on the %s rule", failure.Field, failure.Rule)
+}
+
+// fieldError is the interface of the field errors of the validators, such as the
+// validator.FieldError of go-playground/validator.
+type fieldError interface {
+	Field() string
+	Tag() string
+}
+
+// ValidationErrors logs the field validation failures of errs at info level with the
+// logger of the context, as an array of field, rule and masked value in the
+// validationErrors field, to standardize the diagnostics of the 400 responses. errs may
+// be a ValidationFailure, the validator.ValidationErrors of go-playground/validator, or
+// any slice of errors with Field and Tag methods, possibly joined with errors.Join. The
+// other errors are logged with their message only.
+func ValidationErrors(ctx context.Context, errs error) {
+	if errs == nil {
+		return
+	}
+
+	Get(ctx).WithField(validationErrorsKey, NewValidationFailures(errs)).Info("Validation failed")
+}
+
+// NewValidationFailures returns the validation failures of errs, as logged by ValidationErrors.
+func NewValidationFailures(errs error) []ValidationFailure {
+	var failures []ValidationFailure
+
+	var collect func(err error)
+	collect = func(err error) {
+		switch err := err.(type) {
+		case nil:
+			return
+		case ValidationFailure:
+			if err.Value != "" {
+				err.Value = defaultRedactionMask
+			}
+
+			failures = append(failures, err)
+
+			return
+		case fieldError:
+			failures = append(failures, newValidationFailure(err))
+
+			return
+		case interface{ Unwrap() []error }:
+			for _, cause := range err.Unwrap() {
+				collect(cause)
+			}
+
+			return
+		}
+
+		if value := reflect.ValueOf(err); value.Kind() == reflect.Slice {
+			for i := 0; i < value.Len(); i++ {
+				switch element := value.Index(i).Interface().(type) {
+				case fieldError:
+					failures = append(failures, newValidationFailure(element))
+				case error:
+					collect(element)
+				}
+			}
+
+			return
+		}
+
+		failures = append(failures, ValidationFailure{Message: err.Error()})
+	}
+
+	collect(errs)
+
+	return failures
+}
+
+func newValidationFailure(err fieldError) ValidationFailure {
+	failure := ValidationFailure{Field: err.Field(), Rule: err.Tag()}
+
+	if valuer, ok := err.(interface{ Value() interface{} }); ok {
+		if value := reflect.ValueOf(valuer.Value()); value.IsValid() && !value.IsZero() {
+			failure.Value = defaultRedactionMask
+		}
+	}
+
+	return failure
+}
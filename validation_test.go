@@ -0,0 +1,57 @@
+package glogger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/assert"
+)
+
+// testFieldError mimics the validator.FieldError of go-playground/validator.
+type testFieldError struct {
+	field string
+	tag   string
+	value interface{}
+}
+
+func (err testFieldError) Field() string      { return err.field }
+func (err testFieldError) Tag() string        { return err.tag }
+func (err testFieldError) Value() interface{} { return err.value }
+func (err testFieldError) Error() string      { return err.field + " " + err.tag }
+
+// testValidationErrors mimics the validator.ValidationErrors of go-playground/validator.
+type testValidationErrors []testFieldError
+
+func (errs testValidationErrors) Error() string { return "validation failed" }
+
+func TestValidationErrors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	ctx := WithLogger(context.Background(), logger.WithField("correlationId", "id"))
+
+	errs := errors.Join(
+		testValidationErrors{
+			{field: "email", tag: "email", value: "not an email"},
+			{field: "name", tag: "required"},
+		},
+		ValidationFailure{Field: "age", Rule: "min", Value: "12"},
+		errors.New("body is not JSON"),
+	)
+
+	ValidationErrors(ctx, errs)
+
+	entry := hook.LastEntry()
+	assert.Equal(t, entry.Message, "Validation failed")
+	assert.Equal(t, entry.Data["correlationId"], "id")
+	assert.DeepEqual(t, entry.Data["validationErrors"], []ValidationFailure{
+		{Field: "email", Rule: "email", Value: "[REDACTED]"},
+		{Field: "name", Rule: "required"},
+		{Field: "age", Rule: "min", Value: "[REDACTED]"},
+		{Message: "body is not JSON"},
+	})
+
+	hook.Reset()
+	ValidationErrors(ctx, nil)
+	assert.Assert(t, hook.LastEntry() == nil)
+}
@@ -0,0 +1,35 @@
+package glogger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+// dumpRequest returns the lines of the request line and headers, as sent on the wire.
+func dumpRequest(r *http.Request) []string {
+	dump, err := httputil.DumpRequest(r, false)
+
+	if err != nil {
+		return nil
+	}
+
+	return dumpLines(dump)
+}
+
+// dumpResponse returns the lines of the status line and headers of the response, as
+// sent on the wire.
+func dumpResponse(r *http.Request, statusCode int, header http.Header) []string {
+	var dump bytes.Buffer
+
+	fmt.Fprintf(&dump, "%s %03d %s\r\n", r.Proto, statusCode, http.StatusText(statusCode))
+	header.Write(&dump)
+
+	return dumpLines(dump.Bytes())
+}
+
+func dumpLines(dump []byte) []string {
+	return strings.Split(strings.TrimRight(string(dump), "\r\n"), "\r\n")
+}